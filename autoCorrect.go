@@ -1,14 +1,27 @@
 package bingSpellCheck
 
-import "bytes"
+import (
+	"bytes"
+	"fmt"
+)
 
 // BuildAutoCorrectedText updates text to reflect the corrections in response
+//
+//  Notes
+//    Every flagged token of response is corrected; remove the tokens that
+//    should be kept first, or use lint.AutoCorrect, which honors inline
+//    bingspell: directives and the project config
+//
 func BuildAutoCorrectedText(text string, response *SpellCheckResponse) (result string, err error) {
 	// guard against a panic
 	defer func() {
 		if r := recover(); r != nil {
 			result = ""
-			err = r.(Error)
+			if e, ok := r.(Error); ok {
+				err = e
+			} else {
+				err = fmt.Errorf("bingSpellCheck: cannot correct text: %v", r)
+			}
 		}
 	}()
 
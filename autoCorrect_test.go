@@ -0,0 +1,68 @@
+package bingSpellCheck
+
+import "testing"
+
+func TestBuildAutoCorrectedText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		scr     *SpellCheckResponse
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "nothing flagged",
+			text: "all good",
+			scr:  spellCheckResponse(),
+			want: "all good",
+		},
+		{
+			name: "unknown tokens",
+			text: "teh cat is hapy",
+			scr:  spellCheckResponse(unknown(0, "teh", "the"), unknown(11, "hapy", "happy", "hippy")),
+			want: "the cat is happy",
+		},
+		{
+			name: "repeated token",
+			text: "the the cat",
+			scr:  spellCheckResponse(FlaggedToken{Offset: 4, Token: "the", Type: RepeatedTokenType}),
+			want: "the cat",
+		},
+		{
+			name: "unsupported type",
+			text: "a b c",
+			scr:  spellCheckResponse(FlaggedToken{Offset: 2, Token: "b", Type: "Other"}),
+			want: "a  c",
+		},
+		{
+			name:    "error response",
+			text:    "text",
+			scr:     errorResponse(InvalidRequestErrorCode),
+			wantErr: true,
+		},
+		{
+			name:    "overlapping tokens",
+			text:    "recieve it",
+			scr:     spellCheckResponse(unknown(0, "recieve it", "receive it"), unknown(0, "recieve", "receive")),
+			wantErr: true,
+		},
+		{
+			name:    "unknown token without suggestions",
+			text:    "teh",
+			scr:     spellCheckResponse(unknown(0, "teh")),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildAutoCorrectedText(tt.text, tt.scr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
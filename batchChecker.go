@@ -0,0 +1,89 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult is the result of checking one text of a batch
+type BatchResult struct {
+	Response *SpellCheckResponse
+	Err      error
+}
+
+// BatchChecker checks batches of texts concurrently using another Checker
+type BatchChecker struct {
+	next        Checker
+	concurrency int
+	tracer      Tracer
+}
+
+// NewBatchChecker creates a BatchChecker that makes up to concurrency
+// checks at a time using next
+//
+//  Notes
+//    Combine with RateLimitedChecker to stay within the request rate of
+//    the subscription
+//
+func NewBatchChecker(next Checker, concurrency int) *BatchChecker {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &BatchChecker{next: next, concurrency: concurrency}
+}
+
+// WithTracer creates a SpanBatch span for each batch
+func (bc *BatchChecker) WithTracer(tracer Tracer) *BatchChecker {
+	bc.tracer = tracer
+	return bc
+}
+
+// CheckBatch checks each text with opts and returns the results in the
+// order of texts
+//
+//  Notes
+//    Texts that have not been checked when ctx is done fail with ctx.Err()
+//
+func (bc *BatchChecker) CheckBatch(ctx context.Context, texts []string, opts *CheckOptions) []BatchResult {
+	attrs := []Attribute{{BatchSizeAttribute, len(texts)}}
+	if opts != nil {
+		attrs = append(attrs, Attribute{MarketAttribute, string(opts.Market)}, Attribute{TagAttribute, opts.Tag})
+	}
+
+	ctx, span := startSpan(ctx, bc.tracer, SpanBatch, attrs...)
+	defer span.End()
+
+	results := make([]BatchResult, len(texts))
+	sem := make(chan struct{}, bc.concurrency)
+
+	var wg sync.WaitGroup
+	for i, text := range texts {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, text string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Response, results[i].Err = bc.next.Check(ctx, text, opts)
+		}(i, text)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil || result.Response.IsErrorResponse() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		span.SetAttributes(Attribute{BatchFailedAttribute, failed})
+	}
+
+	return results
+}
@@ -0,0 +1,357 @@
+package bingtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// Mode determines whether a Recorder records or replays interactions
+type Mode int
+
+const (
+	// ModeReplay serves responses from the golden file and fails any request
+	// that was not recorded
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests to the real service and writes each
+	// request/response pair to the golden file
+	ModeRecord
+)
+
+// RecordModeEnv is the environment variable checked by ModeFromEnv
+const RecordModeEnv = "BINGTEST_RECORD"
+
+// RedactedValue replaces the subscription key, and the headers that
+// identify or locate the user, in recorded interactions
+const RedactedValue = "REDACTED"
+
+// redactedHeaders are never written to golden files
+var redactedHeaders = []string{
+	bingSpellCheck.SubscriptionKeyHeader,
+	bingSpellCheck.ClientIDHeader,
+	bingSpellCheck.ClientIPHeader,
+	bingSpellCheck.SearchLocationHeader,
+}
+
+// ModeFromEnv returns ModeRecord if the RecordModeEnv environment variable is
+// set to a non-empty value other than "0" or "false", otherwise ModeReplay
+func ModeFromEnv() Mode {
+	switch strings.ToLower(os.Getenv(RecordModeEnv)) {
+	case "", "0", "false":
+		return ModeReplay
+	default:
+		return ModeRecord
+	}
+}
+
+// RecordedResponse is the stored form of an HTTP response
+type RecordedResponse struct {
+	StatusCode int             `json:"statusCode"`
+	Header     http.Header     `json:"header"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// Interaction is a recorded request/response pair
+//
+//  Notes
+//    Key is the normalized form of the request used for matching (see
+//    MatchKey) and is stored so golden files are easy to review
+//
+type Interaction struct {
+	Key      string           `json:"key"`
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// UnrecordedRequestError is returned by a replaying Recorder when a request
+// has no recorded interaction
+type UnrecordedRequestError struct {
+	Path string
+	Key  string
+}
+
+func (err *UnrecordedRequestError) Error() string {
+	return fmt.Sprintf("bingtest: no recorded interaction in %s for request %q; re-record with %s=1",
+		err.Path, err.Key, RecordModeEnv)
+}
+
+// TB is the subset of testing.TB a Recorder reports failures to, so the
+// package does not link the testing package into binaries that use it
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Recorder is an http.RoundTripper that records Bing Spell Check API
+// interactions to a golden file, or replays them from it
+//
+//  Notes
+//    Requests are matched on path plus normalized query (GET) or form (POST)
+//    parameters, so a request recorded as a GET replays for an equivalent
+//    POST and vice versa. When the same request is made more than once the
+//    recorded responses are returned in order, with the last one repeated.
+//
+//    The subscription key and the client ID, IP address and location
+//    headers of requests and responses are recorded as RedactedValue.
+//
+type Recorder struct {
+	// Transport sends requests in ModeRecord (http.DefaultTransport if nil)
+	Transport http.RoundTripper
+
+	path string
+	mode Mode
+	tb   TB
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     map[string]int
+}
+
+// NewRecorder creates a Recorder for the golden file at path
+//
+//  Notes
+//    In ModeReplay the golden file must exist. In ModeRecord any existing
+//    golden file is replaced.
+//
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	rec := &Recorder{
+		path:     path,
+		mode:     mode,
+		replayed: map[string]int{},
+	}
+
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &rec.interactions); err != nil {
+			return nil, fmt.Errorf("bingtest: %s: %v", path, err)
+		}
+	}
+
+	return rec, nil
+}
+
+// WithT reports unrecorded requests as test failures on tb (usually a
+// *testing.T), in addition to returning an error from RoundTrip
+//
+//  Notes
+//    Failures are reported with Errorf rather than Fatalf, since RoundTrip
+//    may run on a goroutine other than the test's
+//
+func (rec *Recorder) WithT(tb TB) *Recorder {
+	rec.tb = tb
+	return rec
+}
+
+// Mode returns the mode of the recorder
+func (rec *Recorder) Mode() Mode {
+	return rec.mode
+}
+
+// Client returns an *http.Client that uses the recorder as its transport
+func (rec *Recorder) Client() *http.Client {
+	return &http.Client{Transport: rec}
+}
+
+// Interactions returns a copy of the recorded interactions
+func (rec *Recorder) Interactions() []Interaction {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return append([]Interaction(nil), rec.interactions...)
+}
+
+// RoundTrip implements http.RoundTripper
+func (rec *Recorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	params, body, err := requestParams(r)
+	if err != nil {
+		return nil, err
+	}
+	key := MatchKey(r.URL.Path, params)
+
+	if rec.mode == ModeReplay {
+		return rec.replay(r, key)
+	}
+
+	// the body was consumed while reading params
+	if body != nil {
+		r = r.Clone(r.Context())
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	transport := rec.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Key: key,
+		Request: RecordedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Header: redacted(r.Header),
+			Params: params,
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redacted(resp.Header),
+			Body:       storedBody(respBody),
+		},
+	}
+
+	rec.mu.Lock()
+	rec.interactions = append(rec.interactions, interaction)
+	err = rec.save()
+	rec.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return interaction.Response.toHTTP(r), nil
+}
+
+func (rec *Recorder) replay(r *http.Request, key string) (*http.Response, error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	var matches []int
+	for i := range rec.interactions {
+		if rec.interactions[i].Key == key {
+			matches = append(matches, i)
+		}
+	}
+
+	if len(matches) == 0 {
+		err := &UnrecordedRequestError{Path: rec.path, Key: key}
+		if rec.tb != nil {
+			rec.tb.Helper()
+			rec.tb.Errorf("%v", err)
+		}
+		return nil, err
+	}
+
+	n := rec.replayed[key]
+	rec.replayed[key] = n + 1
+	if n >= len(matches) {
+		n = len(matches) - 1
+	}
+
+	return rec.interactions[matches[n]].Response.toHTTP(r), nil
+}
+
+// save writes the golden file, and must be called with mu held
+func (rec *Recorder) save() error {
+	data, err := json.MarshalIndent(rec.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(rec.path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(rec.path, append(data, '\n'), 0644)
+}
+
+// redacted returns a copy of header with the redactedHeaders replaced by
+// RedactedValue
+func redacted(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, RedactedValue)
+		}
+	}
+
+	return header
+}
+
+// MatchKey returns the normalized form of a request used to match recorded
+// interactions
+func MatchKey(path string, params url.Values) string {
+	// Encode sorts by key
+	return path + "?" + params.Encode()
+}
+
+// requestParams returns the query or form parameters of r, along with the
+// body bytes if the body was read
+func requestParams(r *http.Request) (url.Values, []byte, error) {
+	params := r.URL.Query()
+	if r.Body == nil {
+		return params, nil, nil
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/x-www-form-urlencoded" {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, nil, err
+		}
+		for param, values := range form {
+			params[param] = append(params[param], values...)
+		}
+	}
+
+	return params, body, nil
+}
+
+// storedBody keeps JSON bodies readable in golden files and quotes anything else
+func storedBody(body []byte) json.RawMessage {
+	if json.Valid(body) {
+		return json.RawMessage(body)
+	}
+
+	quoted, _ := json.Marshal(string(body))
+	return json.RawMessage(quoted)
+}
+
+func (resp RecordedResponse) toHTTP(r *http.Request) *http.Response {
+	body := []byte(resp.Body)
+
+	var text string
+	if json.Unmarshal(body, &text) == nil {
+		body = []byte(text)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        resp.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+}
@@ -0,0 +1,233 @@
+package bingtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gotomgo/bingSpellCheck"
+	"github.com/gotomgo/bingSpellCheck/bingtest"
+)
+
+const (
+	recordedClientID = "recorded-client-id"
+	recordedClientIP = "203.0.113.7"
+	recordedLocation = "lat:47.6;long:-122.3;re:22"
+)
+
+// newRecordingClient returns a client that sends requests for srv through
+// rec, with the headers that identify and locate the user
+func newRecordingClient(srv *bingtest.Server, rec *bingtest.Recorder) *bingSpellCheck.Client {
+	client := bingSpellCheck.NewClient(testKey).WithEndpoint(srv.URL()).WithHTTPClient(rec.Client())
+	client.Headers.WithClientID(recordedClientID)
+	client.Headers.SetHeader(bingSpellCheck.ClientIPHeader, recordedClientIP)
+	client.Headers.SetHeader(bingSpellCheck.SearchLocationHeader, recordedLocation)
+
+	return client
+}
+
+func TestRecorderRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "check.json")
+	texts := []string{"Is teh data good?", "Nothing to see", strings.Repeat("teh ", 500)}
+
+	srv := bingtest.NewServer(testKey).WithWord("teh", "the")
+
+	rec, err := bingtest.NewRecorder(path, bingtest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var recorded [][]flagged
+	client := newRecordingClient(srv, rec)
+	for _, text := range texts {
+		scr, err := client.Check(context.Background(), text, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorded = append(recorded, flaggedTokens(scr))
+	}
+
+	if got := len(rec.Interactions()); got != len(texts) {
+		t.Fatalf("recorded %d interactions, want %d", got, len(texts))
+	}
+	if got := len(srv.Requests()); got != len(texts) {
+		t.Fatalf("the server received %d requests, want %d", got, len(texts))
+	}
+
+	// the golden file is all that is needed to replay
+	srv.Close()
+
+	rec, err = bingtest.NewRecorder(path, bingtest.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client = newRecordingClient(srv, rec.WithT(t))
+	for i, text := range texts {
+		scr, err := client.Check(context.Background(), text, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := flaggedTokens(scr); !reflect.DeepEqual(got, recorded[i]) {
+			t.Errorf("text %d replayed %v, recorded %v", i, got, recorded[i])
+		}
+	}
+}
+
+func TestRecorderRedaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "check.json")
+
+	srv := bingtest.NewServer(testKey)
+	defer srv.Close()
+
+	rec, err := bingtest.NewRecorder(path, bingtest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scr, err := newRecordingClient(srv, rec).Check(context.Background(), "text", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scr.IsErrorResponse() {
+		t.Fatalf("got %+v; the key must be sent unredacted", scr.Errors)
+	}
+
+	golden, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{testKey, recordedClientID, recordedClientIP, recordedLocation} {
+		if strings.Contains(string(golden), secret) {
+			t.Errorf("the golden file contains %q:\n%s", secret, golden)
+		}
+	}
+
+	interaction := rec.Interactions()[0]
+	for _, header := range []string{
+		bingSpellCheck.SubscriptionKeyHeader,
+		bingSpellCheck.ClientIDHeader,
+		bingSpellCheck.ClientIPHeader,
+		bingSpellCheck.SearchLocationHeader,
+	} {
+		if got := interaction.Request.Header.Get(header); got != bingtest.RedactedValue {
+			t.Errorf("request %s = %q, want it redacted", header, got)
+		}
+	}
+	if got := interaction.Response.Header.Get(bingSpellCheck.ClientIDHeader); got != bingtest.RedactedValue {
+		t.Errorf("response %s = %q, want it redacted", bingSpellCheck.ClientIDHeader, got)
+	}
+	if got := interaction.Response.Header.Get(bingSpellCheck.TraceIDHeader); got == "" || got == bingtest.RedactedValue {
+		t.Errorf("response %s = %q, want it recorded", bingSpellCheck.TraceIDHeader, got)
+	}
+}
+
+func TestRecorderReplayMatching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "check.json")
+
+	srv := bingtest.NewServer(testKey).
+		FailNext(bingtest.Fault{Status: http.StatusServiceUnavailable}, 1)
+
+	rec, err := bingtest.NewRecorder(path, bingtest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the same request twice: a fault, then a response
+	query := url.Values{bingSpellCheck.TextParam: {"text"}, bingSpellCheck.MarketParam: {"en-US"}}
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL()+"?"+query.Encode(), nil)
+		req.Header.Set(bingSpellCheck.SubscriptionKeyHeader, testKey)
+		resp, err := rec.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	srv.Close()
+
+	rec, err = bingtest.NewRecorder(path, bingtest.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// replayed as a POST with the parameters in another order
+	form := "mkt=en-US&text=text"
+	tests := []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK}
+	for i, want := range tests {
+		resp, err := rec.Client().Post(srv.URL(), "application/x-www-form-urlencoded", strings.NewReader(form))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != want {
+			t.Errorf("replay %d: status = %d, want %d", i, resp.StatusCode, want)
+		}
+	}
+}
+
+// fakeTB records the failures reported by a Recorder
+type fakeTB struct {
+	errors []string
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...interface{}) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecorderUnrecordedRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "check.json")
+	if err := ioutil.WriteFile(path, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := bingtest.NewRecorder(path, bingtest.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tb := &fakeTB{}
+	_, err = rec.WithT(tb).Client().Get("http://localhost/bing/v7.0/spellcheck?text=text")
+
+	var unrecorded *bingtest.UnrecordedRequestError
+	if !errors.As(err, &unrecorded) || unrecorded.Key != "/bing/v7.0/spellcheck?text=text" {
+		t.Errorf("got %v, want an UnrecordedRequestError", err)
+	}
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], bingtest.RecordModeEnv) {
+		t.Errorf("reported %q", tb.errors)
+	}
+
+	if _, err := bingtest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), bingtest.ModeReplay); err == nil {
+		t.Error("replaying a missing golden file did not fail")
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bingtest.Mode
+	}{
+		{"", bingtest.ModeReplay},
+		{"0", bingtest.ModeReplay},
+		{"FALSE", bingtest.ModeReplay},
+		{"1", bingtest.ModeRecord},
+		{"true", bingtest.ModeRecord},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(bingtest.RecordModeEnv, tt.value)
+		if got := bingtest.ModeFromEnv(); got != tt.want {
+			t.Errorf("%s=%q: got %v, want %v", bingtest.RecordModeEnv, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+// Package bingtest provides an in-process fake of the Bing Spell Check API
+// for use in tests that cannot (or should not) reach the real service.
+package bingtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// Fault is an injected failure returned instead of a normal response
+//
+//  Fields
+//    Status     - The HTTP status code to return (e.g. 429, 500, 503)
+//    Code       - The Bing error code (defaults based on Status)
+//    SubCode    - The Bing error sub code (optional)
+//    RetryAfter - Value of the Retry-After header, for 429s (optional)
+//
+type Fault struct {
+	Status     int
+	Code       string
+	SubCode    string
+	RetryAfter time.Duration
+}
+
+// RecordedRequest is a request received by the Server
+//
+//  Notes
+//    Params holds the query parameters for GET requests and the form values
+//    for POST requests, so assertions do not depend on the method used
+//
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Header http.Header `json:"header"`
+	Params url.Values  `json:"params"`
+}
+
+// Server is a fake Bing Spell Check API server
+//
+//  Notes
+//    A Server only flags words it has been told about via WithWord (and
+//    repeated words when WithRepeatedDetection is enabled). All methods are
+//    safe to call while requests are in flight.
+//
+//    Like Bing, every response has a BingAPIs-TraceId header, and an
+//    X-MSEdge-ClientID header that echoes the request's client ID or
+//    assigns a new one. Responses are JSON-LD when the Accept header is
+//    application/ld+json.
+//
+type Server struct {
+	// SubscriptionKey is the key the server requires in the
+	// Ocp-Apim-Subscription-Key header
+	SubscriptionKey string
+
+	server   *http.Server
+	listener net.Listener
+	client   *http.Client
+
+	mu             sync.Mutex
+	words          map[string][]string
+	detectRepeated bool
+	latency        time.Duration
+	faults         []Fault
+	requests       []RecordedRequest
+	lastID         uint64
+}
+
+// NewServer starts a fake Bing Spell Check server that requires
+// subscriptionKey
+//
+//  Notes
+//    Call Close when done with the server
+//
+func NewServer(subscriptionKey string) *Server {
+	srv := &Server{
+		SubscriptionKey: subscriptionKey,
+		words:           map[string][]string{},
+	}
+
+	// like httptest.NewServer, without linking the testing package into
+	// binaries that use the fake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("bingtest: failed to listen: %v", err))
+	}
+
+	srv.listener = listener
+	srv.server = &http.Server{Handler: srv}
+	srv.client = &http.Client{Transport: &http.Transport{}}
+	go srv.server.Serve(listener)
+
+	return srv
+}
+
+// Close shuts down the server
+func (srv *Server) Close() {
+	srv.server.Close()
+	srv.client.CloseIdleConnections()
+}
+
+// URL returns the spell check URL of the server
+func (srv *Server) URL() string {
+	return "http://" + srv.listener.Addr().String() + bingSpellCheck.BingSpellCheckPath
+}
+
+// NewClient returns a *bingSpellCheck.Client that sends requests to the
+// server using the server's subscription key
+func (srv *Server) NewClient() *bingSpellCheck.Client {
+	return bingSpellCheck.NewClient(srv.SubscriptionKey).
+		WithEndpoint(srv.URL()).
+		WithHTTPClient(srv.client)
+}
+
+// WithWord flags word (case insensitive) as an UnknownToken with the given
+// suggestions, in decreasing order of preference
+func (srv *Server) WithWord(word string, suggestions ...string) *Server {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.words[strings.ToLower(word)] = suggestions
+	return srv
+}
+
+// WithRepeatedDetection enables or disables flagging of repeated words
+// (e.g. "the the") as RepeatedToken
+func (srv *Server) WithRepeatedDetection(enabled bool) *Server {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.detectRepeated = enabled
+	return srv
+}
+
+// WithLatency delays every response by latency
+func (srv *Server) WithLatency(latency time.Duration) *Server {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.latency = latency
+	return srv
+}
+
+// FailNext queues fault to be returned for the next count requests
+//
+//  Notes
+//    Faults are consumed in the order they are queued, and are returned
+//    after the request is recorded but before it is validated
+//
+func (srv *Server) FailNext(fault Fault, count int) *Server {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	for i := 0; i < count; i++ {
+		srv.faults = append(srv.faults, fault)
+	}
+	return srv
+}
+
+// Requests returns a copy of the requests received so far
+func (srv *Server) Requests() []RecordedRequest {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	return append([]RecordedRequest(nil), srv.requests...)
+}
+
+// Reset clears recorded requests and pending faults
+func (srv *Server) Reset() {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.requests = nil
+	srv.faults = nil
+}
+
+// ServeHTTP implements http.Handler
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params, perr := readParams(r)
+
+	srv.mu.Lock()
+	srv.requests = append(srv.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Header: r.Header.Clone(),
+		Params: params,
+	})
+	latency := srv.latency
+	srv.lastID++
+	traceID := fmt.Sprintf("%032X", srv.lastID)
+	clientID := r.Header.Get(bingSpellCheck.ClientIDHeader)
+	if clientID == "" {
+		srv.lastID++
+		clientID = fmt.Sprintf("%032X", srv.lastID)
+	}
+	var fault *Fault
+	if len(srv.faults) > 0 {
+		fault = &srv.faults[0]
+		srv.faults = srv.faults[1:]
+	}
+	srv.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	w.Header().Set(bingSpellCheck.TraceIDHeader, traceID)
+	w.Header().Set(bingSpellCheck.ClientIDHeader, clientID)
+
+	if fault != nil {
+		writeFault(w, r, *fault)
+		return
+	}
+
+	if r.URL.Path != bingSpellCheck.BingSpellCheckPath {
+		writeError(w, r, http.StatusNotFound, bingSpellCheck.Error{
+			Code:    bingSpellCheck.InvalidRequestErrorCode,
+			SubCode: bingSpellCheck.ResourceErrorSubCode,
+			Message: "Resource not found",
+		})
+		return
+	}
+
+	if status, bingErr := srv.validate(r, params, perr); bingErr != nil {
+		writeError(w, r, status, *bingErr)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, srv.check(params))
+}
+
+// validate applies the same rules the Bing service applies to a request
+func (srv *Server) validate(r *http.Request, params url.Values, perr error) (int, *bingSpellCheck.Error) {
+	key := r.Header.Get(bingSpellCheck.SubscriptionKeyHeader)
+	if key == "" {
+		return http.StatusUnauthorized, &bingSpellCheck.Error{
+			Code:    bingSpellCheck.InvalidAuthorizationErrorCode,
+			SubCode: bingSpellCheck.AuthorizationMissingSubCode,
+			Message: "Subscription key is missing",
+		}
+	}
+	if key != srv.SubscriptionKey {
+		return http.StatusUnauthorized, &bingSpellCheck.Error{
+			Code:    bingSpellCheck.InvalidAuthorizationErrorCode,
+			SubCode: bingSpellCheck.AuthorizationDisabledSubCode,
+			Message: "Subscription key is not valid",
+		}
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		return http.StatusMethodNotAllowed, &bingSpellCheck.Error{
+			Code:    bingSpellCheck.InvalidRequestErrorCode,
+			SubCode: bingSpellCheck.HTTPNotAllowedSubCode,
+			Message: fmt.Sprintf("HTTP method %s is not allowed", r.Method),
+		}
+	}
+
+	if perr != nil {
+		return http.StatusBadRequest, &bingSpellCheck.Error{
+			Code:    bingSpellCheck.InvalidRequestErrorCode,
+			SubCode: bingSpellCheck.ParameterInvalidValueSubCode,
+			Message: perr.Error(),
+		}
+	}
+
+	text := params.Get(bingSpellCheck.TextParam)
+	if text == "" {
+		return http.StatusBadRequest, &bingSpellCheck.Error{
+			Code:      bingSpellCheck.InvalidRequestErrorCode,
+			SubCode:   bingSpellCheck.ParameterMissingSubCode,
+			Message:   "Required parameter is missing",
+			Parameter: bingSpellCheck.TextParam,
+		}
+	}
+
+	length := utf8.RuneCountInString(text) +
+		utf8.RuneCountInString(params.Get(bingSpellCheck.PreContextTextParam)) +
+		utf8.RuneCountInString(params.Get(bingSpellCheck.PostContextTextParam))
+
+	limit := bingSpellCheck.MaxPostTextLength
+	if r.Method == http.MethodGet {
+		limit = bingSpellCheck.MaxGetTextLength
+	}
+	if length > limit {
+		return http.StatusBadRequest, &bingSpellCheck.Error{
+			Code:      bingSpellCheck.InvalidRequestErrorCode,
+			SubCode:   bingSpellCheck.ParameterInvalidValueSubCode,
+			Message:   fmt.Sprintf("Text length %d exceeds the %s limit of %d", length, r.Method, limit),
+			Parameter: bingSpellCheck.TextParam,
+		}
+	}
+
+	if mode := params.Get(bingSpellCheck.ModeParam); mode != "" &&
+		mode != bingSpellCheck.ProofMode && mode != bingSpellCheck.SpellMode {
+		return http.StatusBadRequest, &bingSpellCheck.Error{
+			Code:      bingSpellCheck.InvalidRequestErrorCode,
+			SubCode:   bingSpellCheck.ParameterInvalidValueSubCode,
+			Message:   "Parameter has invalid value",
+			Parameter: bingSpellCheck.ModeParam,
+			Value:     mode,
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// check builds the response for the text in params
+func (srv *Server) check(params url.Values) bingSpellCheck.SpellCheckResponse {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	spellMode := params.Get(bingSpellCheck.ModeParam) == bingSpellCheck.SpellMode
+
+	response := bingSpellCheck.SpellCheckResponse{
+		Type:          bingSpellCheck.SpellCheckResponseType,
+		FlaggedTokens: []bingSpellCheck.FlaggedToken{},
+	}
+
+	var previous *word
+	for _, w := range splitWords(params.Get(bingSpellCheck.TextParam)) {
+		w := w
+		lower := strings.ToLower(w.text)
+
+		repeated := srv.detectRepeated && previous != nil && strings.ToLower(previous.text) == lower
+		previous = &w
+
+		if repeated {
+			response.FlaggedTokens = append(response.FlaggedTokens, bingSpellCheck.FlaggedToken{
+				Offset:      w.offset,
+				Token:       w.text,
+				Type:        bingSpellCheck.RepeatedTokenType,
+				Suggestions: []bingSpellCheck.TokenSuggestion{{Score: 1}},
+			})
+			continue
+		}
+
+		suggestions, ok := srv.words[lower]
+		if !ok {
+			continue
+		}
+
+		token := bingSpellCheck.FlaggedToken{
+			Offset: w.offset,
+			Token:  w.text,
+			Type:   bingSpellCheck.UnknownTokenType,
+		}
+		for i, suggestion := range suggestions {
+			score := 1.0
+			if !spellMode {
+				score = 1.0 / float64(i+1)
+			}
+			token.Suggestions = append(token.Suggestions, bingSpellCheck.TokenSuggestion{
+				Score:      score,
+				Suggestion: suggestion,
+			})
+		}
+		response.FlaggedTokens = append(response.FlaggedTokens, token)
+	}
+
+	return response
+}
+
+type word struct {
+	text   string
+	offset int
+}
+
+// splitWords returns the words in text along with their byte offsets
+func splitWords(text string) []word {
+	var words []word
+
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\''
+		if inWord && start < 0 {
+			start = i
+		} else if !inWord && start >= 0 {
+			words = append(words, word{text: text[start:i], offset: start})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{text: text[start:], offset: start})
+	}
+
+	return words
+}
+
+// readParams returns the query parameters of a GET or the form values of a
+// POST
+func readParams(r *http.Request) (url.Values, error) {
+	if r.Method != http.MethodPost {
+		return r.URL.Query(), nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/x-www-form-urlencoded" {
+		return url.Values{}, fmt.Errorf("POST requires Content-Type application/x-www-form-urlencoded, got %q", mediaType)
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return url.Values{}, err
+	}
+
+	return url.ParseQuery(string(body))
+}
+
+func writeFault(w http.ResponseWriter, r *http.Request, fault Fault) {
+	code := fault.Code
+	if code == "" {
+		switch {
+		case fault.Status == http.StatusTooManyRequests:
+			code = bingSpellCheck.RateLimitExceededErrorCode
+		case fault.Status == http.StatusUnauthorized:
+			code = bingSpellCheck.InvalidAuthorizationErrorCode
+		case fault.Status == http.StatusForbidden:
+			code = bingSpellCheck.InsufficientAuthorizationErrorCode
+		case fault.Status >= 500:
+			code = bingSpellCheck.ServerErrorCode
+		default:
+			code = bingSpellCheck.InvalidRequestErrorCode
+		}
+	}
+
+	if fault.RetryAfter > 0 {
+		w.Header().Set(bingSpellCheck.RetryAfterHeader, strconv.Itoa(int(fault.RetryAfter/time.Second)))
+	}
+
+	writeError(w, r, fault.Status, bingSpellCheck.Error{
+		Code:    code,
+		SubCode: fault.SubCode,
+		Message: http.StatusText(fault.Status),
+	})
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, bingErr bingSpellCheck.Error) {
+	writeJSON(w, r, status, bingSpellCheck.SpellCheckResponse{
+		Type:   bingSpellCheck.ErrorResponseType,
+		Errors: []bingSpellCheck.Error{bingErr},
+	})
+}
+
+// writeJSON writes scr as JSON, or as JSON-LD if the request accepts it
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, scr bingSpellCheck.SpellCheckResponse) {
+	var v interface{} = scr
+	contentType := bingSpellCheck.AcceptApplicationJSON
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get(bingSpellCheck.AcceptHeader)); mediaType == bingSpellCheck.AcceptApplicationLinkedJSON {
+		v = bingSpellCheck.NewLinkedSpellCheckResponse(&scr)
+		contentType = bingSpellCheck.AcceptApplicationLinkedJSON
+	}
+
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
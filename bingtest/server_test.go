@@ -0,0 +1,244 @@
+package bingtest_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/gotomgo/bingSpellCheck"
+	"github.com/gotomgo/bingSpellCheck/bingtest"
+)
+
+const testKey = "test-key"
+
+// flagged is the part of a FlaggedToken the tests compare
+type flagged struct {
+	Offset     int
+	Token      string
+	Type       string
+	Suggestion string
+}
+
+func flaggedTokens(scr *bingSpellCheck.SpellCheckResponse) []flagged {
+	tokens := []flagged{}
+	for _, token := range scr.FlaggedTokens {
+		f := flagged{Offset: token.Offset, Token: token.Token, Type: token.Type}
+		if len(token.Suggestions) > 0 {
+			f.Suggestion = token.Suggestions[0].Suggestion
+		}
+		tokens = append(tokens, f)
+	}
+
+	return tokens
+}
+
+func TestServerCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		words    map[string][]string
+		repeated bool
+		want     []flagged
+	}{
+		{
+			name: "no words",
+			text: "Is teh data good?",
+			want: []flagged{},
+		},
+		{
+			name:  "unknown word",
+			text:  "Is teh data good?",
+			words: map[string][]string{"teh": {"the", "ten"}},
+			want:  []flagged{{3, "teh", bingSpellCheck.UnknownTokenType, "the"}},
+		},
+		{
+			name:  "case insensitive",
+			text:  "Teh end, teh start",
+			words: map[string][]string{"TEH": {"the"}},
+			want: []flagged{
+				{0, "Teh", bingSpellCheck.UnknownTokenType, "the"},
+				{9, "teh", bingSpellCheck.UnknownTokenType, "the"},
+			},
+		},
+		{
+			name:  "byte offsets",
+			text:  "café teh",
+			words: map[string][]string{"teh": {"the"}},
+			want:  []flagged{{6, "teh", bingSpellCheck.UnknownTokenType, "the"}},
+		},
+		{
+			name: "repeated words ignored",
+			text: "the the end",
+			want: []flagged{},
+		},
+		{
+			name:     "repeated words",
+			text:     "the The end",
+			repeated: true,
+			want:     []flagged{{4, "The", bingSpellCheck.RepeatedTokenType, ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := bingtest.NewServer(testKey).WithRepeatedDetection(tt.repeated)
+			defer srv.Close()
+			for word, suggestions := range tt.words {
+				srv.WithWord(word, suggestions...)
+			}
+
+			scr, err := srv.NewClient().Check(context.Background(), tt.text, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !scr.IsSpellCheckResponse() {
+				t.Fatalf("got %s response: %v", scr.Type, scr.Errors)
+			}
+			if got := flaggedTokens(scr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		params      url.Values
+		wantStatus  int
+		wantCode    string
+		wantSubCode string
+	}{
+		{
+			name:        "missing key",
+			params:      url.Values{"text": {"hello"}},
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    bingSpellCheck.InvalidAuthorizationErrorCode,
+			wantSubCode: bingSpellCheck.AuthorizationMissingSubCode,
+		},
+		{
+			name:        "wrong key",
+			key:         "other-key",
+			params:      url.Values{"text": {"hello"}},
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    bingSpellCheck.InvalidAuthorizationErrorCode,
+			wantSubCode: bingSpellCheck.AuthorizationDisabledSubCode,
+		},
+		{
+			name:        "missing text",
+			key:         testKey,
+			params:      url.Values{},
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    bingSpellCheck.InvalidRequestErrorCode,
+			wantSubCode: bingSpellCheck.ParameterMissingSubCode,
+		},
+		{
+			name:        "invalid mode",
+			key:         testKey,
+			params:      url.Values{"text": {"hello"}, "mode": {"grammar"}},
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    bingSpellCheck.InvalidRequestErrorCode,
+			wantSubCode: bingSpellCheck.ParameterInvalidValueSubCode,
+		},
+	}
+
+	srv := bingtest.NewServer(testKey)
+	defer srv.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := bingSpellCheck.NewSpellCheckHeaders(tt.key)
+			params := &bingSpellCheck.SpellCheckParams{Values: tt.params}
+
+			scr, err := bingSpellCheck.SpellCheckContext(context.Background(), http.DefaultClient, srv.URL(), params, headers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !scr.IsErrorResponse() || len(scr.Errors) != 1 {
+				t.Fatalf("got %+v, want one error", scr)
+			}
+			if scr.Meta.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", scr.Meta.StatusCode, tt.wantStatus)
+			}
+			if got := scr.Errors[0]; got.Code != tt.wantCode || got.SubCode != tt.wantSubCode {
+				t.Errorf("error = %s/%s, want %s/%s", got.Code, got.SubCode, tt.wantCode, tt.wantSubCode)
+			}
+		})
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	srv := bingtest.NewServer(testKey).
+		FailNext(bingtest.Fault{Status: http.StatusTooManyRequests}, 1).
+		FailNext(bingtest.Fault{Status: http.StatusServiceUnavailable}, 1)
+	defer srv.Close()
+
+	client := srv.NewClient()
+
+	tests := []struct {
+		wantStatus      int
+		wantUnavailable bool
+	}{
+		{http.StatusTooManyRequests, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusOK, false},
+	}
+
+	for i, tt := range tests {
+		scr, err := client.Check(context.Background(), "hello", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if scr.Meta.StatusCode != tt.wantStatus || scr.IsUnavailable() != tt.wantUnavailable {
+			t.Errorf("request %d: status %d, unavailable %v; want %d, %v",
+				i+1, scr.Meta.StatusCode, scr.IsUnavailable(), tt.wantStatus, tt.wantUnavailable)
+		}
+	}
+
+	if got := len(srv.Requests()); got != len(tests) {
+		t.Errorf("recorded %d requests, want %d", got, len(tests))
+	}
+}
+
+func TestServerRecordsRequests(t *testing.T) {
+	srv := bingtest.NewServer(testKey)
+	defer srv.Close()
+
+	client := srv.NewClient()
+	client.Params.WithMarket("en-GB").WithSpellMode()
+
+	long := make([]byte, bingSpellCheck.MaxGetTextLength+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+
+	tests := []struct {
+		text       string
+		wantMethod string
+	}{
+		{"hello", http.MethodGet},
+		{string(long), http.MethodPost},
+	}
+
+	for _, tt := range tests {
+		srv.Reset()
+		if _, err := client.Check(context.Background(), tt.text, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		requests := srv.Requests()
+		if len(requests) != 1 {
+			t.Fatalf("recorded %d requests, want 1", len(requests))
+		}
+		req := requests[0]
+		if req.Method != tt.wantMethod {
+			t.Errorf("method = %s, want %s", req.Method, tt.wantMethod)
+		}
+		if req.Params.Get(bingSpellCheck.TextParam) != tt.text || req.Params.Get(bingSpellCheck.MarketParam) != "en-GB" {
+			t.Errorf("params = %v", req.Params)
+		}
+	}
+}
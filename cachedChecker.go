@@ -0,0 +1,138 @@
+package bingSpellCheck
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// CachedChecker is a Checker that caches successful responses of another
+// Checker in a fixed size LRU cache
+//
+//  Notes
+//    Responses are cached per text and CheckOptions (other than Tag), so a
+//    response, and the client ID in its Meta, is only shared between checks
+//    for the same user and session
+//
+type CachedChecker struct {
+	next Checker
+	size int
+	ttl  time.Duration
+
+	metrics Metrics
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type cacheEntry struct {
+	key      string
+	response *SpellCheckResponse
+	expires  time.Time
+}
+
+// NewCachedChecker creates a CachedChecker that holds up to size responses
+// from next
+//
+//  Notes
+//    A ttl of 0 means entries never expire (but may still be evicted).
+//    Error responses are never cached.
+//
+func NewCachedChecker(next Checker, size int, ttl time.Duration) *CachedChecker {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &CachedChecker{
+		next:    next,
+		size:    size,
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// Check returns a cached response for text and opts, or checks text using
+// the wrapped Checker
+func (cc *CachedChecker) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	key := text + "\x00" + opts.key()
+
+	if scr, ok := cc.get(key); ok {
+		if cc.metrics != nil {
+			cc.metrics.Add(MetricCacheHits, tagLabels(opts), 1)
+		}
+		return scr, nil
+	}
+
+	if cc.metrics != nil {
+		cc.metrics.Add(MetricCacheMisses, tagLabels(opts), 1)
+	}
+
+	scr, err := cc.next.Check(ctx, text, opts)
+	if err != nil || scr.IsErrorResponse() {
+		return scr, err
+	}
+
+	cc.put(key, scr)
+	return scr.Clone(), nil
+}
+
+// Len returns the number of cached responses
+func (cc *CachedChecker) Len() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.order.Len()
+}
+
+// Purge removes all cached responses
+func (cc *CachedChecker) Purge() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.entries = map[string]*list.Element{}
+	cc.order.Init()
+}
+
+func (cc *CachedChecker) get(key string) (*SpellCheckResponse, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	elem, ok := cc.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if cc.ttl > 0 && time.Now().After(entry.expires) {
+		cc.order.Remove(elem)
+		delete(cc.entries, key)
+		return nil, false
+	}
+
+	cc.order.MoveToFront(elem)
+	return entry.response.Clone(), true
+}
+
+func (cc *CachedChecker) put(key string, scr *SpellCheckResponse) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	entry := &cacheEntry{key: key, response: scr.Clone(), expires: time.Now().Add(cc.ttl)}
+
+	if elem, ok := cc.entries[key]; ok {
+		elem.Value = entry
+		cc.order.MoveToFront(elem)
+		return
+	}
+
+	cc.entries[key] = cc.order.PushFront(entry)
+
+	for cc.order.Len() > cc.size {
+		oldest := cc.order.Back()
+		cc.order.Remove(oldest)
+		delete(cc.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
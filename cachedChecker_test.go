@@ -0,0 +1,125 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCachedChecker(t *testing.T) {
+	tests := []struct {
+		name      string
+		checks    []string
+		opts      []*CheckOptions
+		wantCalls int
+	}{
+		{
+			name:      "hit",
+			checks:    []string{"a", "a", "a"},
+			wantCalls: 1,
+		},
+		{
+			name:      "miss on another text",
+			checks:    []string{"a", "b", "a"},
+			wantCalls: 2,
+		},
+		{
+			name:      "tag is not part of the key",
+			checks:    []string{"a", "a"},
+			opts:      []*CheckOptions{{Tag: "one"}, {Tag: "two"}},
+			wantCalls: 1,
+		},
+		{
+			name:      "options are part of the key",
+			checks:    []string{"a", "a", "a"},
+			opts:      []*CheckOptions{nil, {Mode: SpellMode}, {Market: MktUnitedStates}},
+			wantCalls: 3,
+		},
+		{
+			name:      "users do not share responses",
+			checks:    []string{"a", "a", "a"},
+			opts:      []*CheckOptions{{User: "alice"}, {User: "bob"}, {User: "alice"}},
+			wantCalls: 2,
+		},
+		{
+			name:      "least recently used is evicted",
+			checks:    []string{"a", "b", "a", "c", "a", "b"},
+			wantCalls: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := flagging(unknown(0, "teh", "the"))
+			cc := NewCachedChecker(stub, 2, 0)
+
+			for i, text := range tt.checks {
+				var opts *CheckOptions
+				if i < len(tt.opts) {
+					opts = tt.opts[i]
+				}
+				scr, err := cc.Check(context.Background(), text, opts)
+				if err != nil || len(scr.FlaggedTokens) != 1 {
+					t.Fatalf("check %d = %+v, %v", i, scr, err)
+				}
+			}
+
+			if calls := stub.count(); calls != tt.wantCalls {
+				t.Errorf("made %d checks, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCachedCheckerErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		stub *stubChecker
+	}{
+		{"error", failing(errors.New("unavailable"))},
+		{"error response", responding(errorResponse(ServerErrorCode))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := NewCachedChecker(tt.stub, 10, 0)
+			cc.Check(context.Background(), "a", nil)
+			cc.Check(context.Background(), "a", nil)
+
+			if calls := tt.stub.count(); calls != 2 || cc.Len() != 0 {
+				t.Errorf("made %d checks and cached %d responses, want 2 and 0", calls, cc.Len())
+			}
+		})
+	}
+}
+
+func TestCachedCheckerExpiry(t *testing.T) {
+	stub := flagging()
+	cc := NewCachedChecker(stub, 10, time.Millisecond)
+
+	cc.Check(context.Background(), "a", nil)
+	time.Sleep(5 * time.Millisecond)
+	cc.Check(context.Background(), "a", nil)
+
+	if calls := stub.count(); calls != 2 {
+		t.Errorf("made %d checks, want 2", calls)
+	}
+
+	cc.Purge()
+	if cc.Len() != 0 {
+		t.Errorf("%d responses cached after Purge", cc.Len())
+	}
+}
+
+func TestCachedCheckerCopies(t *testing.T) {
+	cc := NewCachedChecker(flagging(unknown(0, "teh", "the")), 10, 0)
+
+	scr, _ := cc.Check(context.Background(), "teh", nil)
+	scr.FlaggedTokens[0].Suggestions[0].Suggestion = "changed"
+
+	scr, _ = cc.Check(context.Background(), "teh", nil)
+	if got := scr.FlaggedTokens[0].Suggestions[0].Suggestion; got != "the" {
+		t.Errorf("cached response was changed to %q", got)
+	}
+}
@@ -0,0 +1,308 @@
+package bingSpellCheck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MarketInfo describes a market supported by the Bing Spell Check API
+//
+//  Fields
+//    Code           - The market code, e.g. "en-US"
+//    Language       - The ISO 639-1 language code of the market, e.g. "en"
+//    LanguageName   - The English name of the language, e.g. "English"
+//    Country        - The country code of the market, e.g. "US"
+//    ProofSupported - Whether ProofMode is supported in the market
+//
+//  Notes
+//    SpellMode is supported in every market
+//
+type MarketInfo struct {
+	Code           MarketCode
+	Language       string
+	LanguageName   string
+	Country        CountryCode
+	ProofSupported bool
+}
+
+// DisplayName returns the English display name of the market, e.g.
+// "English (United States)"
+func (info MarketInfo) DisplayName() string {
+	return fmt.Sprintf("%s (%s)", info.LanguageName, info.Country.Name())
+}
+
+// CountryInfo describes a country supported by the Bing Spell Check API
+type CountryInfo struct {
+	Code CountryCode
+	Name string
+}
+
+var marketCatalog = []MarketInfo{
+	{MktArgentina, "es", "Spanish", CcArgentina, false},
+	{MktAustralia, "en", "English", CcAustralia, false},
+	{MktAustria, "de", "German", CcAustria, false},
+	{MktBelgiumDutch, "nl", "Dutch", CcBelgium, false},
+	{MktBelgiumFrench, "fr", "French", CcBelgium, false},
+	{MktBrazil, "pt", "Portuguese", CcBrazil, true},
+	{MktCanadaEnglish, "en", "English", CcCanada, false},
+	{MktCanadaFrench, "fr", "French", CcCanada, false},
+	{MktChile, "es", "Spanish", CcChile, false},
+	{MktDenmark, "da", "Danish", CcDenmark, false},
+	{MktFinland, "fi", "Finnish", CcFinland, false},
+	{MktFrance, "fr", "French", CcFrance, false},
+	{MktGermany, "de", "German", CcGermany, false},
+	{MktHongKong, "zh", "Traditional Chinese", CcHongKong, false},
+	{MktIndiaEnglish, "en", "English", CcIndiaEnglish, false},
+	{MktIndonesiaEnglish, "en", "English", CcIndonesiaEnglish, false},
+	{MktItaly, "it", "Italian", CcItaly, false},
+	{MktJapan, "ja", "Japanese", CcJapan, false},
+	{MktKorea, "ko", "Korean", CcKorea, false},
+	{MktMalaysiaEnglish, "en", "English", CcMalaysia, false},
+	{MktMexico, "es", "Spanish", CcMexico, false},
+	{MktNetherlands, "nl", "Dutch", CcNetherlands, false},
+	{MktNewZealand, "en", "English", CcNewZealand, false},
+	{MktNorway, "no", "Norwegian", CcNorway, false},
+	{MktChina, "zh", "Simplified Chinese", CcChina, false},
+	{MktPoland, "pl", "Polish", CcPoland, false},
+	{MktPhilipinesEnglish, "en", "English", CcPhilipines, false},
+	{MktRussia, "ru", "Russian", CcRussia, false},
+	{MktSouthAfrica, "en", "English", CcSouthAfrica, false},
+	{MktSpain, "es", "Spanish", CcSpain, true},
+	{MktSweden, "sv", "Swedish", CcSweden, false},
+	{MktSwitzerlandFrench, "fr", "French", CcSwitzerland, false},
+	{MktSwitzerlandGerman, "de", "German", CcSwitzerland, false},
+	{MktTaiwan, "zh", "Traditional Chinese", CcTaiwan, false},
+	{MktTurkey, "tr", "Turkish", CcTurkey, false},
+	{MktUnitedKingdom, "en", "English", CcUnitedKingdom, false},
+	{MktUnitedStates, "en", "English", CcUnitedStates, true},
+	{MktUnitedStatesSpanish, "es", "Spanish", CcUnitedStates, false},
+}
+
+var countryCatalog = []CountryInfo{
+	{CcArgentina, "Argentina"},
+	{CcAustralia, "Australia"},
+	{CcAustria, "Austria"},
+	{CcBelgium, "Belgium"},
+	{CcBrazil, "Brazil"},
+	{CcCanada, "Canada"},
+	{CcChile, "Chile"},
+	{CcDenmark, "Denmark"},
+	{CcFinland, "Finland"},
+	{CcFrance, "France"},
+	{CcGermany, "Germany"},
+	{CcHongKong, "Hong Kong SAR"},
+	{CcIndiaEnglish, "India"},
+	{CcIndonesiaEnglish, "Indonesia"},
+	{CcItaly, "Italy"},
+	{CcJapan, "Japan"},
+	{CcKorea, "Korea"},
+	{CcMalaysia, "Malaysia"},
+	{CcMexico, "Mexico"},
+	{CcNetherlands, "Netherlands"},
+	{CcNewZealand, "New Zealand"},
+	{CcNorway, "Norway"},
+	{CcChina, "China"},
+	{CcPoland, "Poland"},
+	{CcPhilipines, "Philippines"},
+	{CcPortugal, "Portugal"},
+	{CcRussia, "Russia"},
+	{CcSaudiArabia, "Saudi Arabia"},
+	{CcSouthAfrica, "South Africa"},
+	{CcSpain, "Spain"},
+	{CcSweden, "Sweden"},
+	{CcSwitzerland, "Switzerland"},
+	{CcTaiwan, "Taiwan"},
+	{CcTurkey, "Turkey"},
+	{CcUnitedKingdom, "United Kingdom"},
+	{CcUnitedStates, "United States"},
+}
+
+var (
+	marketsByCode   = map[string]int{}
+	countriesByCode = map[string]int{}
+)
+
+func init() {
+	for i, info := range marketCatalog {
+		marketsByCode[strings.ToLower(string(info.Code))] = i
+	}
+	for i, info := range countryCatalog {
+		countriesByCode[strings.ToUpper(string(info.Code))] = i
+	}
+}
+
+// Markets returns every supported market
+func Markets() []MarketInfo {
+	return append([]MarketInfo(nil), marketCatalog...)
+}
+
+// Countries returns every supported country
+func Countries() []CountryInfo {
+	return append([]CountryInfo(nil), countryCatalog...)
+}
+
+// ProofMarkets returns the markets that support ProofMode
+func ProofMarkets() []MarketCode {
+	var codes []MarketCode
+	for _, info := range marketCatalog {
+		if info.ProofSupported {
+			codes = append(codes, info.Code)
+		}
+	}
+
+	return codes
+}
+
+// SpellMarkets returns the markets that support SpellMode (all of them)
+func SpellMarkets() []MarketCode {
+	codes := make([]MarketCode, len(marketCatalog))
+	for i, info := range marketCatalog {
+		codes[i] = info.Code
+	}
+
+	return codes
+}
+
+// LookupMarket returns the catalog entry for code (case insensitive)
+func LookupMarket(code MarketCode) (MarketInfo, bool) {
+	i, ok := marketsByCode[strings.ToLower(string(code))]
+	if !ok {
+		return MarketInfo{}, false
+	}
+
+	return marketCatalog[i], true
+}
+
+// LookupCountry returns the catalog entry for code (case insensitive)
+func LookupCountry(code CountryCode) (CountryInfo, bool) {
+	i, ok := countriesByCode[strings.ToUpper(string(code))]
+	if !ok {
+		return CountryInfo{}, false
+	}
+
+	return countryCatalog[i], true
+}
+
+// ParseMarketCode converts s to a supported MarketCode
+//
+//  Notes
+//    Matching is case insensitive and accepts '_' as a separator, so
+//    "en-us", "EN_US" and "en-US" all return MktUnitedStates
+//
+func ParseMarketCode(s string) (MarketCode, error) {
+	info, ok := LookupMarket(MarketCode(strings.Replace(strings.TrimSpace(s), "_", "-", 1)))
+	if !ok {
+		return "", fmt.Errorf("bingSpellCheck: unsupported market %q", s)
+	}
+
+	return info.Code, nil
+}
+
+// ParseCountryCode converts s to a supported CountryCode (case insensitive)
+func ParseCountryCode(s string) (CountryCode, error) {
+	info, ok := LookupCountry(CountryCode(strings.TrimSpace(s)))
+	if !ok {
+		return "", fmt.Errorf("bingSpellCheck: unsupported country %q", s)
+	}
+
+	return info.Code, nil
+}
+
+// MarketsForLanguage returns the markets that use lang, an ISO 639-1
+// language code (case insensitive)
+func MarketsForLanguage(lang string) []MarketInfo {
+	var markets []MarketInfo
+	for _, info := range marketCatalog {
+		if strings.EqualFold(info.Language, lang) {
+			markets = append(markets, info)
+		}
+	}
+
+	return markets
+}
+
+// MarketsForCountry returns the markets in country cc (case insensitive)
+func MarketsForCountry(cc CountryCode) []MarketInfo {
+	var markets []MarketInfo
+	for _, info := range marketCatalog {
+		if strings.EqualFold(string(info.Country), string(cc)) {
+			markets = append(markets, info)
+		}
+	}
+
+	return markets
+}
+
+// IsSupported determines if the market is in the catalog
+func (market MarketCode) IsSupported() bool {
+	_, ok := LookupMarket(market)
+	return ok
+}
+
+// Language returns the ISO 639-1 language code of the market, e.g. "en"
+func (market MarketCode) Language() string {
+	if info, ok := LookupMarket(market); ok {
+		return info.Language
+	}
+
+	return strings.ToLower(strings.SplitN(string(market), "-", 2)[0])
+}
+
+// Country returns the CountryCode that matches the market, e.g. "US" for
+// "en-US"
+func (market MarketCode) Country() CountryCode {
+	if info, ok := LookupMarket(market); ok {
+		return info.Country
+	}
+
+	parts := strings.SplitN(string(market), "-", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+
+	return CountryCode(strings.ToUpper(parts[1]))
+}
+
+// DisplayName returns the English display name of the market, e.g.
+// "English (United States)", or the market code if it is not supported
+func (market MarketCode) DisplayName() string {
+	if info, ok := LookupMarket(market); ok {
+		return info.DisplayName()
+	}
+
+	return string(market)
+}
+
+// SupportsMode determines if mode (ProofMode or SpellMode) is supported in
+// the market
+func (market MarketCode) SupportsMode(mode string) bool {
+	info, ok := LookupMarket(market)
+	if !ok {
+		return false
+	}
+
+	switch mode {
+	case ProofMode:
+		return info.ProofSupported
+	case SpellMode:
+		return true
+	}
+
+	return false
+}
+
+// IsSupported determines if the country is in the catalog
+func (cc CountryCode) IsSupported() bool {
+	_, ok := LookupCountry(cc)
+	return ok
+}
+
+// Name returns the English name of the country, or the country code if it
+// is not supported
+func (cc CountryCode) Name() string {
+	if info, ok := LookupCountry(cc); ok {
+		return info.Name
+	}
+
+	return string(cc)
+}
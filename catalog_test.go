@@ -0,0 +1,166 @@
+package bingSpellCheck
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMarketCode(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    MarketCode
+		wantErr bool
+	}{
+		{"en-US", MktUnitedStates, false},
+		{"en-us", MktUnitedStates, false},
+		{"EN_US", MktUnitedStates, false},
+		{" pt-br ", MktBrazil, false},
+		{"zh-hk", MktHongKong, false},
+		{"es-us", MktUnitedStatesSpanish, false},
+		{"en", "", true},
+		{"de-LU", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMarketCode(tt.s)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMarketCode(%q) = %q, %v; want %q, error %v", tt.s, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseCountryCode(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    CountryCode
+		wantErr bool
+	}{
+		{"US", CcUnitedStates, false},
+		{"us", CcUnitedStates, false},
+		{" br ", CcBrazil, false},
+		{"PT", CcPortugal, false},
+		{"XX", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCountryCode(tt.s)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCountryCode(%q) = %q, %v; want %q, error %v", tt.s, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMarketCodeInfo(t *testing.T) {
+	tests := []struct {
+		market      MarketCode
+		supported   bool
+		language    string
+		country     CountryCode
+		displayName string
+	}{
+		{MktUnitedStates, true, "en", CcUnitedStates, "English (United States)"},
+		{"fr-ca", true, "fr", CcCanada, "French (Canada)"},
+		{MktHongKong, true, "zh", CcHongKong, "Traditional Chinese (Hong Kong SAR)"},
+		{"de-LU", false, "de", "LU", "de-LU"},
+		{"xx", false, "xx", "", "xx"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.market.IsSupported(); got != tt.supported {
+			t.Errorf("%s: IsSupported = %v, want %v", tt.market, got, tt.supported)
+		}
+		if got := tt.market.Language(); got != tt.language {
+			t.Errorf("%s: Language = %q, want %q", tt.market, got, tt.language)
+		}
+		if got := tt.market.Country(); got != tt.country {
+			t.Errorf("%s: Country = %q, want %q", tt.market, got, tt.country)
+		}
+		if got := tt.market.DisplayName(); got != tt.displayName {
+			t.Errorf("%s: DisplayName = %q, want %q", tt.market, got, tt.displayName)
+		}
+	}
+}
+
+func TestSupportsMode(t *testing.T) {
+	tests := []struct {
+		market MarketCode
+		proof  bool
+		spell  bool
+	}{
+		{MktUnitedStates, true, true},
+		{MktSpain, true, true},
+		{MktBrazil, true, true},
+		{MktUnitedStatesSpanish, false, true},
+		{MktUnitedKingdom, false, true},
+		{"en-us", true, true},
+		{"de-LU", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.market.SupportsMode(ProofMode); got != tt.proof {
+			t.Errorf("%s: SupportsMode(proof) = %v, want %v", tt.market, got, tt.proof)
+		}
+		if got := tt.market.SupportsMode(SpellMode); got != tt.spell {
+			t.Errorf("%s: SupportsMode(spell) = %v, want %v", tt.market, got, tt.spell)
+		}
+		if tt.market.SupportsMode("grammar") {
+			t.Errorf("%s: SupportsMode(grammar) = true", tt.market)
+		}
+	}
+
+	want := []MarketCode{MktBrazil, MktSpain, MktUnitedStates}
+	if got := ProofMarkets(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ProofMarkets = %v, want %v", got, want)
+	}
+	if got := SpellMarkets(); len(got) != len(Markets()) {
+		t.Errorf("SpellMarkets has %d markets, want %d", len(got), len(Markets()))
+	}
+}
+
+func TestMarketsForLanguageAndCountry(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []MarketInfo
+		want []MarketCode
+	}{
+		{"portuguese", MarketsForLanguage("PT"), []MarketCode{MktBrazil}},
+		{"dutch", MarketsForLanguage("nl"), []MarketCode{MktBelgiumDutch, MktNetherlands}},
+		{"unknown language", MarketsForLanguage("xx"), nil},
+		{"switzerland", MarketsForCountry("ch"), []MarketCode{MktSwitzerlandFrench, MktSwitzerlandGerman}},
+		{"united states", MarketsForCountry(CcUnitedStates), []MarketCode{MktUnitedStates, MktUnitedStatesSpanish}},
+	}
+
+	for _, tt := range tests {
+		var got []MarketCode
+		for _, info := range tt.got {
+			got = append(got, info.Code)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogConsistency(t *testing.T) {
+	seen := map[MarketCode]bool{}
+	for _, info := range Markets() {
+		if seen[info.Code] {
+			t.Errorf("%s is in the catalog twice", info.Code)
+		}
+		seen[info.Code] = true
+
+		if !info.Country.IsSupported() {
+			t.Errorf("%s: country %s is not in the catalog", info.Code, info.Country)
+		}
+		if got, ok := LookupMarket(info.Code); !ok || got != info {
+			t.Errorf("LookupMarket(%s) = %+v, %v", info.Code, got, ok)
+		}
+	}
+
+	markets := Markets()
+	markets[0].Code = "changed"
+	if Markets()[0].Code == "changed" {
+		t.Error("Markets returned the catalog itself")
+	}
+}
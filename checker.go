@@ -0,0 +1,219 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// CheckOptions are the per request options of a Checker
+//
+//  Fields
+//    PreContext  - Text that precedes the text being checked (optional)
+//    PostContext - Text that follows the text being checked (optional)
+//    Mode        - ProofMode or SpellMode (optional, the checker's default
+//      is used when empty)
+//    Market      - The market to check against (optional, the checker's
+//      default is used when empty)
+//    User        - Identifies the user the text belongs to (optional, see
+//      Client.WithClientIDStore)
+//    SessionID, UserID, DocumentID, ClientMachineName, ActionType - The
+//      values of the corresponding request parameters (optional, the
+//      checker's defaults are used when empty; see Session)
+//    Tag         - Identifies the caller in metrics (optional, see Metrics)
+//
+//  Notes
+//    A nil *CheckOptions is equivalent to a zero CheckOptions. Checkers that
+//    do not support an option ignore it.
+//
+type CheckOptions struct {
+	PreContext  string
+	PostContext string
+	Mode        string
+	Market      MarketCode
+	User        string
+
+	SessionID         string
+	UserID            string
+	DocumentID        string
+	ClientMachineName string
+	ActionType        string
+
+	Tag string
+}
+
+// Checker is a spell checker that reports its findings as a
+// SpellCheckResponse
+//
+//  Notes
+//    Client is the Bing implementation of Checker. Implementations must be
+//    safe for concurrent use and must not modify a response after returning
+//    it.
+//
+type Checker interface {
+	Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error)
+}
+
+// CheckerFunc is an adapter to allow the use of ordinary functions as a
+// Checker
+type CheckerFunc func(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error)
+
+// Check calls f(ctx, text, opts)
+func (f CheckerFunc) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	return f(ctx, text, opts)
+}
+
+// Check performs a spelling and/or grammar check on text, failing over to
+// the fallback checker (if any) when Bing is unavailable or rejects the key
+//
+//  Notes
+//    Unless disabled with WithValidation, the request is validated before it
+//    is sent and ValidationErrors is returned if there are problems.
+//
+//    Options are applied to a copy of client.Params, so concurrent calls to
+//    Check are safe as long as Params and Headers are not modified
+//
+func (client *Client) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	if opts == nil {
+		opts = &CheckOptions{}
+	}
+
+	params := client.Params.Clone().WithTextAndContext(text, opts.PreContext, opts.PostContext)
+	if opts.Mode != "" {
+		params.SetParam(ModeParam, opts.Mode)
+	}
+	if opts.Market != "" {
+		params.WithMarket(opts.Market)
+	}
+	for param, value := range map[string]string{
+		SessionIDParam:         opts.SessionID,
+		UserIDParam:            opts.UserID,
+		DocumentIDParam:        opts.DocumentID,
+		ClientMachineNameParam: opts.ClientMachineName,
+		ActionTypeParam:        opts.ActionType,
+	} {
+		if value != "" {
+			params.SetParam(param, value)
+		}
+	}
+
+	ctx, span := startSpan(ctx, client.tracer, SpanCheck, requestAttributes(params, opts.Tag)...)
+
+	headers, clientID := client.clientHeaders(ctx, opts.User)
+
+	if !client.skipValidation {
+		// with a key pool the key comes from the pool, not Headers
+		if err := validateRequest(params, headers, client.keyPool == nil); err != nil {
+			endSpan(span, nil, err)
+			return nil, err
+		}
+	}
+
+	scr, err := client.send(ctx, params, headers, opts.Tag)
+	if err == nil {
+		client.saveClientID(ctx, opts.User, clientID, scr)
+	}
+
+	if client.fallback != nil && ctx.Err() == nil && (err != nil || scr.IsUnavailable() || scr.IsKeyRejected()) {
+		scr, err = client.fallback.Check(ctx, text, opts)
+	}
+
+	endSpan(span, scr, err)
+	return scr, err
+}
+
+// send sends a request using the key pool, if there is one, or else the
+// subscription key in headers
+func (client *Client) send(ctx context.Context, params *SpellCheckParams, headers *SpellCheckHeaders, tag string) (*SpellCheckResponse, error) {
+	if client.dryRun {
+		return client.logDryRun(ctx, params, headers)
+	}
+
+	var reservation *Reservation
+	if client.ledger != nil {
+		var err error
+		if reservation, err = client.ledger.Reserve(1); err != nil {
+			return nil, err
+		}
+		defer reservation.Release()
+	}
+
+	if client.keyPool == nil {
+		return client.do(ctx, client.spellCheckURL, params, headers, tag, reservation)
+	}
+
+	var scr *SpellCheckResponse
+	var err error
+
+	for attempts := client.keyPool.Len(); attempts > 0; attempts-- {
+		key, keyErr := client.keyPool.Next()
+		if keyErr != nil {
+			if scr != nil || err != nil {
+				break
+			}
+			return nil, keyErr
+		}
+
+		keyHeaders := headers.Clone().WithSubscriptionKey(key.Key)
+		scr, err = client.do(ctx, key.SpellCheckURL(client.spellCheckURL), params, keyHeaders, tag, reservation)
+		if err != nil || !scr.IsKeyRejected() {
+			break
+		}
+
+		client.keyPool.MarkExhausted(key.Key)
+	}
+
+	return scr, err
+}
+
+// do sends a request, recording metrics, a span and the transaction (in
+// reservation) if the client has them
+func (client *Client) do(
+	ctx context.Context,
+	targetURL string,
+	params *SpellCheckParams,
+	headers *SpellCheckHeaders,
+	tag string,
+	reservation *Reservation) (*SpellCheckResponse, error) {
+
+	ctx, span := startSpan(ctx, client.tracer, SpanRequest, requestAttributes(params, tag)...)
+
+	start := time.Now()
+	scr, err := client.logAndSend(ctx, targetURL, params, headers)
+
+	if client.metrics != nil {
+		recordRequest(client.metrics, params.Values.Get(MarketParam), tag, time.Since(start).Seconds(), scr, err)
+	}
+
+	recordTransaction(ctx, reservation, headers, tag, scr, err)
+
+	endSpan(span, scr, err)
+	return scr, err
+}
+
+// key returns a string that identifies the options that can change a
+// response (every option but Tag), for use in map keys
+//
+//  Notes
+//    User and the request IDs are included because the response's Meta
+//    (e.g. the client ID Bing returns) belongs to the user and session it
+//    was sent for
+//
+func (opts *CheckOptions) key() string {
+	if opts == nil {
+		opts = &CheckOptions{}
+	}
+
+	return strings.Join([]string{
+		opts.PreContext,
+		opts.PostContext,
+		opts.Mode,
+		string(opts.Market),
+		opts.User,
+		opts.SessionID,
+		opts.UserID,
+		opts.DocumentID,
+		opts.ClientMachineName,
+		opts.ActionType,
+	}, "\x00")
+}
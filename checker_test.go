@@ -0,0 +1,127 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubChecker is a Checker that answers with respond and counts its calls
+type stubChecker struct {
+	respond func(text string, opts *CheckOptions) (*SpellCheckResponse, error)
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (stub *stubChecker) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	stub.mu.Lock()
+	stub.calls++
+	stub.mu.Unlock()
+
+	return stub.respond(text, opts)
+}
+
+func (stub *stubChecker) count() int {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	return stub.calls
+}
+
+// flagging returns a stubChecker that flags tokens in every text
+func flagging(tokens ...FlaggedToken) *stubChecker {
+	return &stubChecker{respond: func(text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+		return spellCheckResponse(tokens...), nil
+	}}
+}
+
+// failing returns a stubChecker that fails every check with err
+func failing(err error) *stubChecker {
+	return &stubChecker{respond: func(text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+		return nil, err
+	}}
+}
+
+// responding returns a stubChecker that answers the checks with responses
+// in turn, repeating the last one
+func responding(responses ...*SpellCheckResponse) *stubChecker {
+	stub := &stubChecker{}
+	stub.respond = func(text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+		n := stub.count() - 1
+		if n >= len(responses) {
+			n = len(responses) - 1
+		}
+		return responses[n].Clone(), nil
+	}
+
+	return stub
+}
+
+func spellCheckResponse(tokens ...FlaggedToken) *SpellCheckResponse {
+	scr := &SpellCheckResponse{Type: SpellCheckResponseType, FlaggedTokens: []FlaggedToken{}}
+	for _, token := range tokens {
+		scr.FlaggedTokens = append(scr.FlaggedTokens, token)
+	}
+
+	return scr.Clone()
+}
+
+func errorResponse(code string) *SpellCheckResponse {
+	return &SpellCheckResponse{Type: ErrorResponseType, Errors: []Error{{Code: code, Message: code}}}
+}
+
+func unknown(offset int, token string, suggestions ...string) FlaggedToken {
+	flagged := FlaggedToken{Offset: offset, Token: token, Type: UnknownTokenType}
+	for i, suggestion := range suggestions {
+		flagged.Suggestions = append(flagged.Suggestions, TokenSuggestion{Score: 0.9 - float64(i)/10, Suggestion: suggestion})
+	}
+
+	return flagged
+}
+
+// keyServer returns a server that answers requests made with the keys in
+// statuses with that HTTP status, and any other key with no findings
+func keyServer(t *testing.T, statuses map[string]int) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status, ok := statuses[r.Header.Get(SubscriptionKeyHeader)]; ok {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"_type":"SpellCheck","flaggedTokens":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestClientRejectedKey(t *testing.T) {
+	srv := keyServer(t, map[string]int{"disabled": http.StatusForbidden})
+
+	// a disabled key is not retried
+	client := NewClient("disabled").WithEndpoint(srv.URL)
+	scr, err := NewRetryChecker(client, 3, 0).Check(context.Background(), "text", nil)
+	if err != nil || !scr.IsKeyRejected() || scr.IsUnavailable() {
+		t.Errorf("got %+v, %v; want a rejected key", scr, err)
+	}
+
+	// but the pool moves on to the next key
+	pool := NewKeyPool(RoundRobin, time.Hour, PoolKey{Key: "disabled"}, PoolKey{Key: "good"})
+	scr, err = NewClient("").WithEndpoint(srv.URL).WithKeyPool(pool).Check(context.Background(), "text", nil)
+	if err != nil || scr.IsErrorResponse() {
+		t.Errorf("got %+v, %v; want a response using the good key", scr, err)
+	}
+
+	// and the fallback is used without one
+	fallback := flagging(unknown(0, "text", "test"))
+	scr, err = NewClient("disabled").WithEndpoint(srv.URL).WithFallback(fallback).Check(context.Background(), "text", nil)
+	if err != nil || len(scr.FlaggedTokens) != 1 || fallback.count() != 1 {
+		t.Errorf("got %+v, %v; want the fallback's response", scr, err)
+	}
+}
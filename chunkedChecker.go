@@ -0,0 +1,170 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var sentenceBreak = regexp.MustCompile(`[.!?]["')\]]*\s+`)
+
+// SplitChunks splits text into chunks of at most maxLength characters,
+// breaking at paragraphs, then sentences, then whitespace where possible
+//
+//  Notes
+//    A chunk is only broken inside a word when it has no whitespace
+//
+func SplitChunks(text string, maxLength int) []Segment {
+	if maxLength < 1 {
+		maxLength = 1
+	}
+
+	var chunks []Segment
+
+	for offset := 0; offset < len(text); {
+		rest := text[offset:]
+		if utf8.RuneCountInString(rest) <= maxLength {
+			chunks = append(chunks, Segment{Offset: offset, Text: rest})
+			break
+		}
+
+		// the byte length of the first maxLength characters
+		limit := 0
+		for i := 0; i < maxLength; i++ {
+			_, size := utf8.DecodeRuneInString(rest[limit:])
+			limit += size
+		}
+
+		end := chunkEnd(rest[:limit])
+		chunks = append(chunks, Segment{Offset: offset, Text: rest[:end]})
+		offset += end
+	}
+
+	return chunks
+}
+
+// chunkEnd returns the byte offset of the best place to end a chunk of s
+func chunkEnd(s string) int {
+	for _, re := range []*regexp.Regexp{paragraphBreak, sentenceBreak} {
+		if locs := re.FindAllStringIndex(s, -1); len(locs) > 0 {
+			if end := locs[len(locs)-1][1]; end > 0 {
+				return end
+			}
+		}
+	}
+
+	if i := strings.LastIndexAny(s, " \t\r\n"); i >= 0 {
+		return i + 1
+	}
+
+	return len(s)
+}
+
+// ChunkedChecker is a Checker that splits texts that are too long for a
+// single request into chunks and checks them one at a time
+//
+//  Notes
+//    The PreContext is sent with the first chunk and the PostContext with
+//    the last, and chunks are sized so the text and context fit in
+//    maxLength characters. The context may take at most half of maxLength,
+//    so chunks are never too small to check.
+//
+type ChunkedChecker struct {
+	next      Checker
+	maxLength int
+	tracer    Tracer
+}
+
+// NewChunkedChecker creates a ChunkedChecker that checks chunks of up to
+// maxLength characters using next
+//
+//  Notes
+//    When maxLength is 0, MaxPostTextLength is used
+//
+func NewChunkedChecker(next Checker, maxLength int) *ChunkedChecker {
+	if maxLength <= 0 {
+		maxLength = MaxPostTextLength
+	}
+
+	return &ChunkedChecker{next: next, maxLength: maxLength}
+}
+
+// WithTracer creates a SpanChunked span for each check and a SpanChunk span
+// for each chunk
+func (cc *ChunkedChecker) WithTracer(tracer Tracer) *ChunkedChecker {
+	cc.tracer = tracer
+	return cc
+}
+
+// Check checks text one chunk at a time and merges the results into a
+// single response, with offsets relative to text
+//
+//  Notes
+//    If any chunk fails, its error (or error response) is returned. If the
+//    PreContext and PostContext are longer than half of maxLength,
+//    ValidationErrors are returned.
+//
+func (cc *ChunkedChecker) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	if opts == nil {
+		opts = &CheckOptions{}
+	}
+
+	contextLength := utf8.RuneCountInString(opts.PreContext) + utf8.RuneCountInString(opts.PostContext)
+	if contextLength > cc.maxLength/2 {
+		var errs ValidationErrors
+		errs.add(PreContextTextParam, "", "context length %d exceeds %d characters, half of the chunk length %d",
+			contextLength, cc.maxLength/2, cc.maxLength)
+		return nil, errs.err()
+	}
+
+	maxLength := cc.maxLength - contextLength
+	chunks := SplitChunks(text, maxLength)
+
+	ctx, span := startSpan(ctx, cc.tracer, SpanChunked,
+		Attribute{MarketAttribute, string(opts.Market)},
+		Attribute{ModeAttribute, opts.Mode},
+		Attribute{TextLengthAttribute, utf8.RuneCountInString(text)},
+		Attribute{TagAttribute, opts.Tag},
+		Attribute{ChunkCountAttribute, len(chunks)})
+
+	scr, err := cc.checkChunks(ctx, chunks, opts)
+	endSpan(span, scr, err)
+
+	return scr, err
+}
+
+func (cc *ChunkedChecker) checkChunks(ctx context.Context, chunks []Segment, opts *CheckOptions) (*SpellCheckResponse, error) {
+	merged := &SpellCheckResponse{Type: SpellCheckResponseType, FlaggedTokens: []FlaggedToken{}}
+
+	for i, chunk := range chunks {
+		chunkOpts := *opts
+		if i > 0 {
+			chunkOpts.PreContext = ""
+		}
+		if i < len(chunks)-1 {
+			chunkOpts.PostContext = ""
+		}
+
+		chunkCtx, span := startSpan(ctx, cc.tracer, SpanChunk,
+			Attribute{ChunkAttribute, i},
+			Attribute{TextLengthAttribute, utf8.RuneCountInString(chunk.Text)})
+
+		scr, err := cc.next.Check(chunkCtx, chunk.Text, &chunkOpts)
+		endSpan(span, scr, err)
+
+		if err != nil {
+			return nil, err
+		}
+		if scr.IsErrorResponse() {
+			return scr, nil
+		}
+
+		for _, token := range scr.FlaggedTokens {
+			token.Offset += chunk.Offset
+			merged.FlaggedTokens = append(merged.FlaggedTokens, token)
+		}
+	}
+
+	return merged, nil
+}
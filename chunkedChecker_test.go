@@ -0,0 +1,167 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSplitChunks(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		maxLength int
+		want      []string
+	}{
+		{"short", "one two", 10, []string{"one two"}},
+		{"words", "one two three four", 9, []string{"one two ", "three ", "four"}},
+		{"sentences", "One two. Three four.", 15, []string{"One two. ", "Three four."}},
+		{"paragraphs", "One. Two\n\nThree", 14, []string{"One. Two\n\n", "Three"}},
+		{"no whitespace", "abcdefgh", 3, []string{"abc", "def", "gh"}},
+		{"characters not bytes", "ééé ééé", 4, []string{"ééé ", "ééé"}},
+		{"empty", "", 5, nil},
+		{"zero length", "ab", 0, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := SplitChunks(tt.text, tt.maxLength)
+
+			var got []string
+			offset := 0
+			for _, chunk := range chunks {
+				if chunk.Offset != offset {
+					t.Errorf("chunk %q at %d, want %d", chunk.Text, chunk.Offset, offset)
+				}
+				offset += len(chunk.Text)
+				got = append(got, chunk.Text)
+			}
+
+			if !equalStrings(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkedChecker(t *testing.T) {
+	var mu sync.Mutex
+	var checked []CheckOptions
+
+	// flags every "teh" in each chunk
+	next := &stubChecker{respond: func(text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+		mu.Lock()
+		checked = append(checked, *opts)
+		mu.Unlock()
+
+		if len([]rune(text))+len([]rune(opts.PreContext))+len([]rune(opts.PostContext)) > 20 {
+			t.Errorf("%q with context is longer than 20 characters", text)
+		}
+
+		scr := spellCheckResponse()
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], "teh")
+			if i < 0 {
+				return scr, nil
+			}
+			scr.FlaggedTokens = append(scr.FlaggedTokens, unknown(offset+i, "teh", "the"))
+			offset += i + len("teh")
+		}
+	}}
+
+	text := "teh cat sat on teh mat and teh dog sat on teh log"
+	scr, err := NewChunkedChecker(next, 20).Check(context.Background(), text, &CheckOptions{PreContext: "pre", PostContext: "post"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var offsets []int
+	for _, token := range scr.FlaggedTokens {
+		if text[token.Offset:token.Offset+len(token.Token)] != token.Token {
+			t.Errorf("token %q at %d is %q in the text", token.Token, token.Offset, text[token.Offset:])
+		}
+		offsets = append(offsets, token.Offset)
+	}
+	if len(offsets) != 4 {
+		t.Errorf("flagged %v, want 4 tokens", offsets)
+	}
+
+	if n := len(checked); n < 3 {
+		t.Fatalf("checked %d chunks, want at least 3", n)
+	}
+	for i, opts := range checked {
+		wantPre, wantPost := "", ""
+		if i == 0 {
+			wantPre = "pre"
+		}
+		if i == len(checked)-1 {
+			wantPost = "post"
+		}
+		if opts.PreContext != wantPre || opts.PostContext != wantPost {
+			t.Errorf("chunk %d sent with context %q, %q; want %q, %q", i, opts.PreContext, opts.PostContext, wantPre, wantPost)
+		}
+	}
+}
+
+func TestChunkedCheckerErrors(t *testing.T) {
+	checkErr := errors.New("check failed")
+
+	tests := []struct {
+		name      string
+		next      *stubChecker
+		opts      *CheckOptions
+		wantCalls int
+		wantErr   bool
+		wantCode  string
+	}{
+		{
+			name:      "error",
+			next:      failing(checkErr),
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "error response",
+			next:      responding(errorResponse(RateLimitExceededErrorCode)),
+			wantCalls: 1,
+			wantCode:  RateLimitExceededErrorCode,
+		},
+		{
+			name:    "context too long",
+			next:    flagging(),
+			opts:    &CheckOptions{PreContext: "123456", PostContext: "12345"},
+			wantErr: true,
+		},
+		{
+			name:      "context at the limit",
+			next:      flagging(),
+			opts:      &CheckOptions{PreContext: "12345", PostContext: "12345"},
+			wantCalls: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := strings.Repeat("word ", 8)
+
+			scr, err := NewChunkedChecker(tt.next, 20).Check(context.Background(), text, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantCode != "" && (scr == nil || len(scr.Errors) == 0 || scr.Errors[0].Code != tt.wantCode) {
+				t.Errorf("got %+v, want a %s error response", scr, tt.wantCode)
+			}
+			if got := tt.next.count(); got != tt.wantCalls {
+				t.Errorf("checked %d chunks, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+
+	var errs ValidationErrors
+	_, err := NewChunkedChecker(flagging(), 20).Check(context.Background(), "text", &CheckOptions{PreContext: strings.Repeat("x", 11)})
+	if !errors.As(err, &errs) {
+		t.Errorf("got %v, want ValidationErrors", err)
+	}
+}
@@ -0,0 +1,98 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"sync"
+)
+
+// ClientIDStore persists the X-MSEdge-ClientID returned by Bing for each
+// user, so it can be sent with the user's subsequent requests
+//
+//  Notes
+//    Bing uses the client ID to provide users with consistent behavior
+//    across calls. Get returns "" (and no error) for an unknown user.
+//    Implementations must be safe for concurrent use.
+//
+type ClientIDStore interface {
+	Get(ctx context.Context, user string) (string, error)
+	Set(ctx context.Context, user, clientID string) error
+}
+
+// MemoryClientIDStore is an in memory ClientIDStore
+type MemoryClientIDStore struct {
+	mutex     sync.RWMutex
+	clientIDs map[string]string
+}
+
+// NewMemoryClientIDStore creates an empty MemoryClientIDStore
+func NewMemoryClientIDStore() *MemoryClientIDStore {
+	return &MemoryClientIDStore{clientIDs: map[string]string{}}
+}
+
+// Get returns the client ID of user, or "" if there is none
+func (store *MemoryClientIDStore) Get(ctx context.Context, user string) (string, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	return store.clientIDs[user], nil
+}
+
+// Set sets the client ID of user
+func (store *MemoryClientIDStore) Set(ctx context.Context, user, clientID string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.clientIDs[user] = clientID
+	return nil
+}
+
+// Delete removes the client ID of user, e.g. when the user opts out of
+// tracking
+func (store *MemoryClientIDStore) Delete(user string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.clientIDs, user)
+}
+
+// WithClientIDStore makes the client send each user's X-MSEdge-ClientID
+// (from store) with their requests, and save the client ID Bing returns
+//
+//  Notes
+//    Users are identified by CheckOptions.User. Requests without a User
+//    are anonymous: the store is not used, so they are sent with the
+//    client's Headers as they are and anonymous users never share a client
+//    ID. Errors from the store are not fatal: the request is sent without a
+//    client ID, or the returned client ID is not saved.
+//
+func (client *Client) WithClientIDStore(store ClientIDStore) *Client {
+	client.clientIDStore = store
+	return client
+}
+
+// clientHeaders returns the headers to use for user's request, and the
+// client ID that was sent
+func (client *Client) clientHeaders(ctx context.Context, user string) (*SpellCheckHeaders, string) {
+	if client.clientIDStore == nil || user == "" {
+		return client.Headers, ""
+	}
+
+	clientID, err := client.clientIDStore.Get(ctx, user)
+	if err != nil || clientID == "" {
+		return client.Headers, ""
+	}
+
+	return client.Headers.Clone().WithClientID(clientID), clientID
+}
+
+// saveClientID saves the client ID returned in scr for user, if it has
+// changed
+func (client *Client) saveClientID(ctx context.Context, user, sent string, scr *SpellCheckResponse) {
+	if client.clientIDStore == nil || user == "" || scr == nil || scr.Meta == nil {
+		return
+	}
+
+	if clientID := scr.Meta.ClientID; clientID != "" && clientID != sent {
+		_ = client.clientIDStore.Set(ctx, user, clientID)
+	}
+}
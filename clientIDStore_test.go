@@ -0,0 +1,159 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestMemoryClientIDStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryClientIDStore()
+
+	if id, err := store.Get(ctx, "alice"); id != "" || err != nil {
+		t.Errorf("Get of an unknown user = %q, %v", id, err)
+	}
+
+	store.Set(ctx, "alice", "id-1")
+	store.Set(ctx, "bob", "id-2")
+	store.Set(ctx, "alice", "id-3")
+
+	tests := []struct {
+		user string
+		want string
+	}{
+		{"alice", "id-3"},
+		{"bob", "id-2"},
+		{"carol", ""},
+	}
+
+	for _, tt := range tests {
+		if got, err := store.Get(ctx, tt.user); got != tt.want || err != nil {
+			t.Errorf("Get(%q) = %q, %v; want %q", tt.user, got, err, tt.want)
+		}
+	}
+
+	store.Delete("alice")
+	if got, _ := store.Get(ctx, "alice"); got != "" {
+		t.Errorf("Get after Delete = %q", got)
+	}
+}
+
+// failingClientIDStore fails every Get and Set
+type failingClientIDStore struct{}
+
+func (failingClientIDStore) Get(ctx context.Context, user string) (string, error) {
+	return "", errors.New("store unavailable")
+}
+
+func (failingClientIDStore) Set(ctx context.Context, user, clientID string) error {
+	return errors.New("store unavailable")
+}
+
+// clientIDServer returns a server that records the client ID of each
+// request and assigns a new client ID to requests without one
+func clientIDServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var received []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		clientID := r.Header.Get(ClientIDHeader)
+		received = append(received, clientID)
+		if clientID == "" {
+			clientID = "assigned-" + strconv.Itoa(len(received))
+		}
+		mu.Unlock()
+
+		w.Header().Set(ClientIDHeader, clientID)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"_type":"SpellCheck","flaggedTokens":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}
+}
+
+func TestClientIDStickiness(t *testing.T) {
+	tests := []struct {
+		name  string
+		store ClientIDStore
+		users []string
+		want  []string
+	}{
+		{
+			name:  "per user",
+			store: NewMemoryClientIDStore(),
+			users: []string{"alice", "bob", "alice", "bob"},
+			want:  []string{"", "", "assigned-1", "assigned-2"},
+		},
+		{
+			name:  "anonymous users do not share a client ID",
+			store: NewMemoryClientIDStore(),
+			users: []string{"", "", "alice", ""},
+			want:  []string{"", "", "", ""},
+		},
+		{
+			name:  "store errors are not fatal",
+			store: failingClientIDStore{},
+			users: []string{"alice", "alice"},
+			want:  []string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, received := clientIDServer(t)
+			client := NewClient("key").WithEndpoint(srv.URL).WithClientIDStore(tt.store)
+
+			for _, user := range tt.users {
+				scr, err := client.Check(context.Background(), "text", &CheckOptions{User: user})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if scr.Meta == nil || scr.Meta.ClientID == "" {
+					t.Fatalf("no client ID in %+v", scr.Meta)
+				}
+			}
+
+			if got := received(); !equalStrings(got, tt.want) {
+				t.Errorf("sent client IDs %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIDStoreAnonymous(t *testing.T) {
+	srv, _ := clientIDServer(t)
+	store := NewMemoryClientIDStore()
+	client := NewClient("key").WithEndpoint(srv.URL).WithClientIDStore(store)
+
+	if _, err := client.Check(context.Background(), "text", nil); err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := store.Get(context.Background(), ""); id != "" {
+		t.Errorf("stored %q for anonymous users", id)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
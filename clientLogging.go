@@ -0,0 +1,252 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// LogOptions control the events logged by a client (see WithLogger)
+//
+//  Fields
+//    Level         - The level of request and response events
+//    ErrorLevel    - The level of failed requests and error responses
+//    RedactHeaders - Headers to redact in addition to the subscription key
+//      and the client's ID, IP address and location, which are always
+//      redacted
+//    RedactParams  - Query parameters to redact, e.g. UserIDParam
+//    RedactText    - If set, applied to the text and context parameters,
+//      and to the flagged tokens and suggestions of response bodies, before
+//      they are logged; otherwise they are redacted
+//    LogBodies     - Log the full request (params and headers) and response
+//      at slog.LevelDebug
+//
+//  Notes
+//    The text being checked is never logged, only its length, and with
+//    LogBodies any part of it (including the flagged tokens of a response)
+//    is logged only as RedactText returns it.
+//
+//    The zero LogOptions logs everything at slog.LevelInfo; see
+//    DefaultLogOptions
+//
+type LogOptions struct {
+	Level         slog.Level
+	ErrorLevel    slog.Level
+	RedactHeaders []string
+	RedactParams  []string
+	RedactText    func(text string) string
+	LogBodies     bool
+}
+
+// DefaultLogOptions returns the LogOptions used when WithLogger is given
+// nil: events at slog.LevelInfo, failures at slog.LevelWarn, and no bodies
+func DefaultLogOptions() *LogOptions {
+	return &LogOptions{Level: slog.LevelInfo, ErrorLevel: slog.LevelWarn}
+}
+
+// WithLogger logs the requests the client sends and the responses it
+// receives to logger, as structured events
+//
+//  Notes
+//    Passing a nil logger disables logging. opts may be nil (see
+//    DefaultLogOptions).
+//
+func (client *Client) WithLogger(logger *slog.Logger, opts *LogOptions) *Client {
+	if opts == nil {
+		opts = DefaultLogOptions()
+	}
+
+	client.logger = logger
+	client.logOptions = opts
+	return client
+}
+
+// logAndSend sends a request, logging it if the client has a logger
+func (client *Client) logAndSend(
+	ctx context.Context,
+	targetURL string,
+	params *SpellCheckParams,
+	headers *SpellCheckHeaders) (*SpellCheckResponse, error) {
+
+	if client.logger == nil {
+		return SpellCheckContext(ctx, client.httpClient, targetURL, params, headers)
+	}
+
+	logger, opts := client.logger, client.logOptions
+
+	method := http.MethodGet
+	if params.TotalTextLength() > MaxGetTextLength {
+		method = http.MethodPost
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("url", redactURL(targetURL)),
+		slog.Int("text_length", params.TotalTextLength()),
+		slog.String("market", params.Values.Get(MarketParam)),
+		slog.String("mode", params.Values.Get(ModeParam)),
+	}
+
+	logger.LogAttrs(ctx, opts.Level, "bing spell check request", attrs...)
+
+	if opts.LogBodies && logger.Enabled(ctx, slog.LevelDebug) {
+		logger.LogAttrs(ctx, slog.LevelDebug, "bing spell check request body",
+			slog.String("params", opts.redactParams(params.Values).Encode()),
+			slog.Any("headers", headers.redacted(opts.RedactHeaders...)))
+	}
+
+	start := time.Now()
+	scr, err := SpellCheckContext(ctx, client.httpClient, targetURL, params, headers)
+	latency := time.Since(start)
+
+	switch {
+	case err != nil:
+		logger.LogAttrs(ctx, opts.ErrorLevel, "bing spell check failed",
+			append(attrs, slog.Duration("latency", latency), slog.String("error", redactError(err)))...)
+
+	case scr.IsErrorResponse():
+		attrs = append(attrs, responseAttrs(scr, latency)...)
+		if len(scr.Errors) > 0 {
+			attrs = append(attrs,
+				slog.String("code", scr.Errors[0].Code),
+				slog.String("sub_code", scr.Errors[0].SubCode))
+		}
+		logger.LogAttrs(ctx, opts.ErrorLevel, "bing spell check error response", attrs...)
+
+	default:
+		attrs = append(attrs, responseAttrs(scr, latency)...)
+		attrs = append(attrs, slog.Int("flagged_tokens", len(scr.FlaggedTokens)))
+		logger.LogAttrs(ctx, opts.Level, "bing spell check response", attrs...)
+	}
+
+	if err == nil && opts.LogBodies && logger.Enabled(ctx, slog.LevelDebug) {
+		if body, jsonErr := json.Marshal(opts.redactResponse(scr)); jsonErr == nil {
+			logger.LogAttrs(ctx, slog.LevelDebug, "bing spell check response body", slog.String("body", string(body)))
+		}
+	}
+
+	return scr, err
+}
+
+func responseAttrs(scr *SpellCheckResponse, latency time.Duration) []slog.Attr {
+	attrs := []slog.Attr{slog.Duration("latency", latency)}
+	if scr.Meta != nil {
+		attrs = append(attrs,
+			slog.Int("status", scr.Meta.StatusCode),
+			slog.String("trace_id", scr.Meta.TraceID))
+	}
+
+	return attrs
+}
+
+// redactText returns text as RedactText returns it, or redacted if there is
+// no RedactText
+func (opts *LogOptions) redactText(text string) string {
+	if opts.RedactText != nil {
+		return opts.RedactText(text)
+	}
+
+	return redactedValue
+}
+
+// redactParams returns a copy of values with the text, context and
+// RedactParams parameters redacted
+func (opts *LogOptions) redactParams(values url.Values) url.Values {
+	redacted := url.Values{}
+
+	for param, v := range values {
+		redacted[param] = append([]string(nil), v...)
+	}
+
+	for _, param := range []string{TextParam, PreContextTextParam, PostContextTextParam} {
+		for i, text := range redacted[param] {
+			redacted[param][i] = opts.redactText(text)
+		}
+	}
+
+	for _, param := range opts.RedactParams {
+		for i := range redacted[param] {
+			redacted[param][i] = redactedValue
+		}
+	}
+
+	return redacted
+}
+
+// redactResponse returns a copy of scr with the flagged tokens and their
+// suggestions, which are parts of the checked text, redacted
+func (opts *LogOptions) redactResponse(scr *SpellCheckResponse) *SpellCheckResponse {
+	redacted := scr.Clone()
+
+	for i := range redacted.FlaggedTokens {
+		token := &redacted.FlaggedTokens[i]
+		token.Token = opts.redactText(token.Token)
+		for j := range token.Suggestions {
+			token.Suggestions[j].Suggestion = opts.redactText(token.Suggestions[j].Suggestion)
+		}
+	}
+
+	return redacted
+}
+
+// redactURL removes the query and any user info from rawURL
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// redactError returns the message of err without the query of the URL of a
+// *url.Error, which holds the text of a GET request
+func redactError(err error) string {
+	if urlErr, ok := err.(*url.Error); ok {
+		redacted := *urlErr
+		redacted.URL = redactURL(urlErr.URL)
+		return redacted.Error()
+	}
+
+	return err.Error()
+}
+
+// alwaysRedactedHeaders are the subscription key and the headers that
+// identify or locate the user
+var alwaysRedactedHeaders = []string{SubscriptionKeyHeader, ClientIDHeader, ClientIPHeader, SearchLocationHeader}
+
+// redacted returns a copy of the headers as a map, with the subscription
+// key, the user's ID, IP address and location, and any additional headers
+// redacted
+func (sch *SpellCheckHeaders) redacted(headers ...string) map[string]string {
+	secret := map[string]bool{}
+	for _, header := range alwaysRedactedHeaders {
+		secret[http.CanonicalHeaderKey(header)] = true
+	}
+	for _, header := range headers {
+		secret[http.CanonicalHeaderKey(header)] = true
+	}
+
+	values := make(map[string]string, len(sch.Headers))
+	for header := range sch.Headers {
+		if secret[http.CanonicalHeaderKey(header)] {
+			values[header] = redact(sch.Headers.Get(header))
+		} else {
+			values[header] = sch.Headers.Get(header)
+		}
+	}
+
+	return values
+}
+
+// LogValue implements slog.LogValuer so logging SpellCheckHeaders never
+// reveals the subscription key or the user's ID, IP address and location
+func (sch *SpellCheckHeaders) LogValue() slog.Value {
+	return slog.AnyValue(sch.redacted())
+}
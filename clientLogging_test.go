@@ -0,0 +1,139 @@
+package bingSpellCheck
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	loggingKey      = "secret-subscription-key"
+	loggingClientID = "secret-client-id"
+	loggingClientIP = "203.0.113.7"
+	loggingLocation = "lat:47.6;long:-122.3;re:22"
+	loggingText     = "privatetext mispeled"
+)
+
+// loggingSecrets must never appear in the log
+var loggingSecrets = []string{loggingKey, loggingClientID, loggingClientIP, loggingLocation, "privatetext", "mispeled", "misspelled"}
+
+func loggingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ClientIDHeader, loggingClientID)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"_type":"SpellCheck","flaggedTokens":[` +
+			`{"offset":12,"token":"mispeled","type":"UnknownToken","suggestions":[{"suggestion":"misspelled","score":0.9}]}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestClientLoggingRedaction(t *testing.T) {
+	srv := loggingServer(t)
+
+	tests := []struct {
+		name     string
+		endpoint string
+		opts     *LogOptions
+		want     []string
+	}{
+		{
+			name:     "defaults",
+			endpoint: srv.URL,
+			opts:     nil,
+			want:     []string{"bing spell check response", "text_length=20"},
+		},
+		{
+			name:     "bodies",
+			endpoint: srv.URL,
+			opts:     &LogOptions{LogBodies: true},
+			want:     []string{"response body", `\"token\":\"REDACTED\"`, `\"suggestion\":\"REDACTED\"`, "text=REDACTED"},
+		},
+		{
+			name:     "bodies with RedactText",
+			endpoint: srv.URL,
+			opts:     &LogOptions{LogBodies: true, RedactText: func(text string) string { return strconv.Itoa(len(text)) + " chars" }},
+			want:     []string{`\"token\":\"8 chars\"`, `\"suggestion\":\"10 chars\"`, "text=20+chars"},
+		},
+		{
+			name:     "failed request",
+			endpoint: "http://127.0.0.1:1/bing/v7.0/spellcheck",
+			opts:     &LogOptions{LogBodies: true},
+			want:     []string{"bing spell check failed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			client := NewClient(loggingKey).WithEndpoint(tt.endpoint).WithLogger(logger, tt.opts)
+			client.Headers.WithClientID(loggingClientID)
+			client.Headers.SetHeader(ClientIPHeader, loggingClientIP)
+			client.Headers.SetHeader(SearchLocationHeader, loggingLocation)
+
+			client.Check(context.Background(), loggingText, nil)
+
+			got := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("log does not contain %q:\n%s", want, got)
+				}
+			}
+			for _, secret := range loggingSecrets {
+				if strings.Contains(got, secret) {
+					t.Errorf("log contains %q:\n%s", secret, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSpellCheckHeadersLogValue(t *testing.T) {
+	headers := NewSpellCheckHeaders(loggingKey).WithClientID(loggingClientID)
+	headers.SetHeader(ClientIPHeader, loggingClientIP)
+	headers.SetHeader(SearchLocationHeader, loggingLocation)
+	headers.SetHeader(UserAgentHeader, "agent")
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("headers", "headers", headers)
+
+	got := buf.String()
+	if !strings.Contains(got, "agent") {
+		t.Errorf("log does not contain the user agent: %s", got)
+	}
+	for _, secret := range loggingSecrets {
+		if strings.Contains(got, secret) {
+			t.Errorf("log contains %q: %s", secret, got)
+		}
+	}
+}
+
+func TestRedactParams(t *testing.T) {
+	params := NewSpellCheckParams().WithTextAndContext(loggingText, "before", "after")
+	params.SetParam(UserIDParam, "user")
+	params.SetParam(MarketParam, "en-US")
+
+	redacted := (&LogOptions{RedactParams: []string{UserIDParam}}).redactParams(params.Values)
+
+	for _, param := range []string{TextParam, PreContextTextParam, PostContextTextParam, UserIDParam} {
+		if got := redacted.Get(param); got != redactedValue {
+			t.Errorf("%s = %q, want it redacted", param, got)
+		}
+	}
+	if got := redacted.Get(MarketParam); got != "en-US" {
+		t.Errorf("%s = %q", MarketParam, got)
+	}
+	if params.Values.Get(TextParam) != loggingText {
+		t.Error("the params were changed")
+	}
+}
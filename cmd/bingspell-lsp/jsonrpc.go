@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// message is a JSON-RPC request, notification or response
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+// isResponse determines if the message is a response to a request we sent
+func (msg *message) isResponse() bool {
+	return msg.Method == "" && msg.ID != nil
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (err *responseError) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", err.Code, err.Message)
+}
+
+// result is a successful response; unlike message, its result is always
+// present (e.g. null for shutdown)
+type result struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result"`
+}
+
+type failure struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Error   *responseError   `json:"error"`
+}
+
+type request struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      *int64      `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// conn is a JSON-RPC connection using the LSP base protocol
+// (Content-Length framed messages)
+type conn struct {
+	r *textproto.Reader
+	w io.Writer
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[string]chan *message
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{
+		r:       textproto.NewReader(bufio.NewReader(r)),
+		w:       w,
+		pending: map[string]chan *message{},
+	}
+}
+
+// read reads the next message
+func (c *conn) read() (*message, error) {
+	header, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+	}
+
+	body := make([]byte, length)
+	if _, err = io.ReadFull(c.r.R, body); err != nil {
+		return nil, err
+	}
+
+	msg := &message{}
+	if err = json.Unmarshal(body, msg); err != nil {
+		return nil, &responseError{Code: codeParseError, Message: err.Error()}
+	}
+
+	return msg, nil
+}
+
+// write writes a message
+func (c *conn) write(v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if _, err = fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+// reply responds to the request with id
+func (c *conn) reply(id *json.RawMessage, v interface{}, err error) error {
+	if err == nil {
+		return c.write(&result{JSONRPC: "2.0", ID: id, Result: v})
+	}
+
+	rpcErr, ok := err.(*responseError)
+	if !ok {
+		rpcErr = &responseError{Code: codeInternalError, Message: err.Error()}
+	}
+
+	return c.write(&failure{JSONRPC: "2.0", ID: id, Error: rpcErr})
+}
+
+// notify sends a notification
+func (c *conn) notify(method string, params interface{}) error {
+	return c.write(&request{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// call sends a request and waits for its response, which must be passed to
+// deliver by the read loop
+func (c *conn) call(ctx context.Context, method string, params, v interface{}) error {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	done := make(chan *message, 1)
+	c.pending[strconv.FormatInt(id, 10)] = done
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, strconv.FormatInt(id, 10))
+		c.mu.Unlock()
+	}()
+
+	if err := c.write(&request{JSONRPC: "2.0", ID: &id, Method: method, Params: params}); err != nil {
+		return err
+	}
+
+	select {
+	case msg := <-done:
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if v == nil {
+			return nil
+		}
+		return json.Unmarshal(msg.Result, v)
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliver passes a response to the call waiting for it
+func (c *conn) deliver(msg *message) {
+	c.mu.Lock()
+	done, ok := c.pending[string(*msg.ID)]
+	c.mu.Unlock()
+
+	if ok {
+		done <- msg
+	}
+}
@@ -0,0 +1,66 @@
+// Command bingspell-lsp is a Language Server Protocol server that publishes
+// spelling findings in Markdown, plain text, source code comments and commit
+// messages as diagnostics
+//
+//  Usage
+//    BING_SPELL_CHECK_KEY=<key> bingspell-lsp [-endpoint url]
+//
+//  Notes
+//    The server speaks LSP over stdin and stdout and logs to stderr. Each
+//    suggestion is offered as a quick fix, along with "add to dictionary".
+//    Settings are read from the "bingspell" section of the workspace
+//    configuration (see Settings). The project config (.bingspell.json at
+//    the workspace root, see lint.Config) is read at startup, and inline
+//    directives such as bingspell:ignore are applied.
+//
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// keyEnv is the environment variable that holds the subscription key
+const keyEnv = "BING_SPELL_CHECK_KEY"
+
+func main() {
+	endpoint := flag.String("endpoint", "", "the Bing spell check URL (optional)")
+	flag.Parse()
+
+	log.SetOutput(os.Stderr)
+	log.SetPrefix("bingspell-lsp: ")
+
+	key := os.Getenv(keyEnv)
+	if key == "" {
+		log.Fatalf("$%s is not set", keyEnv)
+	}
+
+	client := bingSpellCheck.NewClient(key)
+	if *endpoint != "" {
+		client.WithEndpoint(*endpoint)
+	}
+
+	if err := newServer(client, os.Stdin, os.Stdout).run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// appendWords appends words to a dictionary file, creating it if needed
+func appendWords(path string, words []string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	for _, word := range words {
+		if _, err = f.WriteString(word + "\n"); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,636 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotomgo/bingSpellCheck"
+	"github.com/gotomgo/bingSpellCheck/lint"
+)
+
+// addToDictionaryCommand adds its argument to the user dictionary
+const addToDictionaryCommand = "bingspell.addToDictionary"
+
+// defaultDebounce is the default time to wait after a change before
+// checking a document
+const defaultDebounce = 500 * time.Millisecond
+
+// Settings are read from the "bingspell" section of the workspace
+// configuration
+//
+//  Fields
+//    Market         - The market to check against (optional)
+//    Mode           - ProofMode or SpellMode (optional)
+//    Dictionary     - Words that are never flagged as unknown
+//    DictionaryFile - A file of words, one per line, that "add to
+//      dictionary" appends to (optional; without it added words are
+//      forgotten on exit)
+//    Debounce       - Milliseconds to wait after a change before checking
+//
+type Settings struct {
+	Market         bingSpellCheck.MarketCode `json:"market"`
+	Mode           string                    `json:"mode"`
+	Dictionary     []string                  `json:"dictionary"`
+	DictionaryFile string                    `json:"dictionaryFile"`
+	Debounce       int                       `json:"debounce"`
+}
+
+func (settings *Settings) debounce() time.Duration {
+	if settings.Debounce <= 0 {
+		return defaultDebounce
+	}
+
+	return time.Duration(settings.Debounce) * time.Millisecond
+}
+
+// document is an open text document
+//
+//  Notes
+//    findings are the flagged tokens of checked, the text of the version
+//    that was checked; they are only used while it is the current version
+//
+type document struct {
+	uri        string
+	languageID string
+	version    int
+	text       string
+
+	timer  *time.Timer
+	cancel context.CancelFunc
+
+	checked        string
+	checkedVersion int
+	findings       []bingSpellCheck.FlaggedToken
+}
+
+// server is a language server that publishes spelling diagnostics
+type server struct {
+	conn       *conn
+	checker    bingSpellCheck.Checker
+	dictionary *bingSpellCheck.DictionaryChecker
+
+	mu           sync.Mutex
+	settings     Settings
+	fileWords    []string
+	docs         map[string]*document
+	pullSettings bool
+	shutdown     bool
+
+	// root is the directory of the workspace, and project its project
+	// config (nil when there is none)
+	root    string
+	project *lint.Config
+}
+
+// newServer creates a server that checks documents with client
+//
+//  Notes
+//    Responses are cached by segment (paragraph), so a change only sends
+//    the paragraphs it touched to Bing. The dictionary is applied after the
+//    cache, so checking again after it changes does not call Bing.
+//
+func newServer(client bingSpellCheck.Checker, r io.Reader, w io.Writer) *server {
+	cached := bingSpellCheck.NewCachedChecker(bingSpellCheck.NewRetryChecker(client, 3, time.Second), 10000, 0)
+	dictionary := bingSpellCheck.NewDictionaryChecker(cached)
+
+	return &server{
+		conn:       newConn(r, w),
+		checker:    bingSpellCheck.NewChunkedChecker(dictionary, 0),
+		dictionary: dictionary,
+		docs:       map[string]*document{},
+	}
+}
+
+// run handles messages until the client exits or the connection fails
+func (s *server) run() error {
+	for {
+		msg, err := s.conn.read()
+		if err == io.EOF {
+			return nil
+		}
+		if rpcErr, ok := err.(*responseError); ok {
+			log.Print(rpcErr)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.isResponse() {
+			s.conn.deliver(msg)
+			continue
+		}
+
+		if msg.Method == "exit" {
+			return nil
+		}
+
+		result, err := s.handle(msg)
+		if msg.ID != nil {
+			if replyErr := s.conn.reply(msg.ID, result, err); replyErr != nil {
+				return replyErr
+			}
+		} else if err != nil {
+			log.Printf("%s: %v", msg.Method, err)
+		}
+	}
+}
+
+// handle handles a request or notification and returns the result
+func (s *server) handle(msg *message) (interface{}, error) {
+	switch msg.Method {
+	case "initialize":
+		var params InitializeParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(&params), nil
+
+	case "initialized":
+		s.mu.Lock()
+		pull := s.pullSettings
+		s.mu.Unlock()
+		if pull {
+			go s.loadSettings()
+		}
+		return nil, nil
+
+	case "shutdown":
+		s.mu.Lock()
+		s.shutdown = true
+		for _, doc := range s.docs {
+			doc.stop()
+		}
+		s.mu.Unlock()
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		s.didOpen(&params)
+		return nil, nil
+
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.didChange(&params)
+
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		s.didClose(&params)
+		return nil, nil
+
+	case "textDocument/didSave":
+		return nil, nil
+
+	case "textDocument/codeAction":
+		var params CodeActionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.codeActions(&params), nil
+
+	case "workspace/didChangeConfiguration":
+		var params DidChangeConfigurationParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		s.didChangeConfiguration(&params)
+		return nil, nil
+
+	case "workspace/executeCommand":
+		var params ExecuteCommandParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.executeCommand(&params)
+	}
+
+	if msg.ID == nil || strings.HasPrefix(msg.Method, "$/") {
+		// unknown notifications are ignored
+		return nil, nil
+	}
+
+	return nil, &responseError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+}
+
+func unmarshalParams(msg *message, v interface{}) error {
+	if err := json.Unmarshal(msg.Params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	return nil
+}
+
+func (s *server) initialize(params *InitializeParams) *InitializeResult {
+	var settings Settings
+	if len(params.InitializationOptions) > 0 && json.Unmarshal(params.InitializationOptions, &settings) == nil {
+		s.applySettings(settings)
+	}
+
+	s.mu.Lock()
+	s.pullSettings = params.Capabilities.Workspace.Configuration
+	s.mu.Unlock()
+
+	s.loadProject(params.RootURI)
+
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync:       TextDocumentSyncOptions{OpenClose: true, Change: TextDocumentSyncKindIncremental},
+			CodeActionProvider:     CodeActionOptions{CodeActionKinds: []string{CodeActionKindQuickFix}},
+			ExecuteCommandProvider: ExecuteCommandOptions{Commands: []string{addToDictionaryCommand}},
+		},
+		ServerInfo: ServerInfo{Name: "bingspell-lsp"},
+	}
+}
+
+// loadProject loads the project config at the root of the workspace, if it
+// has one
+func (s *server) loadProject(rootURI string) {
+	root, ok := uriPath(rootURI)
+	if !ok {
+		return
+	}
+
+	path := filepath.Join(root, lint.DefaultConfigFile)
+	project, err := lint.LoadConfig(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("reading %s: %v", path, err)
+		}
+		project = nil
+	}
+
+	s.mu.Lock()
+	s.root, s.project = root, project
+	s.mu.Unlock()
+}
+
+// projectPath returns the path of uri relative to the workspace root, with
+// forward slashes, as the project config expects
+func (s *server) projectPath(uri string) string {
+	path, ok := uriPath(uri)
+	if !ok || s.root == "" {
+		return path
+	}
+
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil {
+		return path
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// uriPath returns the file system path of a file URI
+func uriPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+
+	return filepath.FromSlash(u.Path), true
+}
+
+// loadSettings requests the "bingspell" section of the workspace
+// configuration and checks the open documents again
+func (s *server) loadSettings() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var sections []Settings
+	err := s.conn.call(ctx, "workspace/configuration",
+		&ConfigurationParams{Items: []ConfigurationItem{{Section: "bingspell"}}}, &sections)
+	if err != nil {
+		log.Printf("workspace/configuration: %v", err)
+		return
+	}
+
+	if len(sections) > 0 {
+		s.applySettings(sections[0])
+		s.checkAll()
+	}
+}
+
+func (s *server) didChangeConfiguration(params *DidChangeConfigurationParams) {
+	var settings struct {
+		Bingspell *Settings `json:"bingspell"`
+	}
+
+	if json.Unmarshal(params.Settings, &settings) == nil && settings.Bingspell != nil {
+		s.applySettings(*settings.Bingspell)
+		s.checkAll()
+		return
+	}
+
+	s.mu.Lock()
+	pull := s.pullSettings
+	s.mu.Unlock()
+	if pull {
+		go s.loadSettings()
+	}
+}
+
+// applySettings replaces the settings, updating the dictionary
+func (s *server) applySettings(settings Settings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dictionary.RemoveWords(s.settings.Dictionary...)
+	s.dictionary.RemoveWords(s.fileWords...)
+
+	s.settings = settings
+	s.fileWords = nil
+	if settings.DictionaryFile != "" {
+		words, err := lint.ReadDictionary(settings.DictionaryFile)
+		if err != nil && !os.IsNotExist(err) {
+			log.Printf("reading %s: %v", settings.DictionaryFile, err)
+		}
+		s.fileWords = words
+	}
+
+	s.dictionary.AddWords(settings.Dictionary...)
+	s.dictionary.AddWords(s.fileWords...)
+}
+
+func (s *server) didOpen(params *DidOpenTextDocumentParams) {
+	item := params.TextDocument
+	doc := &document{uri: item.URI, languageID: item.LanguageID, version: item.Version, text: item.Text}
+
+	s.mu.Lock()
+	s.docs[item.URI] = doc
+	s.mu.Unlock()
+
+	go s.check(item.URI, item.Version)
+}
+
+func (s *server) didChange(params *DidChangeTextDocumentParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return fmt.Errorf("document not open: %s", params.TextDocument.URI)
+	}
+
+	for _, change := range params.ContentChanges {
+		if change.Range == nil {
+			doc.text = change.Text
+			continue
+		}
+
+		index := lint.NewLineIndex(doc.text)
+		start, end := index.Offset(change.Range.Start), index.Offset(change.Range.End)
+		if end < start {
+			start, end = end, start
+		}
+		doc.text = doc.text[:start] + change.Text + doc.text[end:]
+	}
+	doc.version = params.TextDocument.Version
+
+	// debounce: check once the document stops changing
+	doc.stop()
+	uri, version := doc.uri, doc.version
+	doc.timer = time.AfterFunc(s.settings.debounce(), func() { s.check(uri, version) })
+
+	return nil
+}
+
+func (s *server) didClose(params *DidCloseTextDocumentParams) {
+	s.mu.Lock()
+	if doc, ok := s.docs[params.TextDocument.URI]; ok {
+		doc.stop()
+		delete(s.docs, params.TextDocument.URI)
+	}
+	s.mu.Unlock()
+
+	s.publish(params.TextDocument.URI, 0, []Diagnostic{})
+}
+
+// stop cancels any pending or running check, and must be called with the
+// server's mu held
+func (doc *document) stop() {
+	if doc.timer != nil {
+		doc.timer.Stop()
+		doc.timer = nil
+	}
+	if doc.cancel != nil {
+		doc.cancel()
+		doc.cancel = nil
+	}
+}
+
+// checkAll checks every open document again
+func (s *server) checkAll() {
+	s.mu.Lock()
+	docs := make(map[string]int, len(s.docs))
+	for uri, doc := range s.docs {
+		doc.stop()
+		docs[uri] = doc.version
+	}
+	s.mu.Unlock()
+
+	for uri, version := range docs {
+		go s.check(uri, version)
+	}
+}
+
+// check checks version of a document and publishes its diagnostics, unless
+// the document changes in the meantime
+func (s *server) check(uri string, version int) {
+	s.mu.Lock()
+	doc, ok := s.docs[uri]
+	if !ok || doc.version != version || s.shutdown {
+		s.mu.Unlock()
+		return
+	}
+
+	extract := lint.ExtractorForLanguage(doc.languageID)
+	if extract == nil {
+		extract = lint.ExtractorFor(uri)
+	}
+	if extract == nil {
+		s.mu.Unlock()
+		return
+	}
+
+	path := s.projectPath(uri)
+	if s.project.Ignored(path) {
+		s.mu.Unlock()
+		s.publish(uri, version, []Diagnostic{})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if doc.cancel != nil {
+		doc.cancel()
+	}
+	doc.cancel = cancel
+	text := doc.text
+	opts := &bingSpellCheck.CheckOptions{Market: s.settings.Market, Mode: s.settings.Mode, Tag: "lsp"}
+	checker, opts := s.project.Apply(path, s.checker, opts)
+	s.mu.Unlock()
+
+	defer cancel()
+
+	findings, err := lint.CheckText(ctx, checker, text, extract, opts)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("checking %s: %v", uri, err)
+		}
+		return
+	}
+
+	s.mu.Lock()
+	if doc.version != version || s.docs[uri] != doc {
+		s.mu.Unlock()
+		return
+	}
+	doc.checked, doc.checkedVersion, doc.findings = text, version, findings
+	s.mu.Unlock()
+
+	index := lint.NewLineIndex(text)
+	diagnostics := make([]Diagnostic, len(findings))
+	for i, token := range findings {
+		diagnostics[i] = diagnostic(index, token)
+	}
+
+	s.publish(uri, version, diagnostics)
+}
+
+func (s *server) publish(uri string, version int, diagnostics []Diagnostic) {
+	err := s.conn.notify("textDocument/publishDiagnostics",
+		&PublishDiagnosticsParams{URI: uri, Version: version, Diagnostics: diagnostics})
+	if err != nil {
+		log.Printf("publishing diagnostics: %v", err)
+	}
+}
+
+// diagnostic returns the diagnostic for a flagged token
+func diagnostic(index *lint.LineIndex, token bingSpellCheck.FlaggedToken) Diagnostic {
+	return Diagnostic{
+		Range:    tokenRange(index, token.Offset, token.Offset+len(token.Token)),
+		Severity: DiagnosticSeverityInformation,
+		Code:     token.Type,
+		Source:   "bingspell",
+		Message:  lint.Message(token),
+	}
+}
+
+func tokenRange(index *lint.LineIndex, start, end int) Range {
+	return Range{Start: index.Position(start), End: index.Position(end)}
+}
+
+// codeActions returns the suggestions and "add to dictionary" actions for
+// the findings in the requested range
+func (s *server) codeActions(params *CodeActionParams) []CodeAction {
+	s.mu.Lock()
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok || doc.checkedVersion != doc.version {
+		s.mu.Unlock()
+		return []CodeAction{}
+	}
+	text, findings := doc.checked, doc.findings
+	s.mu.Unlock()
+
+	index := lint.NewLineIndex(text)
+	start, end := index.Offset(params.Range.Start), index.Offset(params.Range.End)
+
+	actions := []CodeAction{}
+	for _, token := range findings {
+		tokenEnd := token.Offset + len(token.Token)
+		if tokenEnd < start || token.Offset > end || s.dictionary.Contains(token.Token) {
+			continue
+		}
+
+		diag := diagnostic(index, token)
+		edit := func(start, end int, newText string) *WorkspaceEdit {
+			return &WorkspaceEdit{Changes: map[string][]TextEdit{
+				params.TextDocument.URI: {{Range: tokenRange(index, start, end), NewText: newText}},
+			}}
+		}
+
+		if token.IsRepeatedToken() {
+			// remove the repeated word and the space after it
+			removeEnd := tokenEnd
+			if removeEnd < len(text) && text[removeEnd] == ' ' {
+				removeEnd++
+			}
+			actions = append(actions, CodeAction{
+				Title:       fmt.Sprintf("Remove repeated %q", token.Token),
+				Kind:        CodeActionKindQuickFix,
+				Diagnostics: []Diagnostic{diag},
+				IsPreferred: true,
+				Edit:        edit(token.Offset, removeEnd, ""),
+			})
+			continue
+		}
+
+		for i, suggestion := range token.Suggestions {
+			actions = append(actions, CodeAction{
+				Title:       fmt.Sprintf("Change to %q", suggestion.Suggestion),
+				Kind:        CodeActionKindQuickFix,
+				Diagnostics: []Diagnostic{diag},
+				IsPreferred: i == 0,
+				Edit:        edit(token.Offset, tokenEnd, suggestion.Suggestion),
+			})
+		}
+
+		actions = append(actions, CodeAction{
+			Title:       fmt.Sprintf("Add %q to dictionary", token.Token),
+			Kind:        CodeActionKindQuickFix,
+			Diagnostics: []Diagnostic{diag},
+			Command:     &Command{Title: "Add to dictionary", Command: addToDictionaryCommand, Arguments: []interface{}{token.Token}},
+		})
+	}
+
+	return actions
+}
+
+func (s *server) executeCommand(params *ExecuteCommandParams) error {
+	if params.Command != addToDictionaryCommand {
+		return &responseError{Code: codeInvalidParams, Message: "unknown command: " + params.Command}
+	}
+
+	var words []string
+	for _, arg := range params.Arguments {
+		var word string
+		if err := json.Unmarshal(arg, &word); err != nil || word == "" {
+			return &responseError{Code: codeInvalidParams, Message: "expected words to add"}
+		}
+		words = append(words, word)
+	}
+
+	s.mu.Lock()
+	s.dictionary.AddWords(words...)
+	path := s.settings.DictionaryFile
+	if path != "" {
+		s.fileWords = append(s.fileWords, words...)
+	}
+	s.mu.Unlock()
+
+	if path != "" {
+		if err := appendWords(path, words); err != nil {
+			return err
+		}
+	}
+
+	s.checkAll()
+	return nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// keyEnv is the environment variable that holds the subscription key when
+// it is not in the config file
+const keyEnv = "BING_SPELL_CHECK_KEY"
+
+// Config is the server configuration, read from a JSON file
+//
+//  Fields
+//    Listen          - The address to listen on (default ":8080")
+//    SubscriptionKey - The Bing subscription key (default $BING_SPELL_CHECK_KEY)
+//    Endpoint        - The Bing spell check URL (optional)
+//    Market          - The default market (optional)
+//    RatePerSecond   - The rate of requests sent to Bing, for all tenants
+//      (0 means unlimited)
+//    Burst           - The burst size of RatePerSecond
+//    Retries         - The number of attempts for failed requests (default 3)
+//    CacheSize       - The number of responses cached for all tenants (0
+//      disables caching)
+//    CacheTTL        - How long responses are cached (0 means until evicted)
+//    MaxBatch        - The maximum number of texts in a batch (default 100)
+//    Concurrency     - The number of texts of a batch checked at a time
+//      (default 4)
+//    RequestTimeout  - The maximum time to handle a request (default 30s)
+//    DrainPeriod     - How long the server keeps serving while reporting
+//      that it is not ready on shutdown, so load balancers stop sending it
+//      requests (default 5s)
+//    ShutdownTimeout - The time in flight requests get to finish on
+//      shutdown, after the DrainPeriod (default 30s)
+//    Ledger          - Where to record usage, and the monthly budgets
+//      (optional)
+//    Tenants         - The teams allowed to use the server
+//
+type Config struct {
+	Listen          string                    `json:"listen"`
+	SubscriptionKey string                    `json:"subscriptionKey"`
+	Endpoint        string                    `json:"endpoint"`
+	Market          bingSpellCheck.MarketCode `json:"market"`
+	RatePerSecond   float64                   `json:"ratePerSecond"`
+	Burst           int                       `json:"burst"`
+	Retries         int                       `json:"retries"`
+	CacheSize       int                       `json:"cacheSize"`
+	CacheTTL        Duration                  `json:"cacheTTL"`
+	MaxBatch        int                       `json:"maxBatch"`
+	Concurrency     int                       `json:"concurrency"`
+	RequestTimeout  Duration                  `json:"requestTimeout"`
+	DrainPeriod     Duration                  `json:"drainPeriod"`
+	ShutdownTimeout Duration                  `json:"shutdownTimeout"`
+	Ledger          *LedgerConfig             `json:"ledger"`
+	Tenants         []TenantConfig            `json:"tenants"`
+}
+
+// LedgerConfig configures the usage ledger (see bingSpellCheck.Ledger)
+type LedgerConfig struct {
+	Path       string `json:"path"`
+	SoftBudget int64  `json:"softBudget"`
+	HardBudget int64  `json:"hardBudget"`
+}
+
+// TenantConfig is a team allowed to use the server
+//
+//  Fields
+//    Name          - Identifies the tenant in logs, metrics and the ledger
+//    Tokens        - The API tokens of the tenant, sent as
+//      "Authorization: Bearer <token>"
+//    RatePerSecond - The rate of texts the tenant may check (0 means
+//      unlimited)
+//    Burst         - The burst size of RatePerSecond, which is also the
+//      largest batch the tenant may send
+//    Market        - The tenant's default market (optional)
+//    Dictionary    - Words that are never flagged as unknown for the tenant
+//
+type TenantConfig struct {
+	Name          string                    `json:"name"`
+	Tokens        []string                  `json:"tokens"`
+	RatePerSecond float64                   `json:"ratePerSecond"`
+	Burst         int                       `json:"burst"`
+	Market        bingSpellCheck.MarketCode `json:"market"`
+	Dictionary    []string                  `json:"dictionary"`
+}
+
+// Duration is a time.Duration that is a string such as "30s" in JSON
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON formats the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// LoadConfig reads the config file at path and applies defaults
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err = json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	config.setDefaults()
+
+	if err = config.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return config, nil
+}
+
+func (config *Config) setDefaults() {
+	if config.Listen == "" {
+		config.Listen = ":8080"
+	}
+	if config.SubscriptionKey == "" {
+		config.SubscriptionKey = os.Getenv(keyEnv)
+	}
+	if config.Retries <= 0 {
+		config.Retries = 3
+	}
+	if config.MaxBatch <= 0 {
+		config.MaxBatch = 100
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 4
+	}
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = Duration(30 * time.Second)
+	}
+	if config.DrainPeriod <= 0 {
+		config.DrainPeriod = Duration(5 * time.Second)
+	}
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = Duration(30 * time.Second)
+	}
+}
+
+func (config *Config) validate() error {
+	if config.SubscriptionKey == "" {
+		return fmt.Errorf("no subscriptionKey (or $%s)", keyEnv)
+	}
+	if len(config.Tenants) == 0 {
+		return fmt.Errorf("no tenants")
+	}
+
+	names := map[string]bool{}
+	tokens := map[string]bool{}
+	for _, tenant := range config.Tenants {
+		if tenant.Name == "" {
+			return fmt.Errorf("tenant without a name")
+		}
+		if names[tenant.Name] {
+			return fmt.Errorf("duplicate tenant %q", tenant.Name)
+		}
+		names[tenant.Name] = true
+
+		if len(tenant.Tokens) == 0 {
+			return fmt.Errorf("tenant %q has no tokens", tenant.Name)
+		}
+		for _, token := range tenant.Tokens {
+			if token == "" || tokens[token] {
+				return fmt.Errorf("tenant %q has an empty or duplicate token", tenant.Name)
+			}
+			tokens[token] = true
+		}
+	}
+
+	return nil
+}
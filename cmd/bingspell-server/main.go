@@ -0,0 +1,86 @@
+// Command bingspell-server exposes spell checking to internal teams as a
+// JSON REST API, so one managed Bing subscription key serves every team
+//
+//  Usage
+//    bingspell-server -config bingspell-server.json
+//
+//  Notes
+//    See Config for the configuration file and Server for the endpoints.
+//    On SIGINT or SIGTERM the server stops reporting ready, keeps serving
+//    for DrainPeriod, then waits up to ShutdownTimeout for in flight
+//    requests to finish, and saves the usage ledger. A second signal exits
+//    at once.
+//
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	configPath := flag.String("config", "bingspell-server.json", "the configuration file")
+	flag.Parse()
+
+	config, err := LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server, err := NewServer(ctx, config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	httpServer := &http.Server{
+		Addr:              config.Listen,
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Printf("listening on %s with %d tenants", config.Listen, len(config.Tenants))
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err = <-errs:
+		// e.g. the address is in use; the ledger is still saved
+		if closeErr := server.Close(); closeErr != nil {
+			log.Print(closeErr)
+		}
+		log.Fatal(err)
+	case <-ctx.Done():
+	}
+
+	// restore the default handling, so a second signal stops the server
+	stop()
+
+	log.Printf("shutting down, draining for %s", time.Duration(config.DrainPeriod))
+	server.Drain()
+	time.Sleep(time.Duration(config.DrainPeriod))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(config.ShutdownTimeout))
+	defer cancel()
+
+	// the ledger is saved even if requests are still in flight
+	err = httpServer.Shutdown(shutdownCtx)
+	if closeErr := server.Close(); closeErr != nil {
+		log.Print(closeErr)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("stopped")
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gotomgo/bingSpellCheck"
+	"github.com/gotomgo/bingSpellCheck/lint"
+)
+
+// maxBodyBytes limits the size of request bodies
+const maxBodyBytes = 1 << 20
+
+// retryBackoff is the delay before the first retry of a failed Bing request
+const retryBackoff = 500 * time.Millisecond
+
+// Server exposes spell checking to tenants as a JSON REST API
+//
+//  Notes
+//    Endpoints:
+//      POST /v1/check       - check a text, returns a SpellCheckResponse
+//      POST /v1/autocorrect - check a text and apply the first suggestions
+//      POST /v1/batch       - check several texts
+//      GET  /healthz        - 200 while the process is running
+//      GET  /readyz         - 200 until shutdown begins
+//      GET  /metrics        - Prometheus metrics
+//
+//    The /v1 endpoints require a tenant token. All tenants share the Bing
+//    key, its rate limit and the cache; dictionaries are applied per tenant
+//    after the cache. Tokens suppressed by inline bingspell: directives in
+//    a text (see lint.Directives) are neither reported nor corrected.
+//
+type Server struct {
+	config  *Config
+	tenants map[[sha256.Size]byte]*tenant
+	metrics *bingSpellCheck.PrometheusMetrics
+	ledger  *bingSpellCheck.Ledger
+	handler http.Handler
+
+	draining int32
+}
+
+type tenant struct {
+	name    string
+	market  bingSpellCheck.MarketCode
+	limiter *bingSpellCheck.RateLimiter
+	checker bingSpellCheck.Checker
+	batch   *bingSpellCheck.BatchChecker
+}
+
+// NewServer creates a Server for config
+func NewServer(ctx context.Context, config *Config) (*Server, error) {
+	server := &Server{
+		config:  config,
+		tenants: map[[sha256.Size]byte]*tenant{},
+		metrics: bingSpellCheck.NewPrometheusMetrics(),
+	}
+
+	client := bingSpellCheck.NewClient(config.SubscriptionKey).WithMetrics(server.metrics)
+	if config.Endpoint != "" {
+		client.WithEndpoint(config.Endpoint)
+	}
+	if config.Market != "" {
+		client.Params.WithMarket(config.Market)
+	}
+
+	if config.Ledger != nil {
+		var store bingSpellCheck.LedgerStore
+		if config.Ledger.Path != "" {
+			store = bingSpellCheck.NewFileLedgerStore(config.Ledger.Path)
+		}
+
+		ledger, err := bingSpellCheck.NewLedger(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+		ledger.SoftBudget = config.Ledger.SoftBudget
+		ledger.HardBudget = config.Ledger.HardBudget
+		ledger.OnSoftBudget = func(month string, used, budget int64) {
+			log.Printf("soft budget reached for %s: %d of %d transactions used", month, used, budget)
+		}
+		client.WithLedger(ledger)
+		server.ledger = ledger
+	}
+
+	// rate limit each attempt, and cache in front of the retries
+	var shared bingSpellCheck.Checker = client
+	if config.RatePerSecond > 0 {
+		shared = bingSpellCheck.NewRateLimitedChecker(shared, config.RatePerSecond, config.Burst).WithMetrics(server.metrics)
+	}
+	shared = bingSpellCheck.NewRetryChecker(shared, config.Retries, retryBackoff).WithMetrics(server.metrics)
+	if config.CacheSize > 0 {
+		shared = bingSpellCheck.NewCachedChecker(shared, config.CacheSize, time.Duration(config.CacheTTL)).WithMetrics(server.metrics)
+	}
+
+	for _, tc := range config.Tenants {
+		t := &tenant{name: tc.Name, market: tc.Market}
+		if t.market == "" {
+			t.market = config.Market
+		}
+		if tc.RatePerSecond > 0 {
+			t.limiter = bingSpellCheck.NewRateLimiter(tc.RatePerSecond, tc.Burst)
+		}
+
+		t.checker = bingSpellCheck.NewChunkedChecker(bingSpellCheck.NewDictionaryChecker(shared, tc.Dictionary...), 0)
+		t.batch = bingSpellCheck.NewBatchChecker(t.checker, config.Concurrency)
+
+		for _, token := range tc.Tokens {
+			server.tenants[sha256.Sum256([]byte(token))] = t
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/check", server.tenantHandler(server.handleCheck))
+	mux.HandleFunc("/v1/autocorrect", server.tenantHandler(server.handleAutoCorrect))
+	mux.HandleFunc("/v1/batch", server.tenantHandler(server.handleBatch))
+	mux.HandleFunc("/healthz", server.handleHealth)
+	mux.HandleFunc("/readyz", server.handleReady)
+	mux.Handle("/metrics", server.metrics)
+	server.handler = mux
+
+	return server, nil
+}
+
+// ServeHTTP implements http.Handler
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.handler.ServeHTTP(w, r)
+}
+
+// Drain makes the server report that it is not ready, so load balancers
+// stop sending it requests before it shuts down
+func (server *Server) Drain() {
+	atomic.StoreInt32(&server.draining, 1)
+}
+
+// Close saves the usage ledger, and must be called once the server has
+// shut down
+func (server *Server) Close() error {
+	if server.ledger == nil {
+		return nil
+	}
+
+	return server.ledger.Close()
+}
+
+// checkRequest is the body of /v1/check and /v1/autocorrect
+type checkRequest struct {
+	Text        string                    `json:"text"`
+	PreContext  string                    `json:"preContext"`
+	PostContext string                    `json:"postContext"`
+	Mode        string                    `json:"mode"`
+	Market      bingSpellCheck.MarketCode `json:"market"`
+}
+
+// batchRequest is the body of /v1/batch
+type batchRequest struct {
+	Texts  []string                  `json:"texts"`
+	Mode   string                    `json:"mode"`
+	Market bingSpellCheck.MarketCode `json:"market"`
+}
+
+type autoCorrectResponse struct {
+	Text          string                        `json:"text"`
+	FlaggedTokens []bingSpellCheck.FlaggedToken `json:"flaggedTokens"`
+}
+
+type batchResponse struct {
+	Results []batchItem `json:"results"`
+}
+
+type batchItem struct {
+	Response *bingSpellCheck.SpellCheckResponse `json:"response,omitempty"`
+	Error    *apiError                          `json:"error,omitempty"`
+}
+
+// apiError is the error body of a failed request
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type errorResponse struct {
+	Error *apiError `json:"error"`
+}
+
+// tenantHandler authenticates the tenant of a POST request and calls
+// handle with a context limited to the request timeout
+func (server *Server) tenantHandler(handle func(ctx context.Context, w http.ResponseWriter, r *http.Request, t *tenant)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "use POST")
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		t, ok := server.tenants[sha256.Sum256([]byte(token))]
+		if token == "" || !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "Unauthorized", "missing or unknown API token")
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(server.config.RequestTimeout))
+		defer cancel()
+
+		handle(ctx, w, r, t)
+	}
+}
+
+func (server *Server) handleCheck(ctx context.Context, w http.ResponseWriter, r *http.Request, t *tenant) {
+	var req checkRequest
+	if !decode(w, r, &req) || !t.allow(w, 1) {
+		return
+	}
+
+	scr, err := t.checker.Check(ctx, req.Text, t.options(req.PreContext, req.PostContext, req.Mode, req.Market))
+	if status, apiErr := failure(scr, err); apiErr != nil {
+		writeError(w, status, apiErr.Code, apiErr.Message)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, unsuppressed(req.Text, scr))
+}
+
+func (server *Server) handleAutoCorrect(ctx context.Context, w http.ResponseWriter, r *http.Request, t *tenant) {
+	var req checkRequest
+	if !decode(w, r, &req) || !t.allow(w, 1) {
+		return
+	}
+
+	scr, err := t.checker.Check(ctx, req.Text, t.options(req.PreContext, req.PostContext, req.Mode, req.Market))
+	if status, apiErr := failure(scr, err); apiErr != nil {
+		writeError(w, status, apiErr.Code, apiErr.Message)
+		return
+	}
+
+	scr = unsuppressed(req.Text, scr)
+	text, err := bingSpellCheck.BuildAutoCorrectedText(req.Text, scr)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "AutoCorrectFailed", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &autoCorrectResponse{Text: text, FlaggedTokens: scr.FlaggedTokens})
+}
+
+func (server *Server) handleBatch(ctx context.Context, w http.ResponseWriter, r *http.Request, t *tenant) {
+	var req batchRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	if len(req.Texts) > server.config.MaxBatch {
+		writeError(w, http.StatusRequestEntityTooLarge, "BatchTooLarge", "a batch has at most "+strconv.Itoa(server.config.MaxBatch)+" texts")
+		return
+	}
+	if !t.allow(w, len(req.Texts)) {
+		return
+	}
+
+	results := t.batch.CheckBatch(ctx, req.Texts, t.options("", "", req.Mode, req.Market))
+
+	resp := &batchResponse{Results: make([]batchItem, len(results))}
+	for i, result := range results {
+		if _, apiErr := failure(result.Response, result.Err); apiErr != nil {
+			resp.Results[i].Error = apiErr
+		} else {
+			resp.Results[i].Response = unsuppressed(req.Texts[i], result.Response)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// unsuppressed returns scr without the tokens suppressed by the inline
+// directives of text, copying it rather than changing a cached response
+func unsuppressed(text string, scr *bingSpellCheck.SpellCheckResponse) *bingSpellCheck.SpellCheckResponse {
+	tokens := lint.ParseDirectives(text).Filter(scr.FlaggedTokens)
+	if len(tokens) == len(scr.FlaggedTokens) {
+		return scr
+	}
+
+	filtered := scr.Clone()
+	filtered.FlaggedTokens = tokens
+	return filtered
+}
+
+func (server *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (server *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&server.draining) != 0 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "draining"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// allow consumes n texts of the tenant's rate limit, or responds with 429
+// (or 413 if n is more than the rate limit can ever allow at once)
+//
+//  Notes
+//    Each text of a batch counts as one request. A rejected batch consumes
+//    none of the rate limit.
+//
+func (t *tenant) allow(w http.ResponseWriter, n int) bool {
+	if t.limiter == nil {
+		return true
+	}
+
+	if burst := t.limiter.Burst(); n > burst {
+		writeError(w, http.StatusRequestEntityTooLarge, "BatchTooLarge",
+			"tenant "+t.name+" can check at most "+strconv.Itoa(burst)+" texts at once")
+		return false
+	}
+
+	if !t.limiter.AllowN(n) {
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusTooManyRequests, "RateLimitExceeded", "tenant "+t.name+" exceeded its rate limit")
+		return false
+	}
+
+	return true
+}
+
+// options returns the CheckOptions of a tenant's request
+//
+//  Notes
+//    Without a mode, SpellMode is used for markets that do not support
+//    ProofMode (the default mode of the Bing API)
+//
+func (t *tenant) options(preContext, postContext, mode string, market bingSpellCheck.MarketCode) *bingSpellCheck.CheckOptions {
+	if market == "" {
+		market = t.market
+	}
+	if mode == "" && market != "" && !market.SupportsMode(bingSpellCheck.ProofMode) {
+		mode = bingSpellCheck.SpellMode
+	}
+
+	return &bingSpellCheck.CheckOptions{
+		PreContext:  preContext,
+		PostContext: postContext,
+		Mode:        mode,
+		Market:      market,
+		Tag:         t.name,
+	}
+}
+
+// failure returns the status and error of a failed check, or a nil error
+func failure(scr *bingSpellCheck.SpellCheckResponse, err error) (int, *apiError) {
+	var validationErrs bingSpellCheck.ValidationErrors
+	var budgetErr *bingSpellCheck.BudgetExceededError
+
+	switch {
+	case errors.As(err, &validationErrs):
+		return http.StatusBadRequest, &apiError{Code: "InvalidRequest", Message: err.Error()}
+
+	case errors.As(err, &budgetErr):
+		return http.StatusServiceUnavailable, &apiError{Code: "BudgetExceeded", Message: err.Error()}
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, &apiError{Code: "Timeout", Message: err.Error()}
+
+	case err != nil:
+		return http.StatusBadGateway, &apiError{Code: "BingUnavailable", Message: err.Error()}
+
+	case scr.IsErrorResponse():
+		if len(scr.Errors) == 0 {
+			return http.StatusBadGateway, &apiError{Code: "BingError", Message: "error response without errors"}
+		}
+
+		bingErr := scr.Errors[0]
+		switch {
+		case bingErr.Code == bingSpellCheck.InvalidRequestErrorCode:
+			return http.StatusBadRequest, &apiError{Code: "InvalidRequest", Message: bingErr.Error()}
+		case bingErr.IsUnavailable():
+			return http.StatusServiceUnavailable, &apiError{Code: "BingUnavailable", Message: bingErr.Error()}
+		}
+		return http.StatusBadGateway, &apiError{Code: "BingError", Message: bingErr.Error()}
+	}
+
+	return http.StatusOK, nil
+}
+
+// decode decodes the JSON body of r into v, or responds with 400 (or 413
+// if the body is larger than maxBodyBytes)
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "BodyTooLarge", "a request body has at most "+strconv.Itoa(maxBodyBytes)+" bytes")
+			return false
+		}
+
+		writeError(w, http.StatusBadRequest, "InvalidJSON", err.Error())
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("writing response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, &errorResponse{Error: &apiError{Code: code, Message: message}})
+}
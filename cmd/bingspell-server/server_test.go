@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// bing is a fake Bing Spell Check API that flags every "teh" and "gotomgo"
+// and records the market of each request
+type bing struct {
+	mu      sync.Mutex
+	markets []string
+}
+
+func (b *bing) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	b.markets = append(b.markets, r.FormValue(bingSpellCheck.MarketParam))
+	b.mu.Unlock()
+
+	text := r.FormValue(bingSpellCheck.TextParam)
+	scr := &bingSpellCheck.SpellCheckResponse{Type: bingSpellCheck.SpellCheckResponseType, FlaggedTokens: []bingSpellCheck.FlaggedToken{}}
+
+	offset := 0
+	for _, word := range strings.Fields(text) {
+		offset += strings.Index(text[offset:], word)
+		switch word {
+		case "teh":
+			scr.FlaggedTokens = append(scr.FlaggedTokens, bingSpellCheck.FlaggedToken{
+				Offset: offset, Token: word, Type: bingSpellCheck.UnknownTokenType,
+				Suggestions: []bingSpellCheck.TokenSuggestion{{Suggestion: "the", Score: 0.9}},
+			})
+		case "gotomgo":
+			scr.FlaggedTokens = append(scr.FlaggedTokens, bingSpellCheck.FlaggedToken{
+				Offset: offset, Token: word, Type: bingSpellCheck.UnknownTokenType,
+			})
+		}
+		offset += len(word)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scr)
+}
+
+func (b *bing) lastMarket() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.markets[len(b.markets)-1]
+}
+
+// newTestServer returns a Server in front of a fake Bing, with the tenants
+// "docs" (token "docs-token", a dictionary and the en-GB market) and
+// "support" (token "support-token", at most 2 texts and then rate limited)
+func newTestServer(t *testing.T) (*httptest.Server, *Server, *bing) {
+	t.Helper()
+
+	fake := &bing{}
+	bingSrv := httptest.NewServer(fake)
+	t.Cleanup(bingSrv.Close)
+
+	config := &Config{
+		SubscriptionKey: "key",
+		Endpoint:        bingSrv.URL,
+		MaxBatch:        3,
+		Tenants: []TenantConfig{
+			{Name: "docs", Tokens: []string{"docs-token"}, Market: bingSpellCheck.MktUnitedKingdom, Dictionary: []string{"gotomgo"}},
+			{Name: "support", Tokens: []string{"support-token"}, RatePerSecond: 0.001, Burst: 2},
+		},
+	}
+	config.setDefaults()
+	if err := config.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := NewServer(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	return srv, server, fake
+}
+
+// post sends body to path with token, and decodes the response into v
+func post(t *testing.T, srv *httptest.Server, path, token, body string, v interface{}) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return resp
+}
+
+func TestServerAuthentication(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		status int
+		code   string
+	}{
+		{"no token", http.MethodPost, "/v1/check", "", http.StatusUnauthorized, "Unauthorized"},
+		{"unknown token", http.MethodPost, "/v1/check", "other-token", http.StatusUnauthorized, "Unauthorized"},
+		{"wrong method", http.MethodGet, "/v1/check", "docs-token", http.StatusMethodNotAllowed, "MethodNotAllowed"},
+		{"unknown path", http.MethodPost, "/v1/grammar", "docs-token", http.StatusNotFound, ""},
+		{"valid token", http.MethodPost, "/v1/check", "docs-token", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(`{"text":"text"}`))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if tt.code != "" {
+				var body errorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == nil || body.Error.Code != tt.code {
+					t.Errorf("got %+v, %v; want a %s error", body.Error, err, tt.code)
+				}
+			}
+		})
+	}
+}
+
+func TestServerTenants(t *testing.T) {
+	srv, _, fake := newTestServer(t)
+	const text = "teh gotomgo docs"
+
+	tests := []struct {
+		name   string
+		token  string
+		body   string
+		tokens []string
+		market string
+	}{
+		{"dictionary and market", "docs-token", `{"text":"` + text + `"}`, []string{"teh"}, "en-GB"},
+		{"no dictionary", "support-token", `{"text":"` + text + `"}`, []string{"teh", "gotomgo"}, ""},
+		{"market in the request", "docs-token", `{"text":"` + text + `","market":"en-US"}`, []string{"teh"}, "en-US"},
+		{"inline directive", "docs-token", `{"text":"teh gotomgo <!-- bingspell:ignore teh -->"}`, nil, "en-GB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scr bingSpellCheck.SpellCheckResponse
+			if resp := post(t, srv, "/v1/check", tt.token, tt.body, &scr); resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d", resp.StatusCode)
+			}
+
+			var tokens []string
+			for _, token := range scr.FlaggedTokens {
+				tokens = append(tokens, token.Token)
+			}
+			if strings.Join(tokens, ",") != strings.Join(tt.tokens, ",") {
+				t.Errorf("flagged %q, want %q", tokens, tt.tokens)
+			}
+			if got := fake.lastMarket(); got != tt.market {
+				t.Errorf("sent market %q, want %q", got, tt.market)
+			}
+		})
+	}
+}
+
+func TestServerAutoCorrect(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+
+	var body autoCorrectResponse
+	if resp := post(t, srv, "/v1/autocorrect", "docs-token", `{"text":"Is teh gotomgo data good?"}`, &body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	if body.Text != "Is the gotomgo data good?" || len(body.FlaggedTokens) != 1 {
+		t.Errorf("got %+v", body)
+	}
+}
+
+func TestServerQuota(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		body   string
+		status int
+		code   string
+	}{
+		{"batch over MaxBatch", "/v1/batch", "docs-token", `{"texts":["a","b","c","d"]}`, http.StatusRequestEntityTooLarge, "BatchTooLarge"},
+		{"batch over the tenant's burst", "/v1/batch", "support-token", `{"texts":["a","b","c"]}`, http.StatusRequestEntityTooLarge, "BatchTooLarge"},
+		{"batch within the burst", "/v1/batch", "support-token", `{"texts":["a","teh"]}`, http.StatusOK, ""},
+		{"rate limited", "/v1/check", "support-token", `{"text":"a"}`, http.StatusTooManyRequests, "RateLimitExceeded"},
+		{"other tenants are not limited", "/v1/check", "docs-token", `{"text":"a"}`, http.StatusOK, ""},
+		{"invalid json", "/v1/check", "docs-token", `{"txt":"a"}`, http.StatusBadRequest, "InvalidJSON"},
+		{"body too large", "/v1/check", "docs-token", `{"text":"` + strings.Repeat("a", maxBodyBytes) + `"}`, http.StatusRequestEntityTooLarge, "BodyTooLarge"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body struct {
+				Error   *apiError   `json:"error"`
+				Results []batchItem `json:"results"`
+			}
+
+			resp := post(t, srv, tt.path, tt.token, tt.body, &body)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d (%+v)", resp.StatusCode, tt.status, body.Error)
+			}
+			if tt.code != "" && (body.Error == nil || body.Error.Code != tt.code) {
+				t.Errorf("got %+v, want a %s error", body.Error, tt.code)
+			}
+			if tt.status == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+				t.Error("no Retry-After header")
+			}
+		})
+	}
+}
+
+func TestServerBatch(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+
+	var body batchResponse
+	if resp := post(t, srv, "/v1/batch", "docs-token", `{"texts":["teh","fine",""]}`, &body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+
+	if len(body.Results) != 3 {
+		t.Fatalf("got %d results", len(body.Results))
+	}
+	if r := body.Results[0].Response; r == nil || len(r.FlaggedTokens) != 1 {
+		t.Errorf("result 0 = %+v", body.Results[0])
+	}
+	for i := 1; i < 3; i++ {
+		if r := body.Results[i].Response; r == nil || len(r.FlaggedTokens) != 0 {
+			t.Errorf("result %d = %+v", i, body.Results[i])
+		}
+	}
+}
+
+func TestServerDrain(t *testing.T) {
+	srv, server, _ := newTestServer(t)
+
+	get := func(path string) int {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if got := get("/readyz"); got != http.StatusOK {
+		t.Errorf("readyz = %d before Drain", got)
+	}
+
+	server.Drain()
+
+	if got := get("/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("readyz = %d after Drain", got)
+	}
+	if got := get("/healthz"); got != http.StatusOK {
+		t.Errorf("healthz = %d after Drain", got)
+	}
+
+	// requests are still served while draining
+	if resp := post(t, srv, "/v1/check", "docs-token", `{"text":"a"}`, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("check = %d while draining", resp.StatusCode)
+	}
+
+	if err := server.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	config := &Config{DrainPeriod: Duration(time.Second)}
+	config.setDefaults()
+
+	if config.Listen != ":8080" || config.Retries != 3 || config.MaxBatch != 100 || config.Concurrency != 4 {
+		t.Errorf("got %+v", config)
+	}
+	if time.Duration(config.DrainPeriod) != time.Second {
+		t.Errorf("DrainPeriod = %s, want the configured 1s", time.Duration(config.DrainPeriod))
+	}
+	if time.Duration(config.ShutdownTimeout) != 30*time.Second {
+		t.Errorf("ShutdownTimeout = %s", time.Duration(config.ShutdownTimeout))
+	}
+
+	config = &Config{}
+	config.setDefaults()
+	if time.Duration(config.DrainPeriod) != 5*time.Second {
+		t.Errorf("default DrainPeriod = %s", time.Duration(config.DrainPeriod))
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gotomgo/bingSpellCheck/lint"
+)
+
+// runCrawl checks every supported file of a directory tree and returns the
+// number of findings it printed
+func runCrawl(ctx context.Context, cf *checkFlags, args []string) (int, error) {
+	fs := newFlagSet("crawl", cf)
+	checkpointPath := fs.String("checkpoint", "", "the checkpoint file, to resume an interrupted crawl (optional)")
+	resultsPath := fs.String("results", "", "a file to append each file's result to, as JSON lines (optional)")
+	batchSize := fs.Int("batch", lint.DefaultCrawlBatchSize, "the number of paragraphs checked per batch")
+	concurrency := fs.Int("concurrency", 4, "the number of checks made at a time")
+	if err := cf.parse(ctx, fs, args); err != nil {
+		return 0, err
+	}
+	if fs.NArg() != 1 {
+		return 0, fmt.Errorf("crawl needs a directory")
+	}
+
+	// paths (and the config and baseline) are relative to the crawled directory
+	dir, err := filepath.Abs(fs.Arg(0))
+	if err != nil {
+		return 0, err
+	}
+	cf.root = dir
+	if err = cf.loadProject(); err != nil {
+		return 0, err
+	}
+
+	baseline, err := cf.readBaseline()
+	if err != nil {
+		return 0, err
+	}
+
+	checker, opts, err := cf.checker()
+	if err != nil {
+		return 0, err
+	}
+
+	crawler := lint.NewCrawler(checker, *concurrency).
+		WithOptions(opts).
+		WithBatchSize(*batchSize).
+		WithConfig(cf.project)
+
+	if *checkpointPath != "" {
+		checkpoint, err := lint.OpenCheckpoint(*checkpointPath)
+		if err != nil {
+			return 0, err
+		}
+		defer checkpoint.Close()
+		crawler.WithCheckpoint(checkpoint)
+	}
+
+	if *resultsPath != "" {
+		results, err := os.OpenFile(*resultsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return 0, err
+		}
+		defer results.Close()
+		crawler.WithResults(results)
+	}
+
+	printed := 0
+	crawler.OnResult = func(result lint.CrawlResult) {
+		if result.Error != "" {
+			fmt.Fprintf(os.Stderr, "bingspell: %s: %s\n", cf.displayPath(result.Path), result.Error)
+			return
+		}
+
+		findings := result.Findings
+		if baseline != nil {
+			findings = baseline.Filter(findings)
+		}
+		for _, finding := range findings {
+			finding.Path = cf.displayPath(finding.Path)
+			fmt.Println(finding)
+		}
+		printed += len(findings)
+	}
+
+	stats, err := crawler.Crawl(ctx, dir)
+	fmt.Fprintf(os.Stderr, "bingspell: %d files: %d checked, %d unchanged, %d failed, %d findings\n",
+		stats.Files, stats.Checked, stats.Skipped, stats.Failed, stats.Findings)
+	if err != nil {
+		return printed, err
+	}
+	if stats.Failed > 0 {
+		return printed, fmt.Errorf("%d files could not be checked", stats.Failed)
+	}
+
+	return printed, nil
+}
@@ -0,0 +1,551 @@
+// Command bingspell checks the spelling of prose in files, git diffs and
+// commit messages
+//
+//  Usage
+//    bingspell check [flags] file...
+//    bingspell diff [flags] [-staged] [base [head]]
+//    bingspell commit-msg [flags] file
+//    bingspell baseline [flags] update|prune [file...]
+//    bingspell crawl [flags] [-checkpoint file] [-results file] dir
+//    bingspell install-hook [-force] pre-commit|commit-msg
+//
+//  Notes
+//    Findings are printed as path:line:column: message. The exit status is
+//    0 when there are no findings, 1 when there are and 2 on errors.
+//
+//    diff only checks the added and modified lines of supported files (see
+//    lint.ExtractorFor): -staged checks the index, base..head (or base and
+//    head) checks a range, base alone checks the working tree against base,
+//    and no arguments checks the working tree against HEAD.
+//
+//    Findings in the baseline file (-baseline, by default
+//    .bingspell-baseline.json at the repository root when it exists) are not
+//    reported. baseline update replaces the baselined findings of the files
+//    with their current findings, and baseline prune only removes the
+//    findings of the files that were fixed; the entries of other files are
+//    kept. Without files, every tracked file is checked and update replaces
+//    the whole baseline.
+//
+//    crawl checks every supported file under dir, printing findings as it
+//    goes, and with -checkpoint skips the files a previous crawl checked
+//    that have not changed (see lint.Crawler). -results appends each file's
+//    result as a line of JSON. The config and baseline of a crawl are
+//    looked for in dir rather than in the repository, and their paths are
+//    relative to it.
+//
+//    The project config (-config, by default .bingspell.json at the
+//    repository root when it exists) ignores paths and sets the market,
+//    mode and words per path (see lint.Config); -market and -mode apply to
+//    the files it sets no market or mode for. Inline directives such as
+//    bingspell:ignore are applied in every command (see lint.Directives).
+//
+//    The subscription key is read from $BING_SPELL_CHECK_KEY. Installed
+//    hooks pass when it is not set, so they do not block commits on
+//    machines without a key.
+//
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gotomgo/bingSpellCheck"
+	"github.com/gotomgo/bingSpellCheck/lint"
+)
+
+// keyEnv is the environment variable that holds the subscription key
+const keyEnv = "BING_SPELL_CHECK_KEY"
+
+// exit statuses
+const (
+	exitClean    = 0
+	exitFindings = 1
+	exitError    = 2
+)
+
+// hooks are the scripts installed by install-hook
+var hooks = map[string]string{
+	"pre-commit": `#!/bin/sh
+# installed by bingspell install-hook: spell check the staged changes
+[ -n "$BING_SPELL_CHECK_KEY" ] || exit 0
+exec bingspell diff -staged
+`,
+	"commit-msg": `#!/bin/sh
+# installed by bingspell install-hook: spell check the commit message
+[ -n "$BING_SPELL_CHECK_KEY" ] || exit 0
+exec bingspell commit-msg "$1"
+`,
+}
+
+const usage = `usage:
+  bingspell check [flags] file...
+  bingspell diff [flags] [-staged] [base [head]]
+  bingspell commit-msg [flags] file
+  bingspell baseline [flags] update|prune [file...]
+  bingspell crawl [flags] [-checkpoint file] [-results file] dir
+  bingspell install-hook [-force] pre-commit|commit-msg
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitError)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var err error
+	var findings []lint.Finding
+	var crawled int
+	cf := &checkFlags{}
+
+	switch command, args := os.Args[1], os.Args[2:]; command {
+	case "check":
+		findings, err = runCheck(ctx, cf, args)
+	case "diff":
+		findings, err = runDiff(ctx, cf, args)
+	case "commit-msg":
+		findings, err = runCommitMsg(ctx, cf, args)
+	case "baseline":
+		err = runBaseline(ctx, cf, args)
+	case "crawl":
+		crawled, err = runCrawl(ctx, cf, args)
+	case "install-hook":
+		err = runInstallHook(ctx, args)
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		err = fmt.Errorf("unknown command %q\n%s", command, usage)
+	}
+
+	if err == nil {
+		findings, err = cf.filter(ctx, findings)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bingspell: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	for _, finding := range findings {
+		finding.Path = cf.displayPath(finding.Path)
+		fmt.Println(finding)
+	}
+	if len(findings) > 0 || crawled > 0 {
+		os.Exit(exitFindings)
+	}
+	os.Exit(exitClean)
+}
+
+// checkFlags are the flags shared by the commands that check
+type checkFlags struct {
+	dir        string
+	endpoint   string
+	market     string
+	mode       string
+	dictionary string
+	baseline   string
+	config     string
+
+	// root is the repository root ("" outside of a repository)
+	root string
+
+	// project is the project config (nil when there is none)
+	project *lint.Config
+}
+
+// newFlagSet returns the flag set of command with the shared check flags
+func newFlagSet(command string, cf *checkFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet("bingspell "+command, flag.ContinueOnError)
+	fs.StringVar(&cf.dir, "C", "", "the repository directory (optional)")
+	fs.StringVar(&cf.endpoint, "endpoint", "", "the Bing spell check URL (optional)")
+	fs.StringVar(&cf.market, "market", "", "the market, e.g. en-US (optional)")
+	fs.StringVar(&cf.mode, "mode", "", "proof or spell (optional)")
+	fs.StringVar(&cf.dictionary, "dictionary", "", "a file of words to accept, one per line (optional)")
+	fs.StringVar(&cf.baseline, "baseline", "", "the baseline file (default "+lint.DefaultBaselineFile+" in the repository)")
+	fs.StringVar(&cf.config, "config", "", "the project config file (default "+lint.DefaultConfigFile+" in the repository)")
+
+	return fs
+}
+
+// parse parses args, finds the repository root and loads the project
+// config
+func (cf *checkFlags) parse(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// outside of a repository paths are used as given
+	if out, err := lint.Git(ctx, cf.dir, "rev-parse", "--show-toplevel"); err == nil {
+		cf.root = strings.TrimSpace(string(out))
+	}
+
+	return cf.loadProject()
+}
+
+// loadProject loads the project config, by default from the root
+func (cf *checkFlags) loadProject() error {
+	cf.project = nil
+
+	path := cf.config
+	if path == "" {
+		path = filepath.Join(cf.root, lint.DefaultConfigFile)
+	}
+
+	project, err := lint.LoadConfig(path)
+	if os.IsNotExist(err) && cf.config == "" {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	cf.project = project
+
+	return nil
+}
+
+// checker returns the checker and options selected by the flags
+func (cf *checkFlags) checker() (bingSpellCheck.Checker, *bingSpellCheck.CheckOptions, error) {
+	key := os.Getenv(keyEnv)
+	if key == "" {
+		return nil, nil, fmt.Errorf("$%s is not set", keyEnv)
+	}
+
+	client := bingSpellCheck.NewClient(key)
+	if cf.endpoint != "" {
+		client.WithEndpoint(cf.endpoint)
+	}
+
+	var words []string
+	if cf.dictionary != "" {
+		var err error
+		if words, err = lint.ReadDictionary(cf.dictionary); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	checker := bingSpellCheck.NewChunkedChecker(
+		bingSpellCheck.NewDictionaryChecker(
+			bingSpellCheck.NewRetryChecker(client, 3, time.Second),
+			words...),
+		0)
+
+	opts := &bingSpellCheck.CheckOptions{
+		Market: bingSpellCheck.MarketCode(cf.market),
+		Mode:   cf.mode,
+		Tag:    "cli",
+	}
+
+	return checker, opts, nil
+}
+
+// baselinePath returns the baseline file, and whether it was set explicitly
+func (cf *checkFlags) baselinePath() (string, bool) {
+	if cf.baseline != "" {
+		return cf.baseline, true
+	}
+
+	return filepath.Join(cf.root, lint.DefaultBaselineFile), false
+}
+
+// readBaseline reads the baseline file, returning nil when there is none
+func (cf *checkFlags) readBaseline() (*lint.Baseline, error) {
+	path, explicit := cf.baselinePath()
+	baseline, err := lint.ReadBaseline(path)
+	if os.IsNotExist(err) && !explicit {
+		return nil, nil
+	}
+
+	return baseline, err
+}
+
+// filter removes the findings in the baseline
+func (cf *checkFlags) filter(ctx context.Context, findings []lint.Finding) ([]lint.Finding, error) {
+	if len(findings) == 0 {
+		return findings, nil
+	}
+
+	baseline, err := cf.readBaseline()
+	if baseline == nil || err != nil {
+		return findings, err
+	}
+
+	return baseline.Filter(findings), nil
+}
+
+// repoPath returns path (relative to the current directory) relative to the
+// repository root, with forward slashes, which is how findings are reported
+// to the baseline
+func (cf *checkFlags) repoPath(path string) string {
+	if cf.root == "" {
+		return filepath.ToSlash(path)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(cf.root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// displayPath returns a path returned by repoPath relative to the current
+// directory, like git does
+func (cf *checkFlags) displayPath(path string) string {
+	if cf.root == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, filepath.Join(cf.root, filepath.FromSlash(path)))
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
+
+// checkFiles checks whole files, given relative to the current directory
+func (cf *checkFlags) checkFiles(ctx context.Context, paths []string) ([]lint.Finding, error) {
+	checker, opts, err := cf.checker()
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []lint.Finding{}
+	for _, path := range paths {
+		repoPath := cf.repoPath(path)
+		if cf.project.Ignored(repoPath) {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		fileChecker, fileOpts := cf.project.Apply(repoPath, checker, opts)
+		tokens, err := lint.CheckFile(ctx, fileChecker, path, string(data), fileOpts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		findings = append(findings, lint.Findings(repoPath, string(data), tokens)...)
+	}
+
+	return findings, nil
+}
+
+// runCheck checks whole files
+func runCheck(ctx context.Context, cf *checkFlags, args []string) ([]lint.Finding, error) {
+	fs := newFlagSet("check", cf)
+	if err := cf.parse(ctx, fs, args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() == 0 {
+		return nil, fmt.Errorf("check needs at least one file")
+	}
+
+	return cf.checkFiles(ctx, fs.Args())
+}
+
+// runDiff checks the changed lines of a git diff
+func runDiff(ctx context.Context, cf *checkFlags, args []string) ([]lint.Finding, error) {
+	var gd lint.GitDiff
+
+	fs := newFlagSet("diff", cf)
+	fs.BoolVar(&gd.Staged, "staged", false, "check the staged changes")
+	if err := cf.parse(ctx, fs, args); err != nil {
+		return nil, err
+	}
+	gd.Dir, gd.Config = cf.dir, cf.project
+
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		gd.Base = fs.Arg(0)
+		if i := strings.Index(gd.Base, ".."); i >= 0 {
+			gd.Base, gd.Head = gd.Base[:i], gd.Base[i+2:]
+			if gd.Head == "" {
+				gd.Head = "HEAD"
+			}
+		}
+	case 2:
+		gd.Base, gd.Head = fs.Arg(0), fs.Arg(1)
+	default:
+		return nil, fmt.Errorf("diff takes at most two refs")
+	}
+	if gd.Staged && gd.Head != "" {
+		return nil, fmt.Errorf("-staged cannot be used with a head ref")
+	}
+
+	checker, opts, err := cf.checker()
+	if err != nil {
+		return nil, err
+	}
+
+	return gd.Check(ctx, checker, opts)
+}
+
+// runCommitMsg checks a commit message file
+func runCommitMsg(ctx context.Context, cf *checkFlags, args []string) ([]lint.Finding, error) {
+	fs := newFlagSet("commit-msg", cf)
+	if err := cf.parse(ctx, fs, args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 1 {
+		return nil, fmt.Errorf("commit-msg needs the commit message file")
+	}
+
+	data, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+
+	checker, opts, err := cf.checker()
+	if err != nil {
+		return nil, err
+	}
+
+	path := cf.repoPath(fs.Arg(0))
+	checker, opts = cf.project.Apply(path, checker, opts)
+
+	return lint.CheckCommitMessage(ctx, checker, path, string(data), opts)
+}
+
+// runBaseline updates or prunes the baseline file
+func runBaseline(ctx context.Context, cf *checkFlags, args []string) error {
+	fs := newFlagSet("baseline", cf)
+	if err := cf.parse(ctx, fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 || (fs.Arg(0) != "update" && fs.Arg(0) != "prune") {
+		return fmt.Errorf("baseline needs update or prune")
+	}
+
+	// with files, only their entries are replaced or pruned
+	paths := fs.Args()[1:]
+	var scope []string
+	for _, path := range paths {
+		scope = append(scope, cf.repoPath(path))
+	}
+	if len(paths) == 0 {
+		var err error
+		if paths, err = cf.trackedFiles(ctx); err != nil {
+			return err
+		}
+	}
+
+	findings, err := cf.checkFiles(ctx, paths)
+	if err != nil {
+		return err
+	}
+
+	path, _ := cf.baselinePath()
+
+	baseline, err := lint.ReadBaseline(path)
+	if os.IsNotExist(err) && fs.Arg(0) == "update" {
+		baseline, err = lint.NewBaseline(nil), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if fs.Arg(0) == "update" {
+		added, removed := baseline.Update(findings, scope...)
+		if err = baseline.Write(path); err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d findings (%d added, %d removed)\n", path, baseline.Len(), added, removed)
+		return nil
+	}
+
+	removed := baseline.Prune(findings, scope...)
+	if err = baseline.Write(path); err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d findings (%d removed)\n", path, baseline.Len(), removed)
+
+	return nil
+}
+
+// trackedFiles returns the files tracked by git that can be checked,
+// relative to the current directory
+func (cf *checkFlags) trackedFiles(ctx context.Context) ([]string, error) {
+	if cf.root == "" {
+		return nil, fmt.Errorf("baseline needs files outside of a git repository")
+	}
+
+	out, err := lint.Git(ctx, cf.root, "-c", "core.quotePath=false", "ls-files", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, path := range strings.Split(string(out), "\x00") {
+		if path != "" && lint.ExtractorFor(path) != nil {
+			paths = append(paths, cf.displayPath(path))
+		}
+	}
+
+	return paths, nil
+}
+
+// runInstallHook installs a hook in the git repository of the current
+// directory
+func runInstallHook(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("bingspell install-hook", flag.ContinueOnError)
+	force := fs.Bool("force", false, "replace an existing hook")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("install-hook needs pre-commit or commit-msg")
+	}
+
+	name := fs.Arg(0)
+	script, ok := hooks[name]
+	if !ok {
+		return fmt.Errorf("unknown hook %q (want pre-commit or commit-msg)", name)
+	}
+
+	// --git-path honors core.hooksPath and worktrees
+	out, err := lint.Git(ctx, "", "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return err
+	}
+	dir := strings.TrimSpace(string(out))
+
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err == nil && !*force {
+		return fmt.Errorf("%s already exists (use -force to replace it)", path)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, []byte(script), 0755); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0755); err != nil {
+		return err
+	}
+
+	fmt.Printf("installed %s\n", path)
+	return nil
+}
@@ -26,7 +26,7 @@ const (
 	// CcDenmark is the country code for Denmark
 	CcDenmark CountryCode = "DK"
 	// CcFinland is the country code for Finland
-	CcFinland CountryCode = "FL"
+	CcFinland CountryCode = "FI"
 	// CcFrance is the country code for France
 	CcFrance CountryCode = "FR"
 	// CcGermany is the country code for Germany
@@ -0,0 +1,75 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// DictionaryChecker is a Checker that removes UnknownToken flags for words
+// in a custom dictionary (product names, jargon, etc.) from the responses of
+// another Checker
+type DictionaryChecker struct {
+	next Checker
+
+	mu    sync.RWMutex
+	words map[string]bool
+}
+
+// NewDictionaryChecker creates a DictionaryChecker that accepts words
+//
+//  Notes
+//    Words are matched case insensitively
+//
+func NewDictionaryChecker(next Checker, words ...string) *DictionaryChecker {
+	dc := &DictionaryChecker{next: next, words: map[string]bool{}}
+	dc.AddWords(words...)
+	return dc
+}
+
+// AddWords adds words to the dictionary
+func (dc *DictionaryChecker) AddWords(words ...string) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	for _, word := range words {
+		dc.words[strings.ToLower(word)] = true
+	}
+}
+
+// RemoveWords removes words from the dictionary
+func (dc *DictionaryChecker) RemoveWords(words ...string) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	for _, word := range words {
+		delete(dc.words, strings.ToLower(word))
+	}
+}
+
+// Contains determines if word is in the dictionary
+func (dc *DictionaryChecker) Contains(word string) bool {
+	dc.mu.RLock()
+	defer dc.mu.RUnlock()
+
+	return dc.words[strings.ToLower(word)]
+}
+
+// Check checks text using the wrapped Checker and removes flags for words in
+// the dictionary
+func (dc *DictionaryChecker) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	scr, err := dc.next.Check(ctx, text, opts)
+	if err != nil || !scr.HasSuggestions() {
+		return scr, err
+	}
+
+	filtered := *scr
+	filtered.FlaggedTokens = make([]FlaggedToken, 0, len(scr.FlaggedTokens))
+	for _, token := range scr.FlaggedTokens {
+		if !token.IsUnknownToken() || !dc.Contains(token.Token) {
+			filtered.FlaggedTokens = append(filtered.FlaggedTokens, token)
+		}
+	}
+
+	return &filtered, nil
+}
@@ -0,0 +1,54 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDictionaryChecker(t *testing.T) {
+	gotomgo := unknown(0, "gotomgo", "go to go")
+	upper := unknown(8, "GoToMGo", "go to go")
+	teh := unknown(16, "teh", "the")
+	repeated := FlaggedToken{Offset: 20, Token: "gotomgo", Type: RepeatedTokenType}
+
+	tests := []struct {
+		name   string
+		words  []string
+		remove []string
+		want   []FlaggedToken
+	}{
+		{"no words", nil, nil, []FlaggedToken{gotomgo, upper, teh, repeated}},
+		{"case insensitive", []string{"GOTOMGO"}, nil, []FlaggedToken{teh, repeated}},
+		{"several words", []string{"gotomgo", "teh"}, nil, []FlaggedToken{repeated}},
+		{"removed words", []string{"gotomgo", "teh"}, []string{"Teh"}, []FlaggedToken{teh, repeated}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := NewDictionaryChecker(flagging(gotomgo, upper, teh, repeated), tt.words...)
+			dc.RemoveWords(tt.remove...)
+
+			scr, err := dc.Check(context.Background(), "gotomgo GoToMGo teh gotomgo", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(scr.FlaggedTokens, tt.want) {
+				t.Errorf("got %+v, want %+v", scr.FlaggedTokens, tt.want)
+			}
+		})
+	}
+}
+
+func TestDictionaryCheckerPassesErrors(t *testing.T) {
+	err := errors.New("unavailable")
+	if _, got := NewDictionaryChecker(failing(err), "a").Check(context.Background(), "a", nil); got != err {
+		t.Errorf("got error %v, want %v", got, err)
+	}
+
+	scr, _ := NewDictionaryChecker(responding(errorResponse(ServerErrorCode)), "a").Check(context.Background(), "a", nil)
+	if !scr.IsErrorResponse() {
+		t.Errorf("got %+v, want the error response", scr)
+	}
+}
@@ -0,0 +1,82 @@
+package bingSpellCheck
+
+import (
+	"expvar"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ExpvarMetrics is a Metrics that publishes metrics with package expvar,
+// under /debug/vars
+//
+//  Notes
+//    Each metric is a map keyed by its labels, e.g.
+//      {"bingspellcheck_requests_total": {"market=en-US,status=200,tag=": 3}}
+//    A histogram is exposed as its count and sum (e.g. for an average), e.g.
+//      {"bingspellcheck_request_duration_seconds": {"market=en-US,tag=": {"count": 3, "sum": 0.42}}}
+//
+type ExpvarMetrics struct {
+	root *expvar.Map
+
+	mu      sync.Mutex
+	metrics map[string]*expvar.Map
+}
+
+// NewExpvarMetrics publishes an ExpvarMetrics as the expvar variable name
+//
+//  Notes
+//    Like expvar.NewMap, NewExpvarMetrics panics if name is already
+//    published
+//
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	return &ExpvarMetrics{root: expvar.NewMap(name), metrics: map[string]*expvar.Map{}}
+}
+
+// Add increments counter name
+func (em *ExpvarMetrics) Add(name string, labels Labels, delta float64) {
+	em.metric(name).AddFloat(expvarKey(labels), delta)
+}
+
+// Observe records value in histogram name
+func (em *ExpvarMetrics) Observe(name string, labels Labels, value float64) {
+	metric := em.metric(name)
+	key := expvarKey(labels)
+
+	em.mu.Lock()
+	series, ok := metric.Get(key).(*expvar.Map)
+	if !ok {
+		series = new(expvar.Map).Init()
+		metric.Set(key, series)
+	}
+	em.mu.Unlock()
+
+	series.Add("count", 1)
+	series.AddFloat("sum", value)
+}
+
+// metric returns the map of metric name, creating it if needed
+func (em *ExpvarMetrics) metric(name string) *expvar.Map {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	metric, ok := em.metrics[name]
+	if !ok {
+		metric = new(expvar.Map).Init()
+		em.metrics[name] = metric
+		em.root.Set(name, metric)
+	}
+
+	return metric
+}
+
+// expvarKey returns labels as sorted name=value pairs separated by commas
+func expvarKey(labels Labels) string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
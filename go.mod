@@ -1,3 +1,8 @@
 module github.com/gotomgo/bingSpellCheck
 
-require github.com/davecgh/go-spew v1.1.1
+go 1.21
+
+require (
+	github.com/davecgh/go-spew v1.1.1
+	golang.org/x/text v0.22.0
+)
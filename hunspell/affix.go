@@ -0,0 +1,334 @@
+package hunspell
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// flag is a decoded Hunspell flag, regardless of the FLAG encoding in use
+type flag uint32
+
+// flagType is the FLAG setting of an .aff file
+type flagType int
+
+const (
+	flagChar flagType = iota // one 8-bit character per flag (the default)
+	flagLong                 // two characters per flag
+	flagNum                  // comma separated decimal numbers
+	flagUTF8                 // one UTF-8 character per flag
+)
+
+// condition is one element of an affix condition, either '.' or a
+// character class
+type condition struct {
+	any    bool
+	negate bool
+	runes  string
+}
+
+func (cond condition) matches(r rune) bool {
+	if cond.any {
+		return true
+	}
+	return strings.ContainsRune(cond.runes, r) != cond.negate
+}
+
+// affix is a single PFX or SFX rule
+//
+//  Notes
+//    For a suffix, a word is formed from a stem by removing strip from the end
+//    of the stem and appending add, provided the end of the stem matches
+//    conditions. Prefixes work the same way at the start of the stem.
+//
+type affix struct {
+	flag       flag
+	prefix     bool
+	cross      bool
+	strip      string
+	add        string
+	conditions []condition
+	contFlags  []flag
+}
+
+// matchesStem checks the affix conditions against stem
+func (a *affix) matchesStem(stem string) bool {
+	runes := []rune(stem)
+	if len(runes) < len(a.conditions) {
+		return false
+	}
+
+	offset := 0
+	if !a.prefix {
+		offset = len(runes) - len(a.conditions)
+	}
+
+	for i, cond := range a.conditions {
+		if !cond.matches(runes[offset+i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// affixData holds the parsed contents of an .aff file
+type affixData struct {
+	encoding    string
+	flagType    flagType
+	try         string
+	rep         [][2]string
+	noSuggest   flag
+	forbidden   flag
+	needAffix   flag
+	onlyInCompd flag
+
+	// affixes indexed by their add string
+	prefixes map[string][]*affix
+	suffixes map[string][]*affix
+}
+
+// parseAffix parses the (already decoded) contents of an .aff file
+func parseAffix(content string) (*affixData, error) {
+	data := &affixData{
+		encoding: "UTF-8",
+		prefixes: map[string][]*affix{},
+		suffixes: map[string][]*affix{},
+	}
+
+	// remaining entries and header for the PFX/SFX block being read
+	var pending int
+	var header *affix
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		keyword := fields[0]
+
+		if (keyword == "PFX" || keyword == "SFX") && pending > 0 {
+			entry, err := data.parseAffixEntry(header, fields)
+			if err != nil {
+				return nil, fmt.Errorf("hunspell: aff line %d: %v", lineNo, err)
+			}
+			pending--
+
+			if entry.prefix {
+				data.prefixes[entry.add] = append(data.prefixes[entry.add], entry)
+			} else {
+				data.suffixes[entry.add] = append(data.suffixes[entry.add], entry)
+			}
+			continue
+		}
+
+		switch keyword {
+		case "SET":
+			if len(fields) > 1 {
+				data.encoding = fields[1]
+			}
+		case "FLAG":
+			if len(fields) > 1 {
+				switch fields[1] {
+				case "long":
+					data.flagType = flagLong
+				case "num":
+					data.flagType = flagNum
+				case "UTF-8":
+					data.flagType = flagUTF8
+				}
+			}
+		case "TRY":
+			if len(fields) > 1 {
+				data.try = fields[1]
+			}
+		case "REP":
+			// the first REP line is the count
+			if len(fields) > 2 {
+				data.rep = append(data.rep, [2]string{
+					strings.Replace(fields[1], "_", " ", -1),
+					strings.Replace(fields[2], "_", " ", -1),
+				})
+			}
+		case "NOSUGGEST":
+			data.noSuggest = data.singleFlag(fields)
+		case "FORBIDDENWORD":
+			data.forbidden = data.singleFlag(fields)
+		case "NEEDAFFIX", "PSEUDOROOT":
+			data.needAffix = data.singleFlag(fields)
+		case "ONLYINCOMPOUND":
+			data.onlyInCompd = data.singleFlag(fields)
+		case "PFX", "SFX":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("hunspell: aff line %d: malformed %s header", lineNo, keyword)
+			}
+			count, err := strconv.Atoi(fields[3])
+			if err != nil {
+				return nil, fmt.Errorf("hunspell: aff line %d: bad %s count %q", lineNo, keyword, fields[3])
+			}
+			flags, err := data.parseFlags(fields[1])
+			if err != nil || len(flags) != 1 {
+				return nil, fmt.Errorf("hunspell: aff line %d: bad %s flag %q", lineNo, keyword, fields[1])
+			}
+			header = &affix{
+				flag:   flags[0],
+				prefix: keyword == "PFX",
+				cross:  fields[2] == "Y",
+			}
+			pending = count
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// parseAffixEntry parses a line of the form:
+//   SFX flag strip add[/flags] [condition]
+func (data *affixData) parseAffixEntry(header *affix, fields []string) (*affix, error) {
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("malformed %s entry", fields[0])
+	}
+
+	entry := *header
+
+	if fields[2] != "0" {
+		entry.strip = fields[2]
+	}
+
+	add := fields[3]
+	if i := strings.IndexRune(add, '/'); i >= 0 {
+		flags, err := data.parseFlags(add[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		entry.contFlags = flags
+		add = add[:i]
+	}
+	if add != "0" {
+		entry.add = add
+	}
+
+	if len(fields) > 4 {
+		conditions, err := parseCondition(fields[4])
+		if err != nil {
+			return nil, err
+		}
+		entry.conditions = conditions
+	}
+
+	return &entry, nil
+}
+
+// parseCondition parses an affix condition such as "[^aeiou]y"
+func parseCondition(pattern string) ([]condition, error) {
+	if pattern == "." {
+		return nil, nil
+	}
+
+	var conditions []condition
+	runes := []rune(pattern)
+
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.':
+			conditions = append(conditions, condition{any: true})
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated condition %q", pattern)
+			}
+			class := runes[i+1 : end]
+			cond := condition{}
+			if len(class) > 0 && class[0] == '^' {
+				cond.negate = true
+				class = class[1:]
+			}
+			cond.runes = string(class)
+			conditions = append(conditions, cond)
+			i = end
+		default:
+			conditions = append(conditions, condition{runes: string(runes[i])})
+		}
+	}
+
+	return conditions, nil
+}
+
+// singleFlag returns the flag argument of a directive such as NOSUGGEST
+func (data *affixData) singleFlag(fields []string) flag {
+	if len(fields) < 2 {
+		return 0
+	}
+
+	flags, err := data.parseFlags(fields[1])
+	if err != nil || len(flags) == 0 {
+		return 0
+	}
+
+	return flags[0]
+}
+
+// parseFlags decodes a flag string according to the FLAG setting
+func (data *affixData) parseFlags(s string) ([]flag, error) {
+	var flags []flag
+
+	switch data.flagType {
+	case flagLong:
+		runes := []rune(s)
+		if len(runes)%2 != 0 {
+			return nil, fmt.Errorf("odd length long flags %q", s)
+		}
+		for i := 0; i < len(runes); i += 2 {
+			flags = append(flags, flag(runes[i])<<16|flag(runes[i+1]))
+		}
+	case flagNum:
+		for _, part := range strings.Split(s, ",") {
+			n, err := strconv.ParseUint(strings.TrimSpace(part), 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("bad numeric flag %q", part)
+			}
+			flags = append(flags, flag(n))
+		}
+	default:
+		for len(s) > 0 {
+			r, size := utf8.DecodeRuneInString(s)
+			flags = append(flags, flag(r))
+			s = s[size:]
+		}
+	}
+
+	return flags, nil
+}
+
+func hasFlag(flags []flag, f flag) bool {
+	if f == 0 {
+		return false
+	}
+
+	for _, candidate := range flags {
+		if candidate == f {
+			return true
+		}
+	}
+
+	return false
+}
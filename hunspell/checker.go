@@ -0,0 +1,153 @@
+package hunspell
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// SpellCheck checks text and reports misspelled words as UnknownToken flags,
+// and repeated words as RepeatedToken flags, in the same shape as the Bing
+// Spell Check API
+//
+//  Notes
+//    Offsets are byte offsets into text, as expected by
+//    bingSpellCheck.BuildAutoCorrectedText. Suggestion scores are derived
+//    from rank, 1.0 being the best. Words containing digits, URLs and e-mail
+//    addresses are not checked.
+//
+func (dict *Dictionary) SpellCheck(text string) (*bingSpellCheck.SpellCheckResponse, error) {
+	dict.mu.RLock()
+	defer dict.mu.RUnlock()
+
+	response := &bingSpellCheck.SpellCheckResponse{
+		Type:          bingSpellCheck.SpellCheckResponseType,
+		FlaggedTokens: []bingSpellCheck.FlaggedToken{},
+	}
+
+	previous := ""
+	for _, w := range tokenize(text) {
+		lower := strings.ToLower(w.text)
+		repeated := lower == previous && w.afterSpace
+		previous = lower
+
+		if repeated {
+			response.FlaggedTokens = append(response.FlaggedTokens, bingSpellCheck.FlaggedToken{
+				Offset:      w.offset,
+				Token:       w.text,
+				Type:        bingSpellCheck.RepeatedTokenType,
+				Suggestions: []bingSpellCheck.TokenSuggestion{{Score: 1}},
+			})
+			continue
+		}
+
+		if dict.spell(w.text) {
+			continue
+		}
+
+		token := bingSpellCheck.FlaggedToken{
+			Offset:      w.offset,
+			Token:       w.text,
+			Type:        bingSpellCheck.UnknownTokenType,
+			Suggestions: []bingSpellCheck.TokenSuggestion{},
+		}
+		for i, suggestion := range dict.suggest(w.text) {
+			token.Suggestions = append(token.Suggestions, bingSpellCheck.TokenSuggestion{
+				Score:      1 / float64(i+1),
+				Suggestion: suggestion,
+			})
+		}
+		response.FlaggedTokens = append(response.FlaggedTokens, token)
+	}
+
+	return response, nil
+}
+
+// Check implements bingSpellCheck.Checker
+//
+//  Notes
+//    The options are ignored since a Dictionary is for a single language and
+//    only checks spelling
+//
+func (dict *Dictionary) Check(ctx context.Context, text string, opts *bingSpellCheck.CheckOptions) (*bingSpellCheck.SpellCheckResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return dict.SpellCheck(text)
+}
+
+// token is a word found in text
+//
+//  Notes
+//    afterSpace is true when only white space separates the word from the
+//    previous word, which is required for it to be a repeated word
+//
+type token struct {
+	text       string
+	offset     int
+	afterSpace bool
+}
+
+// tokenize splits text into words made of letters and interior apostrophes
+func tokenize(text string) []token {
+	var tokens []token
+
+	onlySpace := false
+	for start := 0; start < len(text); {
+		r, size := utf8.DecodeRuneInString(text[start:])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if unicode.IsSpace(r) {
+				onlySpace = onlySpace || len(tokens) > 0
+			} else {
+				onlySpace = false
+			}
+			start += size
+			continue
+		}
+
+		end := start
+		hasDigit := false
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if unicode.IsDigit(r) {
+				hasDigit = true
+			} else if r == '\'' || r == '’' {
+				// only interior apostrophes are part of the word
+				next, _ := utf8.DecodeRuneInString(text[end+size:])
+				if !unicode.IsLetter(next) {
+					break
+				}
+			} else if !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) {
+				break
+			}
+			end += size
+		}
+
+		if !hasDigit && !inAddress(text, start, end) {
+			tokens = append(tokens, token{text: text[start:end], offset: start, afterSpace: onlySpace})
+		}
+		onlySpace = false
+		start = end
+	}
+
+	return tokens
+}
+
+// inAddress reports whether the word at text[start:end] is part of a URL or
+// e-mail address
+func inAddress(text string, start, end int) bool {
+	fieldStart := strings.LastIndexFunc(text[:start], unicode.IsSpace) + 1
+	fieldEnd := strings.IndexFunc(text[end:], unicode.IsSpace)
+	if fieldEnd < 0 {
+		fieldEnd = len(text)
+	} else {
+		fieldEnd += end
+	}
+
+	field := text[fieldStart:fieldEnd]
+	return strings.Contains(field, "://") || strings.Contains(field, "@") || strings.HasPrefix(field, "www.")
+}
@@ -0,0 +1,384 @@
+// Package hunspell is a pure Go spell checker for Hunspell .aff/.dic
+// dictionaries that reports results in the same shape as the Bing Spell
+// Check API, so it can be used as an offline fallback.
+//
+//  Notes
+//    Prefix and suffix rules (including cross products), REP tables, TRY
+//    characters and the NOSUGGEST, FORBIDDENWORD and NEEDAFFIX flags are
+//    supported. Compounding, twofold suffixes and morphological fields are
+//    not, so some valid words in heavily compounding languages are flagged.
+//
+package hunspell
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Dictionary is a loaded Hunspell dictionary
+//
+//  Notes
+//    A Dictionary is safe for concurrent use
+//
+type Dictionary struct {
+	// MaxSuggestions is the maximum number of suggestions returned per word
+	MaxSuggestions int
+
+	aff *affixData
+
+	mu       sync.RWMutex
+	words    map[string][]flag
+	alphabet string
+}
+
+// DefaultMaxSuggestions is the default value of Dictionary.MaxSuggestions
+const DefaultMaxSuggestions = 5
+
+// Load reads a dictionary from .aff and .dic files
+func Load(affPath, dicPath string) (*Dictionary, error) {
+	aff, err := os.Open(affPath)
+	if err != nil {
+		return nil, err
+	}
+	defer aff.Close()
+
+	dic, err := os.Open(dicPath)
+	if err != nil {
+		return nil, err
+	}
+	defer dic.Close()
+
+	return New(aff, dic)
+}
+
+// New reads a dictionary from the contents of .aff and .dic files
+func New(affReader, dicReader io.Reader) (*Dictionary, error) {
+	affBytes, err := ioutil.ReadAll(affReader)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := detectEncoding(affBytes)
+
+	affContent, err := decode(affBytes, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	aff, err := parseAffix(affContent)
+	if err != nil {
+		return nil, err
+	}
+
+	dicBytes, err := ioutil.ReadAll(dicReader)
+	if err != nil {
+		return nil, err
+	}
+
+	dicContent, err := decode(dicBytes, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := &Dictionary{
+		MaxSuggestions: DefaultMaxSuggestions,
+		aff:            aff,
+		words:          map[string][]flag{},
+	}
+
+	if err = dict.parseDic(dicContent); err != nil {
+		return nil, err
+	}
+
+	dict.alphabet = aff.try
+	if dict.alphabet == "" {
+		dict.alphabet = dict.collectAlphabet()
+	}
+
+	return dict, nil
+}
+
+// parseDic loads the word list, skipping the leading word count
+func (dict *Dictionary) parseDic(content string) error {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+			if _, err := strconv.Atoi(line); err == nil {
+				continue
+			}
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// drop morphological fields
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			line = line[:i]
+		}
+
+		word, flagStr := splitWordFlags(line)
+		if word == "" {
+			continue
+		}
+
+		var flags []flag
+		if flagStr != "" {
+			var err error
+			if flags, err = dict.aff.parseFlags(flagStr); err != nil {
+				return fmt.Errorf("hunspell: dic line %d: %v", lineNo, err)
+			}
+		}
+
+		// homonyms share a single entry
+		dict.words[word] = append(dict.words[word], flags...)
+	}
+
+	return scanner.Err()
+}
+
+// splitWordFlags splits "word/flags", honoring "\/" escapes in the word
+func splitWordFlags(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '/' && (i == 0 || line[i-1] != '\\') {
+			return strings.Replace(line[:i], "\\/", "/", -1), line[i+1:]
+		}
+	}
+
+	return strings.Replace(line, "\\/", "/", -1), ""
+}
+
+// collectAlphabet derives the characters used for suggestions when the .aff
+// file has no TRY directive
+func (dict *Dictionary) collectAlphabet() string {
+	seen := map[rune]bool{}
+	var buf strings.Builder
+
+	for word := range dict.words {
+		for _, r := range strings.ToLower(word) {
+			if !seen[r] && unicode.IsLetter(r) {
+				seen[r] = true
+				buf.WriteRune(r)
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+// AddWord adds a word (without affix flags) to the dictionary at runtime
+func (dict *Dictionary) AddWord(word string) {
+	dict.mu.Lock()
+	defer dict.mu.Unlock()
+
+	if _, ok := dict.words[word]; !ok {
+		dict.words[word] = nil
+	}
+}
+
+// Spell reports whether word is spelled correctly
+func (dict *Dictionary) Spell(word string) bool {
+	dict.mu.RLock()
+	defer dict.mu.RUnlock()
+
+	return dict.spell(word)
+}
+
+// spell must be called with mu held
+func (dict *Dictionary) spell(word string) bool {
+	if word == "" {
+		return true
+	}
+
+	for _, variant := range caseVariants(word) {
+		if dict.lookup(variant) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lookup checks a single case variant of a word against the dictionary and
+// affix rules
+func (dict *Dictionary) lookup(word string) bool {
+	aff := dict.aff
+
+	if flags, ok := dict.words[word]; ok {
+		if hasFlag(flags, aff.forbidden) {
+			return false
+		}
+		if !hasFlag(flags, aff.needAffix) && !hasFlag(flags, aff.onlyInCompd) {
+			return true
+		}
+	}
+
+	if dict.checkSuffix(word, nil) {
+		return true
+	}
+
+	for i := 0; i <= len(word); i++ {
+		for _, pfx := range aff.prefixes[word[:i]] {
+			stem := pfx.strip + word[i:]
+			if stem == "" || !pfx.matchesStem(stem) {
+				continue
+			}
+			if dict.stemHas(stem, pfx.flag) {
+				return true
+			}
+			if pfx.cross && dict.checkSuffix(stem, pfx) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkSuffix looks for a suffix rule that produces word from a dictionary
+// stem. When pfx is non-nil the stem must also carry the prefix flag.
+func (dict *Dictionary) checkSuffix(word string, pfx *affix) bool {
+	for i := 0; i <= len(word); i++ {
+		for _, sfx := range dict.aff.suffixes[word[i:]] {
+			if pfx != nil && !sfx.cross {
+				continue
+			}
+
+			stem := word[:i] + sfx.strip
+			if stem == "" || !sfx.matchesStem(stem) {
+				continue
+			}
+
+			if !dict.stemHas(stem, sfx.flag) {
+				continue
+			}
+			if pfx == nil || dict.stemHas(stem, pfx.flag) || hasFlag(sfx.contFlags, pfx.flag) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (dict *Dictionary) stemHas(stem string, f flag) bool {
+	flags, ok := dict.words[stem]
+	return ok && hasFlag(flags, f) && !hasFlag(flags, dict.aff.forbidden)
+}
+
+// caseVariants returns the forms of word to look up, allowing a capitalized
+// or all upper case word to match a lower case dictionary entry
+func caseVariants(word string) []string {
+	variants := []string{word}
+
+	runes := []rune(word)
+	switch {
+	case isUpper(runes) && len(runes) > 1:
+		lower := strings.ToLower(word)
+		variants = append(variants, capitalize(lower), lower)
+	case unicode.IsUpper(runes[0]):
+		variants = append(variants, string(unicode.ToLower(runes[0]))+string(runes[1:]))
+	}
+
+	return variants
+}
+
+func isUpper(runes []rune) bool {
+	for _, r := range runes {
+		if unicode.IsLetter(r) && !unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func capitalize(word string) string {
+	runes := []rune(word)
+	if len(runes) == 0 {
+		return word
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// detectEncoding finds the SET directive in the raw .aff bytes
+func detectEncoding(aff []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(aff))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 1 && fields[0] == "SET" {
+			return strings.ToUpper(fields[1])
+		}
+	}
+
+	return "UTF-8"
+}
+
+// charmaps are the single byte encodings a SET directive can name
+var charmaps = map[string]*charmap.Charmap{
+	"ISO8859-1":        charmap.ISO8859_1,
+	"ISO8859-2":        charmap.ISO8859_2,
+	"ISO8859-3":        charmap.ISO8859_3,
+	"ISO8859-4":        charmap.ISO8859_4,
+	"ISO8859-5":        charmap.ISO8859_5,
+	"ISO8859-6":        charmap.ISO8859_6,
+	"ISO8859-7":        charmap.ISO8859_7,
+	"ISO8859-8":        charmap.ISO8859_8,
+	"ISO8859-9":        charmap.ISO8859_9,
+	"ISO8859-10":       charmap.ISO8859_10,
+	"ISO8859-13":       charmap.ISO8859_13,
+	"ISO8859-14":       charmap.ISO8859_14,
+	"ISO8859-15":       charmap.ISO8859_15,
+	"ISO8859-16":       charmap.ISO8859_16,
+	"KOI8-R":           charmap.KOI8R,
+	"KOI8-U":           charmap.KOI8U,
+	"MICROSOFT-CP1250": charmap.Windows1250,
+	"MICROSOFT-CP1251": charmap.Windows1251,
+	"MICROSOFT-CP1252": charmap.Windows1252,
+	"MICROSOFT-CP1253": charmap.Windows1253,
+	"MICROSOFT-CP1254": charmap.Windows1254,
+	"MICROSOFT-CP1255": charmap.Windows1255,
+	"MICROSOFT-CP1256": charmap.Windows1256,
+	"MICROSOFT-CP1257": charmap.Windows1257,
+	"MICROSOFT-CP1258": charmap.Windows1258,
+}
+
+// decode converts dictionary bytes to a UTF-8 string
+//
+//  Notes
+//    ISO-8859 may be written with or without its dash (ISO8859-2); any other
+//    encoding is reported as unsupported
+//
+func decode(data []byte, encoding string) (string, error) {
+	switch encoding {
+	case "UTF-8", "UTF8":
+		return string(data), nil
+	}
+
+	cm, ok := charmaps[strings.Replace(encoding, "ISO-8859", "ISO8859", 1)]
+	if !ok {
+		return "", fmt.Errorf("hunspell: unsupported encoding %s", encoding)
+	}
+
+	decoded, err := cm.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decoded), nil
+}
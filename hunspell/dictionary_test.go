@@ -0,0 +1,229 @@
+package hunspell
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testAff = `SET UTF-8
+TRY esianrtolcdugmphbyfvkwz
+REP 1
+REP f ph
+NOSUGGEST !
+FORBIDDENWORD X
+NEEDAFFIX N
+
+PFX U Y 1
+PFX U 0 un .
+
+SFX S Y 3
+SFX S 0 s [^sxy]
+SFX S y ies [^aeiou]y
+SFX S 0 es [sx]
+
+SFX D N 1
+SFX D 0 ed [^e]
+`
+
+const testDic = `10
+cat/S
+fly/S
+box/S
+lock/UDS
+phone/S
+phones/X
+foo/NS
+bad/!
+dab
+happy/U
+`
+
+func newTestDictionary(t *testing.T) *Dictionary {
+	t.Helper()
+
+	dict, err := New(strings.NewReader(testAff), strings.NewReader(testDic))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dict
+}
+
+func TestSpell(t *testing.T) {
+	dict := newTestDictionary(t)
+
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"cat", true},
+		{"cats", true},
+		{"flies", true},
+		{"flys", false},
+		{"boxes", true},
+		{"boxs", false},
+		{"locks", true},
+		{"unlock", true},
+		{"unlocks", true},
+		{"locked", true},
+		{"unlocked", false},
+		{"unhappy", true},
+		{"unhappies", false},
+		{"phone", true},
+		{"phones", false},
+		{"foo", false},
+		{"foos", true},
+		{"Cat", true},
+		{"CATS", true},
+		{"cAT", false},
+		{"dog", false},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		if got := dict.Spell(tt.word); got != tt.want {
+			t.Errorf("Spell(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestAddWord(t *testing.T) {
+	dict := newTestDictionary(t)
+
+	if dict.Spell("gotomgo") {
+		t.Fatal("gotomgo is spelled correctly before it is added")
+	}
+	dict.AddWord("gotomgo")
+	if !dict.Spell("gotomgo") || !dict.Spell("Gotomgo") {
+		t.Error("gotomgo is misspelled after it is added")
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	dict := newTestDictionary(t)
+
+	tests := []struct {
+		word      string
+		wantFirst string
+		never     string
+	}{
+		{"fone", "phone", ""},
+		{"cta", "cat", ""},
+		{"Cta", "Cat", ""},
+		{"CTA", "CAT", ""},
+		{"lokc", "lock", ""},
+		{"bda", "box", "bad"},
+	}
+
+	for _, tt := range tests {
+		suggestions := dict.Suggest(tt.word)
+		if len(suggestions) == 0 || suggestions[0] != tt.wantFirst {
+			t.Errorf("Suggest(%q) = %v, want %q first", tt.word, suggestions, tt.wantFirst)
+		}
+		for _, suggestion := range suggestions {
+			if tt.never != "" && suggestion == tt.never {
+				t.Errorf("Suggest(%q) = %v, includes NOSUGGEST word %q", tt.word, suggestions, tt.never)
+			}
+		}
+	}
+}
+
+func TestSuggestMax(t *testing.T) {
+	dict := newTestDictionary(t)
+	dict.MaxSuggestions = 1
+
+	if suggestions := dict.Suggest("cts"); len(suggestions) != 1 {
+		t.Errorf("Suggest = %v, want 1 suggestion", suggestions)
+	}
+}
+
+func TestSpellCheck(t *testing.T) {
+	dict := newTestDictionary(t)
+
+	scr, err := dict.SpellCheck("the cta sat on the the box")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type flagged struct {
+		offset int
+		token  string
+		typ    string
+	}
+	var got []flagged
+	for _, token := range scr.FlaggedTokens {
+		got = append(got, flagged{token.Offset, token.Token, token.Type})
+	}
+
+	// "the", "sat" and "on" are not in the dictionary either
+	want := []flagged{
+		{0, "the", "UnknownToken"},
+		{4, "cta", "UnknownToken"},
+		{8, "sat", "UnknownToken"},
+		{12, "on", "UnknownToken"},
+		{15, "the", "UnknownToken"},
+		{19, "the", "RepeatedToken"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		pattern string
+		stem    string
+		want    bool
+	}{
+		{".", "anything", true},
+		{"y", "fly", true},
+		{"y", "flo", false},
+		{"[^aeiou]y", "fly", true},
+		{"[^aeiou]y", "day", false},
+		{"[sx]", "box", true},
+		{"[sx]", "cat", false},
+		{"ab", "b", false},
+	}
+
+	for _, tt := range tests {
+		conditions, err := parseCondition(tt.pattern)
+		if err != nil {
+			t.Fatalf("parseCondition(%q): %v", tt.pattern, err)
+		}
+		a := &affix{conditions: conditions}
+		if got := a.matchesStem(tt.stem); got != tt.want {
+			t.Errorf("condition %q on %q = %v, want %v", tt.pattern, tt.stem, got, tt.want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		encoding string
+		data     []byte
+		want     string
+		wantErr  bool
+	}{
+		{"UTF-8", []byte("café"), "café", false},
+		{"ISO8859-1", []byte{'c', 'a', 'f', 0xe9}, "café", false},
+		{"ISO-8859-1", []byte{'c', 'a', 'f', 0xe9}, "café", false},
+		{"ISO8859-2", []byte{0xb1}, "ą", false},
+		{"ISO8859-7", []byte{0xe1, 0xe2}, "αβ", false},
+		{"KOI8-R", []byte{0xc1, 0xc2}, "аб", false},
+		{"MICROSOFT-CP1251", []byte{0xe0}, "а", false},
+		{"ISO8859-11", []byte{0xa1}, "", true},
+		{"TIS-620", []byte{0xa1}, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := decode(tt.data, tt.encoding)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("decode(%s) error = %v, want error %v", tt.encoding, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("decode(%s) = %q, want %q", tt.encoding, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package hunspell
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxScanDistance is the largest edit distance considered when scanning the
+// word list for suggestions
+const maxScanDistance = 2
+
+// candidate is a possible suggestion for a misspelled word
+type candidate struct {
+	word     string
+	distance int
+	rep      bool
+}
+
+// Suggest returns suggested replacements for word, best first
+//
+//  Notes
+//    Candidates come from the REP table, from single edits (using the TRY
+//    characters) that are valid under the affix rules, and finally from
+//    dictionary stems within an edit distance of 2. They are ranked by edit
+//    distance, then REP matches, then by sharing the first letter of word.
+//
+func (dict *Dictionary) Suggest(word string) []string {
+	dict.mu.RLock()
+	defer dict.mu.RUnlock()
+
+	return dict.suggest(word)
+}
+
+// suggest must be called with mu held
+func (dict *Dictionary) suggest(word string) []string {
+	max := dict.MaxSuggestions
+	if max <= 0 {
+		max = DefaultMaxSuggestions
+	}
+
+	lower := strings.ToLower(word)
+	found := map[string]*candidate{}
+
+	add := func(suggestion string, rep bool) {
+		key := strings.ToLower(suggestion)
+		if suggestion == word || !dict.suggestible(suggestion) {
+			return
+		}
+		if c, ok := found[key]; ok {
+			c.rep = c.rep || rep
+			return
+		}
+		found[key] = &candidate{
+			word:     suggestion,
+			distance: editDistance([]rune(lower), []rune(key)),
+			rep:      rep,
+		}
+	}
+
+	for _, rep := range dict.aff.rep {
+		for i := strings.Index(lower, rep[0]); i >= 0; {
+			add(lower[:i]+rep[1]+lower[i+len(rep[0]):], true)
+			next := strings.Index(lower[i+1:], rep[0])
+			if next < 0 {
+				break
+			}
+			i += next + 1
+		}
+	}
+
+	for _, edit := range dict.edits(lower) {
+		add(edit, false)
+	}
+
+	if len(found) < max {
+		dict.scan(lower, add)
+	}
+
+	candidates := make([]*candidate, 0, len(found))
+	for _, c := range found {
+		candidates = append(candidates, c)
+	}
+
+	first, _ := firstRune(lower)
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		if a.rep != b.rep {
+			return a.rep
+		}
+		af, _ := firstRune(strings.ToLower(a.word))
+		bf, _ := firstRune(strings.ToLower(b.word))
+		if (af == first) != (bf == first) {
+			return af == first
+		}
+		return a.word < b.word
+	})
+
+	if len(candidates) > max {
+		candidates = candidates[:max]
+	}
+
+	suggestions := make([]string, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = matchCase(word, c.word)
+	}
+
+	return suggestions
+}
+
+// edits returns the single edits (delete, transpose, replace, insert) of word
+// that are spelled correctly
+func (dict *Dictionary) edits(word string) []string {
+	runes := []rune(word)
+	alphabet := []rune(dict.alphabet)
+
+	var results []string
+	try := func(candidate []rune) {
+		s := string(candidate)
+		if dict.spell(s) {
+			results = append(results, s)
+		}
+	}
+
+	buf := make([]rune, 0, len(runes)+1)
+	for i := range runes {
+		try(append(append(buf[:0], runes[:i]...), runes[i+1:]...))
+
+		if i+1 < len(runes) {
+			swapped := append(buf[:0], runes...)
+			swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+			try(swapped)
+		}
+
+		for _, r := range alphabet {
+			if r != runes[i] {
+				replaced := append(buf[:0], runes...)
+				replaced[i] = r
+				try(replaced)
+			}
+		}
+	}
+
+	for i := 0; i <= len(runes); i++ {
+		for _, r := range alphabet {
+			inserted := append(append(append(buf[:0], runes[:i]...), r), runes[i:]...)
+			try(inserted)
+		}
+	}
+
+	return results
+}
+
+// scan offers dictionary stems close to word
+func (dict *Dictionary) scan(word string, add func(string, bool)) {
+	runes := []rune(word)
+
+	for entry := range dict.words {
+		entryRunes := []rune(strings.ToLower(entry))
+		if abs(len(entryRunes)-len(runes)) > maxScanDistance {
+			continue
+		}
+		if editDistance(runes, entryRunes) <= maxScanDistance && dict.spell(entry) {
+			add(entry, false)
+		}
+	}
+}
+
+// suggestible reports whether a candidate may be offered as a suggestion
+func (dict *Dictionary) suggestible(word string) bool {
+	flags, ok := dict.words[word]
+	if !ok {
+		flags = dict.words[strings.ToLower(word)]
+	}
+
+	return !hasFlag(flags, dict.aff.noSuggest)
+}
+
+// matchCase applies the capitalization of original to suggestion
+func matchCase(original, suggestion string) string {
+	runes := []rune(original)
+	if len(runes) == 0 {
+		return suggestion
+	}
+
+	if isUpper(runes) && len(runes) > 1 {
+		return strings.ToUpper(suggestion)
+	}
+	if unicode.IsUpper(runes[0]) {
+		return capitalize(suggestion)
+	}
+
+	return suggestion
+}
+
+// editDistance is the Damerau-Levenshtein (optimal string alignment)
+// distance between a and b
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(minInt(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = minInt(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(b)]
+}
+
+func firstRune(s string) (rune, bool) {
+	for _, r := range s {
+		return r, true
+	}
+	return 0, false
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
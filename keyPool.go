@@ -0,0 +1,266 @@
+package bingSpellCheck
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrNoKeysAvailable is returned by KeyPool.Next when every key in the pool
+// is exhausted (or the pool is empty)
+var ErrNoKeysAvailable = errors.New("bingSpellCheck: no subscription keys available")
+
+// KeySelection is the strategy a KeyPool uses to choose the next key
+type KeySelection int
+
+const (
+	// RoundRobin uses each available key in turn
+	RoundRobin KeySelection = iota
+
+	// Weighted uses each available key in proportion to its Weight
+	Weighted
+)
+
+// DefaultKeyCooldown is the default time a key stays exhausted
+const DefaultKeyCooldown = time.Minute
+
+// PoolKey is a subscription key in a KeyPool
+//
+//  Fields
+//    Key      - The Bing subscription key
+//    Endpoint - The endpoint to use with the key (optional), either a host
+//      such as "https://westus.api.cognitive.microsoft.com" or a full spell
+//      check URL. When empty, the client's endpoint is used.
+//    Weight   - The relative share of requests for Weighted selection (values
+//      less than 1 are treated as 1)
+//
+type PoolKey struct {
+	Key      string
+	Endpoint string
+	Weight   int
+}
+
+// SpellCheckURL returns the spell check URL for the key's endpoint, or
+// defaultURL if the key has no endpoint
+func (key PoolKey) SpellCheckURL(defaultURL string) string {
+	if key.Endpoint == "" {
+		return defaultURL
+	}
+
+	u, err := url.Parse(key.Endpoint)
+	if err != nil || (u.Path != "" && u.Path != "/") {
+		return key.Endpoint
+	}
+
+	u.Path = BingSpellCheckPath
+	return u.String()
+}
+
+// PoolKeyStatus is the state of a key in a KeyPool
+type PoolKeyStatus struct {
+	PoolKey
+	Available      bool
+	ExhaustedUntil time.Time
+}
+
+type poolEntry struct {
+	PoolKey
+	exhaustedUntil time.Time
+	currentWeight  int
+}
+
+// KeyPool rotates requests across several subscription keys, skipping keys
+// that are exhausted
+//
+//  Notes
+//    A key is exhausted when it is rejected for authorization or for
+//    exceeding its rate limit or quota (see Client.WithKeyPool). It becomes
+//    available again after the cooldown. Keys can be added, removed and
+//    replaced at any time; a KeyPool is safe for concurrent use.
+//
+type KeyPool struct {
+	selection KeySelection
+	cooldown  time.Duration
+
+	mu      sync.Mutex
+	entries []*poolEntry
+	next    int
+}
+
+// NewKeyPool creates a KeyPool using selection to choose keys
+//
+//  Notes
+//    A cooldown of 0 uses DefaultKeyCooldown
+//
+func NewKeyPool(selection KeySelection, cooldown time.Duration, keys ...PoolKey) *KeyPool {
+	if cooldown <= 0 {
+		cooldown = DefaultKeyCooldown
+	}
+
+	pool := &KeyPool{selection: selection, cooldown: cooldown}
+	pool.Add(keys...)
+	return pool
+}
+
+// Next returns the next available key
+func (pool *KeyPool) Next() (PoolKey, error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	now := time.Now()
+
+	if pool.selection == Weighted {
+		// smooth weighted round robin
+		var best *poolEntry
+		total := 0
+		for _, entry := range pool.entries {
+			if entry.exhaustedUntil.After(now) {
+				continue
+			}
+			weight := entry.weight()
+			entry.currentWeight += weight
+			total += weight
+			if best == nil || entry.currentWeight > best.currentWeight {
+				best = entry
+			}
+		}
+		if best == nil {
+			return PoolKey{}, ErrNoKeysAvailable
+		}
+		best.currentWeight -= total
+		return best.PoolKey, nil
+	}
+
+	for i := 0; i < len(pool.entries); i++ {
+		entry := pool.entries[(pool.next+i)%len(pool.entries)]
+		if !entry.exhaustedUntil.After(now) {
+			pool.next = (pool.next + i + 1) % len(pool.entries)
+			return entry.PoolKey, nil
+		}
+	}
+
+	return PoolKey{}, ErrNoKeysAvailable
+}
+
+// Len returns the number of keys in the pool, exhausted or not
+func (pool *KeyPool) Len() int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return len(pool.entries)
+}
+
+// MarkExhausted makes key unavailable until the cooldown has passed
+func (pool *KeyPool) MarkExhausted(key string) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if entry := pool.find(key); entry != nil {
+		entry.exhaustedUntil = time.Now().Add(pool.cooldown)
+	}
+}
+
+// MarkAvailable makes key available immediately
+func (pool *KeyPool) MarkAvailable(key string) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if entry := pool.find(key); entry != nil {
+		entry.exhaustedUntil = time.Time{}
+	}
+}
+
+// Add adds keys to the pool, updating the endpoint and weight of keys that
+// are already in the pool
+func (pool *KeyPool) Add(keys ...PoolKey) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for _, key := range keys {
+		if entry := pool.find(key.Key); entry != nil {
+			entry.PoolKey = key
+		} else {
+			pool.entries = append(pool.entries, &poolEntry{PoolKey: key})
+		}
+	}
+}
+
+// Remove removes keys from the pool
+func (pool *KeyPool) Remove(keys ...string) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for _, key := range keys {
+		for i, entry := range pool.entries {
+			if entry.Key == key {
+				pool.entries = append(pool.entries[:i], pool.entries[i+1:]...)
+				break
+			}
+		}
+	}
+
+	if pool.next >= len(pool.entries) {
+		pool.next = 0
+	}
+}
+
+// Replace replaces every key in the pool with keys
+//
+//  Notes
+//    Keys that remain in the pool keep their exhausted state
+//
+func (pool *KeyPool) Replace(keys ...PoolKey) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	entries := make([]*poolEntry, 0, len(keys))
+	for _, key := range keys {
+		entry := pool.find(key.Key)
+		if entry == nil {
+			entry = &poolEntry{}
+		}
+		entry.PoolKey = key
+		entries = append(entries, entry)
+	}
+
+	pool.entries = entries
+	pool.next = 0
+}
+
+// Status returns the state of each key in the pool
+func (pool *KeyPool) Status() []PoolKeyStatus {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	now := time.Now()
+	status := make([]PoolKeyStatus, len(pool.entries))
+	for i, entry := range pool.entries {
+		status[i] = PoolKeyStatus{
+			PoolKey:        entry.PoolKey,
+			Available:      !entry.exhaustedUntil.After(now),
+			ExhaustedUntil: entry.exhaustedUntil,
+		}
+	}
+
+	return status
+}
+
+// find must be called with mu held
+func (pool *KeyPool) find(key string) *poolEntry {
+	for _, entry := range pool.entries {
+		if entry.Key == key {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+func (entry *poolEntry) weight() int {
+	if entry.Weight < 1 {
+		return 1
+	}
+
+	return entry.Weight
+}
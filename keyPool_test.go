@@ -0,0 +1,194 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// nextKeys returns the keys of the next n calls to pool.Next, with "" for
+// ErrNoKeysAvailable
+func nextKeys(t *testing.T, pool *KeyPool, n int) []string {
+	t.Helper()
+
+	var keys []string
+	for i := 0; i < n; i++ {
+		key, err := pool.Next()
+		if err != nil && err != ErrNoKeysAvailable {
+			t.Fatal(err)
+		}
+		keys = append(keys, key.Key)
+	}
+
+	return keys
+}
+
+func TestKeyPoolRotation(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection KeySelection
+		keys      []PoolKey
+		exhausted []string
+		want      []string
+	}{
+		{
+			name:      "round robin",
+			selection: RoundRobin,
+			keys:      []PoolKey{{Key: "a"}, {Key: "b"}, {Key: "c"}},
+			want:      []string{"a", "b", "c", "a", "b"},
+		},
+		{
+			name:      "round robin skips exhausted keys",
+			selection: RoundRobin,
+			keys:      []PoolKey{{Key: "a"}, {Key: "b"}, {Key: "c"}},
+			exhausted: []string{"b"},
+			want:      []string{"a", "c", "a", "c"},
+		},
+		{
+			name:      "weighted",
+			selection: Weighted,
+			keys:      []PoolKey{{Key: "a", Weight: 3}, {Key: "b", Weight: 1}},
+			want:      []string{"a", "a", "b", "a", "a", "a", "b", "a"},
+		},
+		{
+			name:      "weights less than 1 count as 1",
+			selection: Weighted,
+			keys:      []PoolKey{{Key: "a"}, {Key: "b", Weight: -5}},
+			want:      []string{"a", "b", "a", "b"},
+		},
+		{
+			name:      "weighted skips exhausted keys",
+			selection: Weighted,
+			keys:      []PoolKey{{Key: "a", Weight: 3}, {Key: "b", Weight: 1}},
+			exhausted: []string{"a"},
+			want:      []string{"b", "b"},
+		},
+		{
+			name:      "all exhausted",
+			selection: RoundRobin,
+			keys:      []PoolKey{{Key: "a"}, {Key: "b"}},
+			exhausted: []string{"a", "b"},
+			want:      []string{"", ""},
+		},
+		{
+			name:      "empty",
+			selection: Weighted,
+			want:      []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewKeyPool(tt.selection, time.Hour, tt.keys...)
+			for _, key := range tt.exhausted {
+				pool.MarkExhausted(key)
+			}
+
+			if got := nextKeys(t, pool, len(tt.want)); !equalStrings(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyPoolCooldown(t *testing.T) {
+	const cooldown = 50 * time.Millisecond
+	pool := NewKeyPool(RoundRobin, cooldown, PoolKey{Key: "a"}, PoolKey{Key: "b"})
+
+	pool.MarkExhausted("a")
+	pool.MarkExhausted("unknown")
+
+	status := pool.Status()
+	if status[0].Available || status[0].ExhaustedUntil.IsZero() || !status[1].Available {
+		t.Errorf("status = %+v", status)
+	}
+	if got := nextKeys(t, pool, 2); !equalStrings(got, []string{"b", "b"}) {
+		t.Errorf("during the cooldown got %q", got)
+	}
+
+	time.Sleep(2 * cooldown)
+
+	if !pool.Status()[0].Available {
+		t.Error("a is still exhausted after the cooldown")
+	}
+	if got := nextKeys(t, pool, 2); !equalStrings(got, []string{"a", "b"}) {
+		t.Errorf("after the cooldown got %q", got)
+	}
+
+	pool.MarkExhausted("b")
+	pool.MarkAvailable("b")
+	if !pool.Status()[1].Available {
+		t.Error("MarkAvailable did not end the cooldown")
+	}
+
+	if pool := NewKeyPool(RoundRobin, 0); pool.cooldown != DefaultKeyCooldown {
+		t.Errorf("cooldown = %s, want %s", pool.cooldown, DefaultKeyCooldown)
+	}
+}
+
+func TestKeyPoolChanges(t *testing.T) {
+	pool := NewKeyPool(RoundRobin, time.Hour, PoolKey{Key: "a"}, PoolKey{Key: "b"}, PoolKey{Key: "c"})
+	pool.MarkExhausted("b")
+
+	pool.Add(PoolKey{Key: "a", Endpoint: "https://westus.api.cognitive.microsoft.com"}, PoolKey{Key: "d"})
+	if pool.Len() != 4 {
+		t.Errorf("Len = %d after Add, want 4", pool.Len())
+	}
+	if got := pool.Status()[0].Endpoint; got == "" {
+		t.Error("Add did not update the endpoint of a")
+	}
+
+	pool.Remove("c", "unknown")
+	if got := nextKeys(t, pool, 3); !equalStrings(got, []string{"a", "d", "a"}) {
+		t.Errorf("after Remove got %q", got)
+	}
+
+	// b keeps its cooldown, e is new
+	pool.Replace(PoolKey{Key: "b"}, PoolKey{Key: "e"})
+	if got := nextKeys(t, pool, 2); !equalStrings(got, []string{"e", "e"}) {
+		t.Errorf("after Replace got %q", got)
+	}
+
+	pool.Remove("b", "e")
+	if _, err := pool.Next(); err != ErrNoKeysAvailable {
+		t.Errorf("Next on an empty pool = %v", err)
+	}
+}
+
+func TestPoolKeySpellCheckURL(t *testing.T) {
+	const defaultURL = "https://api.cognitive.microsoft.com" + BingSpellCheckPath
+
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"", defaultURL},
+		{"https://westus.api.cognitive.microsoft.com", "https://westus.api.cognitive.microsoft.com" + BingSpellCheckPath},
+		{"https://westus.api.cognitive.microsoft.com/", "https://westus.api.cognitive.microsoft.com" + BingSpellCheckPath},
+		{"https://proxy.example.com/spell", "https://proxy.example.com/spell"},
+	}
+
+	for _, tt := range tests {
+		if got := (PoolKey{Key: "key", Endpoint: tt.endpoint}).SpellCheckURL(defaultURL); got != tt.want {
+			t.Errorf("SpellCheckURL(%q) = %q, want %q", tt.endpoint, got, tt.want)
+		}
+	}
+}
+
+func TestClientKeyPoolRotation(t *testing.T) {
+	srv := keyServer(t, map[string]int{"limited": http.StatusTooManyRequests})
+	pool := NewKeyPool(RoundRobin, time.Hour, PoolKey{Key: "limited"}, PoolKey{Key: "good"})
+	client := NewClient("").WithEndpoint(srv.URL).WithKeyPool(pool)
+
+	for i := 0; i < 3; i++ {
+		scr, err := client.Check(context.Background(), "text", nil)
+		if err != nil || scr.IsErrorResponse() {
+			t.Fatalf("check %d: got %+v, %v", i, scr, err)
+		}
+	}
+
+	if status := pool.Status(); status[0].Available || !status[1].Available {
+		t.Errorf("status = %+v, want only the rate limited key exhausted", status)
+	}
+}
@@ -0,0 +1,168 @@
+package bingSpellCheck
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Detection is the result of language identification
+//
+//  Fields
+//    Language   - The ISO 639-1 code of the detected language, or "" if the
+//      language could not be identified
+//    Confidence - From 0 (a guess) to 1 (certain)
+//    Market     - The default market for Language (see MatchMarket), or ""
+//      if there is no supported market for it
+//
+type Detection struct {
+	Language   string
+	Confidence float64
+	Market     MarketCode
+}
+
+// ngramProfile is a normalized vector of character trigram frequencies
+type ngramProfile struct {
+	counts map[string]float64
+	norm   float64
+}
+
+var languageProfiles = map[string]*ngramProfile{}
+
+func init() {
+	for lang, sample := range languageSamples {
+		languageProfiles[lang] = newNgramProfile(sample)
+	}
+}
+
+// newNgramProfile counts the character trigrams of the words in text, with
+// word boundaries marked by spaces
+func newNgramProfile(text string) *ngramProfile {
+	profile := &ngramProfile{counts: map[string]float64{}}
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			profile.counts[string(runes[i:i+3])]++
+		}
+	}
+
+	for _, count := range profile.counts {
+		profile.norm += count * count
+	}
+	profile.norm = math.Sqrt(profile.norm)
+
+	return profile
+}
+
+// similarity is the cosine similarity of two profiles
+func (profile *ngramProfile) similarity(other *ngramProfile) float64 {
+	if profile.norm == 0 || other.norm == 0 {
+		return 0
+	}
+
+	dot := 0.0
+	for ngram, count := range profile.counts {
+		dot += count * other.counts[ngram]
+	}
+
+	return dot / (profile.norm * other.norm)
+}
+
+// DetectLanguage identifies the language of text, offline
+//
+//  Notes
+//    Japanese, Korean, Chinese and Russian are identified by script. Other
+//    languages with a supported market are identified by comparing character
+//    trigram frequencies with built-in profiles. Confidence reflects both
+//    how much better the best match is than the runner up and how much text
+//    there was to go on; texts of a few words are unreliable.
+//
+func DetectLanguage(text string) Detection {
+	if lang, ok := detectScript(text); ok {
+		return newDetection(lang, 1)
+	}
+
+	profile := newNgramProfile(text)
+	if profile.norm == 0 {
+		return Detection{}
+	}
+
+	type score struct {
+		lang       string
+		similarity float64
+	}
+
+	scores := make([]score, 0, len(languageProfiles))
+	for lang, languageProfile := range languageProfiles {
+		scores = append(scores, score{lang, profile.similarity(languageProfile)})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].similarity != scores[j].similarity {
+			return scores[i].similarity > scores[j].similarity
+		}
+		return scores[i].lang < scores[j].lang
+	})
+
+	best, second := scores[0], scores[1]
+	if best.similarity == 0 {
+		return Detection{}
+	}
+
+	margin := (best.similarity - second.similarity) / best.similarity
+
+	// about 60 trigrams (a sentence or two) are needed for a reliable answer
+	total := 0.0
+	for _, count := range profile.counts {
+		total += count
+	}
+	support := math.Min(1, total/60)
+
+	return newDetection(best.lang, math.Min(1, margin*3)*support)
+}
+
+func newDetection(lang string, confidence float64) Detection {
+	market, _ := MatchMarket(lang)
+	return Detection{Language: lang, Confidence: confidence, Market: market}
+}
+
+// detectScript identifies languages that can be told apart by script alone
+func detectScript(text string) (string, bool) {
+	var letters, kana, hangul, han, cyrillic int
+
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case !unicode.IsLetter(r):
+			continue
+		}
+		letters++
+	}
+
+	if letters == 0 {
+		return "", false
+	}
+
+	switch {
+	case kana > 0 && (kana+han)*2 > letters:
+		return "ja", true
+	case hangul*2 > letters:
+		return "ko", true
+	case han*2 > letters:
+		return "zh", true
+	case cyrillic*2 > letters:
+		return "ru", true
+	}
+
+	return "", false
+}
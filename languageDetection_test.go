@@ -0,0 +1,59 @@
+package bingSpellCheck
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text     string
+		language string
+		market   MarketCode
+	}{
+		{"The weather was nice, so we went for a walk in the park with the children after lunch.", "en", MktUnitedStates},
+		{"El tiempo era agradable, así que fuimos a dar un paseo por el parque con los niños después de comer.", "es", MktSpain},
+		{"Il faisait beau, alors nous sommes allés nous promener dans le parc avec les enfants après le déjeuner.", "fr", MktFrance},
+		{"Das Wetter war schön, also sind wir nach dem Mittagessen mit den Kindern im Park spazieren gegangen.", "de", MktGermany},
+		{"Il tempo era bello, quindi dopo pranzo siamo andati a fare una passeggiata nel parco con i bambini.", "it", MktItaly},
+		{"O tempo estava bom, então fomos dar um passeio no parque com as crianças depois do almoço.", "pt", MktBrazil},
+		{"Het weer was mooi, dus zijn we na de lunch met de kinderen in het park gaan wandelen.", "nl", MktNetherlands},
+		{"今日はいい天気なので、公園を散歩しました。", "ja", MktJapan},
+		{"오늘은 날씨가 좋아서 공원을 산책했습니다.", "ko", MktKorea},
+		{"今天天气很好，我们去公园散步了。", "zh", MktChina},
+		{"Погода была хорошая, и мы пошли гулять в парк.", "ru", MktRussia},
+	}
+
+	for _, tt := range tests {
+		got := DetectLanguage(tt.text)
+		if got.Language != tt.language || got.Market != tt.market {
+			t.Errorf("DetectLanguage(%q) = %+v, want %s (%s)", tt.text, got, tt.language, tt.market)
+		}
+		if got.Confidence <= 0 || got.Confidence > 1 {
+			t.Errorf("DetectLanguage(%q) confidence = %v", tt.text, got.Confidence)
+		}
+	}
+}
+
+func TestDetectLanguageConfidence(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		min  float64
+		max  float64
+	}{
+		{"script", "公園を散歩しました", 1, 1},
+		{"long text", "The weather was nice, so we went for a walk in the park with the children after lunch, and then we went home to read the books that we had found there.", 0.5, 1},
+		{"a few words", "the park", 0, 0.3},
+		{"no letters", "12345 !?", 0, 0},
+		{"empty", "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		got := DetectLanguage(tt.text)
+		if got.Confidence < tt.min || got.Confidence > tt.max {
+			t.Errorf("%s: confidence %v, want %v to %v (%+v)", tt.name, got.Confidence, tt.min, tt.max, got)
+		}
+	}
+
+	if got := DetectLanguage("12345"); got != (Detection{}) {
+		t.Errorf("DetectLanguage of digits = %+v, want nothing", got)
+	}
+}
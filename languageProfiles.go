@@ -0,0 +1,158 @@
+package bingSpellCheck
+
+// languageSamples is the training text for the n-gram language profiles used
+// by DetectLanguage, keyed by ISO 639-1 language code
+//
+//  Notes
+//    Only languages written in the Latin alphabet need samples; Japanese,
+//    Korean, Chinese and Russian are detected by script. The samples favor
+//    common function words, which carry most of the signal in short texts.
+//
+var languageSamples = map[string]string{
+	"en": `The quick brown fox jumps over the lazy dog. This is a short text that
+		we use to learn which letters and words are common in English. It is not
+		about anything in particular, but it should have the words that people
+		write every day: the, and, of, to, in, that, it, with, for, was, on, are,
+		as, they, be, at, one, have, this, from, or, had, by, not, but, what, all,
+		were, when, we, there, can, an, your, which, their, said, if, will, each,
+		about, how, up, out, them, then, she, many, some, so, these, would, other,
+		into, has, more, her, two, like, him, see, time, could, no, make, than,
+		first, been, its, who, now, people, my, made, over, did, down, only, way,
+		find, use, may, water, long, little, very, after, words, called, just,
+		where, most, know, through, should, thought, right, things, nothing.`,
+
+	"es": `El rápido zorro marrón salta sobre el perro perezoso. Este es un texto
+		corto que usamos para aprender qué letras y palabras son comunes en
+		español. No trata de nada en particular, pero debe tener las palabras que
+		la gente escribe todos los días: de, la, que, el, en, y, a, los, se, del,
+		las, un, por, con, no, una, su, para, es, al, lo, como, más, pero, sus, le,
+		ya, o, este, sí, porque, esta, entre, cuando, muy, sin, sobre, también, me,
+		hasta, hay, donde, quien, desde, todo, nos, durante, todos, uno, les, ni,
+		contra, otros, ese, eso, ante, ellos, e, esto, mí, antes, algunos, qué,
+		unos, yo, otro, otras, otra, él, tanto, esa, estos, mucho, quienes, nada,
+		muchos, cual, poco, ella, estar, estas, algunas, algo, nosotros, año.`,
+
+	"fr": `Le renard brun rapide saute par-dessus le chien paresseux. Ceci est un
+		court texte que nous utilisons pour apprendre quelles lettres et quels
+		mots sont courants en français. Il ne parle de rien en particulier, mais
+		il devrait contenir les mots que les gens écrivent tous les jours : de,
+		la, le, et, les, des, en, un, du, une, que, est, pour, qui, dans, par, plus,
+		pas, au, sur, ne, se, ce, il, sont, avec, son, aux, mais, comme, ou, été,
+		elle, nous, vous, leur, bien, aussi, fait, très, cette, tout, deux, peut,
+		sans, entre, même, encore, après, avant, être, avoir, faire, dire, aller,
+		voir, savoir, pouvoir, falloir, vouloir, toujours, jamais, beaucoup,
+		quelque, chose, rien, parce, pourquoi, comment, quand, où, année.`,
+
+	"de": `Der schnelle braune Fuchs springt über den faulen Hund. Dies ist ein
+		kurzer Text, mit dem wir lernen, welche Buchstaben und Wörter im Deutschen
+		häufig sind. Er handelt von nichts Besonderem, aber er sollte die Wörter
+		enthalten, die Menschen jeden Tag schreiben: der, die, und, in, den, von,
+		zu, das, mit, sich, des, auf, für, ist, im, dem, nicht, ein, eine, als,
+		auch, es, an, werden, aus, er, hat, dass, sie, nach, wird, bei, einer, um,
+		am, sind, noch, wie, einem, über, einen, so, zum, war, haben, nur, oder,
+		aber, vor, zur, bis, mehr, durch, man, sein, wurde, sei, hatte, kann,
+		gegen, vom, können, schon, wenn, habe, seine, ihre, dann, unter, wir,
+		soll, ich, eines, jahr, zwei, jahren, diese, dieser, wieder, keine.`,
+
+	"it": `La veloce volpe marrone salta sopra il cane pigro. Questo è un breve
+		testo che usiamo per imparare quali lettere e parole sono comuni in
+		italiano. Non parla di niente in particolare, ma dovrebbe contenere le
+		parole che la gente scrive ogni giorno: di, e, il, la, che, in, a, per,
+		un, è, del, non, una, con, i, da, si, le, sono, al, della, lo, gli, come,
+		ha, ma, più, anche, se, nel, questo, alla, dei, delle, ci, su, loro, tutto,
+		essere, fare, molto, quando, perché, dove, ancora, sempre, mai, niente,
+		qualcosa, questa, quello, quella, degli, nella, sulla, tra, fra, dopo,
+		prima, senza, oggi, domani, ieri, anno, cosa, casa, tempo, giorno, volta.`,
+
+	"pt": `A rápida raposa marrom pula sobre o cão preguiçoso. Este é um texto
+		curto que usamos para aprender quais letras e palavras são comuns em
+		português. Não trata de nada em particular, mas deve ter as palavras que
+		as pessoas escrevem todos os dias: de, a, o, que, e, do, da, em, um, para,
+		é, com, não, uma, os, no, se, na, por, mais, as, dos, como, mas, foi, ao,
+		ele, das, tem, à, seu, sua, ou, ser, quando, muito, há, nos, já, está, eu,
+		também, só, pelo, pela, até, isso, ela, entre, era, depois, sem, mesmo,
+		aos, ter, seus, quem, nas, me, esse, eles, estão, você, tinha, foram,
+		essa, num, nem, suas, meu, às, minha, têm, numa, pelos, elas, havia,
+		seja, qual, será, nós, tenho, lhe, deles, essas, esses, pelas, este.
+		Não sei onde fica a estação, então vou perguntar ao senhor que trabalha
+		ali: informação, atenção, situação, relação, ações, opções, questões,
+		irmão, coração, mãe, pão, melhor, trabalho, filho, olho, senhora, vizinho,
+		amanhã, hoje, agora, ainda, aqui, lá, obrigado, você, vocês, gente.`,
+
+	"nl": `De snelle bruine vos springt over de luie hond. Dit is een korte tekst
+		die we gebruiken om te leren welke letters en woorden veel voorkomen in
+		het Nederlands. Hij gaat nergens in het bijzonder over, maar hij moet de
+		woorden bevatten die mensen elke dag schrijven: de, en, van, ik, te, dat,
+		die, in, een, hij, het, niet, zijn, is, was, op, aan, met, als, voor, had,
+		er, maar, om, hem, dan, zou, of, wat, mijn, men, dit, zo, door, over, ze,
+		zich, bij, ook, tot, je, mij, uit, der, daar, haar, naar, heb, hoe, heeft,
+		hebben, deze, u, want, nog, zal, me, zij, nu, ge, geen, omdat, iets,
+		worden, toch, al, waren, veel, meer, doen, toen, moet, ben, zonder, kan,
+		hun, dus, alles, onder, ja, eens, hier, wie, werd, altijd, doch, wordt.`,
+
+	"da": `Den hurtige brune ræv springer over den dovne hund. Dette er en kort
+		tekst, som vi bruger til at lære, hvilke bogstaver og ord der er almindelige
+		på dansk. Den handler ikke om noget bestemt, men den bør indeholde de ord,
+		som folk skriver hver dag: og, i, jeg, det, at, en, den, til, er, som, på,
+		de, med, han, af, for, ikke, der, var, mig, sig, men, et, har, om, vi, min,
+		havde, ham, hun, nu, over, da, fra, du, ud, sin, dem, os, op, man, hans,
+		hvor, eller, hvad, skal, selv, her, alle, vil, blev, kunne, ind, når,
+		være, dog, noget, ville, jo, deres, efter, ned, skulle, denne, end, dette,
+		mit, også, under, have, dig, anden, hende, mine, alt, meget, sit, sine.
+		Jeg ved ikke, hvor den nærmeste station ligger, så jeg spørger manden
+		derovre: hvornår, hvorfor, hvordan, hvilken, nogle, mange, gerne, vide,
+		fordi, aften, morgen, dag, nat, godt, tak, mennesker, køre, gå, nå.`,
+
+	"sv": `Den snabba bruna räven hoppar över den lata hunden. Det här är en kort
+		text som vi använder för att lära oss vilka bokstäver och ord som är
+		vanliga på svenska. Den handlar inte om något särskilt, men den bör
+		innehålla de ord som människor skriver varje dag: och, i, att, det, som,
+		en, på, är, av, för, med, till, den, har, de, inte, om, ett, han, men,
+		var, jag, sig, från, vi, så, kan, man, när, år, säger, hon, under, också,
+		efter, eller, nu, sin, där, vid, mot, ska, skulle, kommer, ut, får, finns,
+		vara, hade, alla, andra, mycket, än, här, då, sedan, över, bara, blir,
+		upp, även, vad, få, två, vill, ha, många, hur, mer, går, sverige, kronor,
+		detta, nya, procent, skall, hans, utan, sina, något, svenska, allt.`,
+
+	"no": `Den raske brune reven hopper over den late hunden. Dette er en kort
+		tekst som vi bruker for å lære hvilke bokstaver og ord som er vanlige på
+		norsk. Den handler ikke om noe spesielt, men den bør inneholde de ordene
+		som folk skriver hver dag: og, i, jeg, det, at, en, et, den, til, er, som,
+		på, de, med, han, av, ikke, der, så, var, meg, seg, men, ett, har, om, vi,
+		min, mitt, ha, hadde, hun, nå, over, da, ved, fra, du, ut, sin, dem, oss,
+		opp, man, kan, hans, hvor, eller, hva, skal, selv, sjøl, her, alle, vil,
+		bli, ble, blitt, kunne, inn, når, være, kom, noen, noe, ville, dere,
+		deres, kun, ja, etter, ned, skulle, denne, for, deg, si, sine, sitt, mot.
+		Jeg vet ikke hvor den nærmeste stasjonen ligger, så jeg spør mannen der
+		borte: hvorfor, hvordan, hvilken, noen, mange, gjerne, vite, fordi,
+		kveld, morgen, dag, natt, godt, takk, mennesker, kjøre, gå, nå, ikkje.`,
+
+	"fi": `Nopea ruskea kettu hyppää laiskan koiran yli. Tämä on lyhyt teksti,
+		jonka avulla opimme, mitkä kirjaimet ja sanat ovat yleisiä suomen
+		kielessä. Se ei kerro mistään erityisestä, mutta siinä pitäisi olla ne
+		sanat, joita ihmiset kirjoittavat joka päivä: ja, on, ei, että, se, oli,
+		hän, ovat, mutta, kun, niin, myös, kuin, tai, jos, vain, sen, hänen, ole,
+		mitä, tämä, nyt, jo, sitten, olla, koska, voi, siitä, kanssa, sekä,
+		minä, sinä, me, te, he, mikä, joka, jotka, tässä, siinä, täällä, siellä,
+		vielä, aina, koskaan, paljon, vähän, hyvin, enemmän, kaikki, jotain,
+		mitään, kuka, miksi, miten, missä, milloin, vuosi, päivä, aika, talo.`,
+
+	"pl": `Szybki brązowy lis przeskakuje nad leniwym psem. To jest krótki tekst,
+		którego używamy, aby nauczyć się, które litery i słowa są popularne w
+		języku polskim. Nie dotyczy niczego szczególnego, ale powinien zawierać
+		słowa, które ludzie piszą codziennie: i, w, nie, na, się, z, do, to, że,
+		jest, o, jak, ale, po, co, tak, za, od, już, jego, jej, tylko, przez,
+		może, ich, czy, dla, tym, był, była, było, są, być, bardzo, jeszcze,
+		kiedy, gdzie, który, która, które, także, jednak, między, przed, pod,
+		nad, bez, wszystko, nic, coś, ktoś, dlaczego, zawsze, nigdy, teraz, rok,
+		dzień, czas, dom, ludzie, świat, życie, praca, dziecko, miasto, kraj.`,
+
+	"tr": `Hızlı kahverengi tilki tembel köpeğin üzerinden atlar. Bu, Türkçede
+		hangi harflerin ve kelimelerin yaygın olduğunu öğrenmek için
+		kullandığımız kısa bir metindir. Belirli bir konu hakkında değildir, ama
+		insanların her gün yazdığı kelimeleri içermelidir: ve, bir, bu, da, de,
+		için, ile, çok, ne, daha, gibi, o, ama, en, var, olarak, sonra, kadar,
+		değil, ben, sen, biz, siz, onlar, her, şey, yok, mı, mi, mu, mü, nasıl,
+		neden, nerede, zaman, şimdi, bugün, yarın, dün, yıl, gün, ev, iş, insan,
+		hayat, dünya, çocuk, şehir, ülke, güzel, büyük, küçük, yeni, eski, iyi.`,
+}
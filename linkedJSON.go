@@ -0,0 +1,158 @@
+package bingSpellCheck
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// LinkedContext is the JSON-LD @context of Bing API responses
+const LinkedContext = `{"@vocab":"http://bingapis.com/v7/schemas/","s":"http://schema.org/","@base":"https://api.cognitive.microsoft.com/api/v7/"}`
+
+// LinkedNode holds the JSON-LD keywords of an object in a JSON-LD response
+//
+//  Fields
+//    LinkedType - The type of the object (the JSON-LD equivalent of _type)
+//    ID         - The identifier of the object, if it has one
+//
+type LinkedNode struct {
+	LinkedType string `json:"@type,omitempty"`
+	ID         string `json:"@id,omitempty"`
+}
+
+// LinkedTokenSuggestion is a TokenSuggestion in a JSON-LD response
+type LinkedTokenSuggestion struct {
+	LinkedNode
+	TokenSuggestion
+}
+
+// LinkedFlaggedToken is a FlaggedToken in a JSON-LD response
+type LinkedFlaggedToken struct {
+	LinkedNode
+	FlaggedToken
+	Suggestions []LinkedTokenSuggestion `json:"suggestions"`
+}
+
+// LinkedError is an Error in a JSON-LD response
+type LinkedError struct {
+	LinkedNode
+	Error
+}
+
+// LinkedSpellCheckResponse is the JSON-LD (application/ld+json) form of a
+// SpellCheckResponse, returned when the Accept header is
+// AcceptApplicationLinkedJSON (see SpellCheckHeaders.WithLinkedJSON)
+//
+//  Notes
+//    Context is kept raw because JSON-LD allows it to be a string, an object
+//    or an array. Use SpellCheckResponse for the normalized view.
+//
+type LinkedSpellCheckResponse struct {
+	Context json.RawMessage `json:"@context,omitempty"`
+	LinkedNode
+	FlaggedTokens []LinkedFlaggedToken `json:"flaggedTokens"`
+	Errors        []LinkedError        `json:"errors,omitempty"`
+}
+
+// SpellCheckResponse returns the normalized (plain JSON) form of the
+// response
+//
+//  Notes
+//    Compact IRI and absolute IRI types are reduced to their final term,
+//    e.g. "s:SpellCheck" or "http://bingapis.com/v7/schemas/SpellCheck"
+//    become "SpellCheck"
+//
+func (lr *LinkedSpellCheckResponse) SpellCheckResponse() *SpellCheckResponse {
+	scr := &SpellCheckResponse{Type: linkedTerm(lr.LinkedType), Linked: lr}
+
+	if lr.FlaggedTokens != nil {
+		scr.FlaggedTokens = make([]FlaggedToken, len(lr.FlaggedTokens))
+		for i, linkedToken := range lr.FlaggedTokens {
+			token := linkedToken.FlaggedToken
+			token.Suggestions = make([]TokenSuggestion, len(linkedToken.Suggestions))
+			for j, suggestion := range linkedToken.Suggestions {
+				token.Suggestions[j] = suggestion.TokenSuggestion
+			}
+			scr.FlaggedTokens[i] = token
+		}
+	}
+
+	if lr.Errors != nil {
+		scr.Errors = make([]Error, len(lr.Errors))
+		for i, err := range lr.Errors {
+			scr.Errors[i] = err.Error
+		}
+	}
+
+	if scr.Type == "" && len(scr.Errors) > 0 {
+		scr.Type = ErrorResponseType
+	}
+
+	return scr
+}
+
+// NewLinkedSpellCheckResponse returns the JSON-LD form of scr, with
+// LinkedContext as its @context
+func NewLinkedSpellCheckResponse(scr *SpellCheckResponse) *LinkedSpellCheckResponse {
+	lr := &LinkedSpellCheckResponse{
+		Context:    json.RawMessage(LinkedContext),
+		LinkedNode: LinkedNode{LinkedType: scr.Type},
+	}
+
+	if scr.FlaggedTokens != nil {
+		lr.FlaggedTokens = make([]LinkedFlaggedToken, len(scr.FlaggedTokens))
+		for i, token := range scr.FlaggedTokens {
+			linkedToken := LinkedFlaggedToken{FlaggedToken: token}
+			linkedToken.FlaggedToken.Suggestions = nil
+			linkedToken.Suggestions = make([]LinkedTokenSuggestion, len(token.Suggestions))
+			for j, suggestion := range token.Suggestions {
+				linkedToken.Suggestions[j] = LinkedTokenSuggestion{TokenSuggestion: suggestion}
+			}
+			lr.FlaggedTokens[i] = linkedToken
+		}
+	}
+
+	if scr.Errors != nil {
+		lr.Errors = make([]LinkedError, len(scr.Errors))
+		for i, err := range scr.Errors {
+			lr.Errors[i] = LinkedError{LinkedNode: LinkedNode{LinkedType: "Error"}, Error: err}
+		}
+	}
+
+	return lr
+}
+
+// isLinkedJSON determines if the response content type is JSON-LD
+func isLinkedJSON(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediaType == AcceptApplicationLinkedJSON
+}
+
+// parseSpellCheckResponse parses a response body as JSON-LD or plain JSON,
+// based on the response content type
+func parseSpellCheckResponse(header http.Header, body []byte) (*SpellCheckResponse, error) {
+	if isLinkedJSON(header) {
+		var linked LinkedSpellCheckResponse
+		if err := json.Unmarshal(body, &linked); err != nil {
+			return nil, err
+		}
+		return linked.SpellCheckResponse(), nil
+	}
+
+	var spellCheck SpellCheckResponse
+	if err := json.Unmarshal(body, &spellCheck); err != nil {
+		return nil, err
+	}
+
+	return &spellCheck, nil
+}
+
+// linkedTerm returns the final term of a JSON-LD type
+func linkedTerm(linkedType string) string {
+	if i := strings.LastIndexAny(linkedType, ":/#"); i >= 0 {
+		return linkedType[i+1:]
+	}
+
+	return linkedType
+}
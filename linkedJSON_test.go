@@ -0,0 +1,184 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const linkedSpellCheck = `{
+  "@context": ` + LinkedContext + `,
+  "@type": "s:SpellCheck",
+  "flaggedTokens": [{
+    "@type": "FlaggedToken",
+    "offset": 3, "token": "teh", "type": "UnknownToken",
+    "suggestions": [{"@type": "http://bingapis.com/v7/schemas/TokenSuggestion", "suggestion": "the", "score": 0.9}]
+  }]
+}`
+
+func TestParseSpellCheckResponse(t *testing.T) {
+	jsonLD := header("Content-Type", AcceptApplicationLinkedJSON+"; charset=utf-8")
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		body    string
+		want    *SpellCheckResponse
+		linked  bool
+		wantErr bool
+	}{
+		{
+			name:   "json-ld",
+			header: jsonLD,
+			body:   linkedSpellCheck,
+			want:   spellCheckResponse(unknown(3, "teh", "the")),
+			linked: true,
+		},
+		{
+			name:   "json-ld error",
+			header: jsonLD,
+			body:   `{"errors": [{"@type": "Error", "code": "InvalidRequest", "message": "bad", "parameter": "text"}]}`,
+			want:   &SpellCheckResponse{Type: ErrorResponseType, Errors: []Error{{Code: "InvalidRequest", Message: "bad", Parameter: "text"}}},
+			linked: true,
+		},
+		{
+			name:   "json",
+			header: header("Content-Type", "application/json"),
+			body:   `{"_type": "SpellCheck", "flaggedTokens": [{"offset": 3, "token": "teh", "type": "UnknownToken", "suggestions": [{"suggestion": "the", "score": 0.9}]}]}`,
+			want:   spellCheckResponse(unknown(3, "teh", "the")),
+		},
+		{
+			name:    "invalid json-ld",
+			header:  jsonLD,
+			body:    `{"flaggedTokens": {}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSpellCheckResponse(tt.header, []byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if (got.Linked != nil) != tt.linked {
+				t.Errorf("Linked = %v, want it set %v", got.Linked, tt.linked)
+			}
+			got.Linked = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkedSpellCheckResponseRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		scr  *SpellCheckResponse
+	}{
+		{"tokens", spellCheckResponse(unknown(0, "Teh", "The", "Ten"), FlaggedToken{Offset: 8, Token: "is is", Type: RepeatedTokenType})},
+		{"no tokens", spellCheckResponse()},
+		{"errors", &SpellCheckResponse{Type: ErrorResponseType, Errors: []Error{{Code: "RateLimitExceeded", Message: "slow down"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linked := NewLinkedSpellCheckResponse(tt.scr)
+			if string(linked.Context) != LinkedContext {
+				t.Errorf("@context = %s", linked.Context)
+			}
+
+			body, err := json.Marshal(linked)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var parsed LinkedSpellCheckResponse
+			if err := json.Unmarshal(body, &parsed); err != nil {
+				t.Fatal(err)
+			}
+
+			got := parsed.SpellCheckResponse()
+			if got.Linked != &parsed {
+				t.Error("Linked is not the JSON-LD response")
+			}
+			// nil and empty slices are equivalent in a response
+			got.Linked = nil
+			if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", tt.scr) {
+				t.Errorf("round trip of %s\ngot  %+v\nwant %+v", body, got, tt.scr)
+			}
+		})
+	}
+}
+
+func TestLinkedTerm(t *testing.T) {
+	tests := []struct {
+		linkedType string
+		want       string
+	}{
+		{"SpellCheck", "SpellCheck"},
+		{"s:SpellCheck", "SpellCheck"},
+		{"http://bingapis.com/v7/schemas/SpellCheck", "SpellCheck"},
+		{"http://schema.org/#Thing", "Thing"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := linkedTerm(tt.linkedType); got != tt.want {
+			t.Errorf("linkedTerm(%q) = %q, want %q", tt.linkedType, got, tt.want)
+		}
+	}
+}
+
+func TestIsLinkedJSON(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"application/ld+json", true},
+		{"Application/LD+JSON; charset=utf-8", true},
+		{"application/json", false},
+		{"", false},
+		{"application/ld+json; =", false},
+	}
+
+	for _, tt := range tests {
+		if got := isLinkedJSON(header("Content-Type", tt.contentType)); got != tt.want {
+			t.Errorf("isLinkedJSON(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestClientLinkedJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get(AcceptHeader); got != AcceptApplicationLinkedJSON {
+			t.Errorf("Accept = %q", got)
+		}
+		w.Header().Set("Content-Type", AcceptApplicationLinkedJSON)
+		w.Write([]byte(linkedSpellCheck))
+	}))
+	defer srv.Close()
+
+	client := NewClient("key").WithEndpoint(srv.URL)
+	client.Headers.WithLinkedJSON()
+
+	scr, err := client.Check(context.Background(), "Is teh data good?", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scr.Type != SpellCheckResponseType || len(scr.FlaggedTokens) != 1 || scr.FlaggedTokens[0].Suggestions[0].Suggestion != "the" {
+		t.Errorf("got %+v", scr)
+	}
+	if scr.Linked == nil || scr.Linked.FlaggedTokens[0].LinkedType != "FlaggedToken" {
+		t.Errorf("Linked = %+v", scr.Linked)
+	}
+}
@@ -0,0 +1,258 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DefaultBaselineFile is the conventional name of a baseline file
+const DefaultBaselineFile = ".bingspell-baseline.json"
+
+// BaselineEntry is a finding recorded in a baseline
+//
+//  Fields
+//    Path        - The file of the finding (with forward slashes)
+//    Token       - The flagged token
+//    Fingerprint - The Fingerprint of the finding
+//    Count       - The number of identical findings (e.g. a word repeated on
+//                  a line), omitted when 1
+//
+type BaselineEntry struct {
+	Path        string `json:"path"`
+	Token       string `json:"token"`
+	Fingerprint string `json:"fingerprint"`
+	Count       int    `json:"count,omitempty"`
+}
+
+// baselineKey identifies the findings of an entry
+type baselineKey struct {
+	path        string
+	token       string
+	fingerprint string
+}
+
+// Baseline is a set of accepted (usually pre-existing) findings, so a
+// codebase can be checked in CI without first fixing every legacy finding
+//
+//  Notes
+//    Findings are matched by file, token and Fingerprint rather than by
+//    position, so a baselined finding stays suppressed when lines are added
+//    above it, and is reported again once its line is edited.
+//
+//    Paths are compared as given, so findings should be reported with the
+//    same paths (e.g. relative to the repository root) when the baseline is
+//    created and when it is used
+//
+type Baseline struct {
+	counts map[baselineKey]int
+}
+
+// NewBaseline creates a Baseline that accepts findings
+func NewBaseline(findings []Finding) *Baseline {
+	baseline := &Baseline{counts: map[baselineKey]int{}}
+	for _, finding := range findings {
+		baseline.counts[findingKey(finding)]++
+	}
+
+	return baseline
+}
+
+// findingKey returns the key of a finding
+func findingKey(finding Finding) baselineKey {
+	return baselineKey{
+		path:        filepath.ToSlash(finding.Path),
+		token:       finding.Token,
+		fingerprint: finding.Fingerprint,
+	}
+}
+
+// ReadBaseline reads a baseline file written by Baseline.Write
+func ReadBaseline(path string) (*Baseline, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []BaselineEntry
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	baseline := &Baseline{counts: map[baselineKey]int{}}
+	for _, entry := range entries {
+		count := entry.Count
+		if count <= 0 {
+			count = 1
+		}
+		baseline.counts[baselineKey{path: entry.Path, token: entry.Token, fingerprint: entry.Fingerprint}] += count
+	}
+
+	return baseline, nil
+}
+
+// Write writes the baseline to the file at path
+//
+//  Notes
+//    Entries are sorted so the file diffs well when committed, and written
+//    to a temporary file that is renamed over the file
+//
+func (baseline *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(baseline.Entries(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := ioutil.TempFile(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Entries returns the entries of the baseline, sorted by path, token and
+// fingerprint
+func (baseline *Baseline) Entries() []BaselineEntry {
+	entries := make([]BaselineEntry, 0, len(baseline.counts))
+	for key, count := range baseline.counts {
+		entry := BaselineEntry{Path: key.path, Token: key.token, Fingerprint: key.fingerprint}
+		if count > 1 {
+			entry.Count = count
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Token != b.Token {
+			return a.Token < b.Token
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
+
+	return entries
+}
+
+// Len returns the number of findings in the baseline
+func (baseline *Baseline) Len() int {
+	n := 0
+	for _, count := range baseline.counts {
+		n += count
+	}
+
+	return n
+}
+
+// Filter returns the findings that are not in the baseline
+//
+//  Notes
+//    Each baselined finding suppresses one finding, so when a line with a
+//    baselined finding is duplicated the copy is reported
+//
+func (baseline *Baseline) Filter(findings []Finding) []Finding {
+	remaining := make(map[baselineKey]int, len(baseline.counts))
+	for key, count := range baseline.counts {
+		remaining[key] = count
+	}
+
+	filtered := []Finding{}
+	for _, finding := range findings {
+		key := findingKey(finding)
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		filtered = append(filtered, finding)
+	}
+
+	return filtered
+}
+
+// Update replaces the baselined findings of paths with findings, or the
+// whole baseline if no paths are given, and returns how many findings were
+// added and removed
+//
+//  Notes
+//    Paths are compared with the Path of the findings, so entries of files
+//    that were not checked are kept
+//
+func (baseline *Baseline) Update(findings []Finding, paths ...string) (added, removed int) {
+	inPaths := pathSet(paths)
+	current := NewBaseline(findings).counts
+
+	for key, count := range current {
+		if count > baseline.counts[key] {
+			added += count - baseline.counts[key]
+		}
+	}
+	for key, count := range baseline.counts {
+		if inPaths(key.path) && count > current[key] {
+			removed += count - current[key]
+			delete(baseline.counts, key)
+		}
+	}
+	for key, count := range current {
+		baseline.counts[key] = count
+	}
+
+	return added, removed
+}
+
+// Prune removes the findings that are no longer reported (because they were
+// fixed, or their line changed) and returns how many were removed
+//
+//  Notes
+//    findings must be the findings of every file in paths, or of every file
+//    in the baseline if no paths are given; the entries of other files are
+//    kept. New findings are not added (see Update).
+//
+func (baseline *Baseline) Prune(findings []Finding, paths ...string) int {
+	inPaths := pathSet(paths)
+	current := NewBaseline(findings).counts
+
+	removed := 0
+	for key, count := range baseline.counts {
+		if inPaths(key.path) && count > current[key] {
+			removed += count - current[key]
+			if current[key] == 0 {
+				delete(baseline.counts, key)
+			} else {
+				baseline.counts[key] = current[key]
+			}
+		}
+	}
+
+	return removed
+}
+
+// pathSet returns a function that reports whether a baselined path is one
+// of paths, or always true when there are none
+func pathSet(paths []string) func(path string) bool {
+	if len(paths) == 0 {
+		return func(string) bool { return true }
+	}
+
+	set := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		set[filepath.ToSlash(path)] = true
+	}
+
+	return func(path string) bool { return set[path] }
+}
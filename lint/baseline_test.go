@@ -0,0 +1,210 @@
+package lint
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+func finding(path, token, fingerprint string) Finding {
+	return Finding{
+		Path:         path,
+		Fingerprint:  fingerprint,
+		FlaggedToken: bingSpellCheck.FlaggedToken{Token: token},
+	}
+}
+
+func TestBaselineFilter(t *testing.T) {
+	baseline := NewBaseline([]Finding{
+		finding("a.md", "teh", "f1"),
+		finding("a.md", "teh", "f1"),
+		finding("b.md", "recieve", "f2"),
+	})
+
+	tests := []struct {
+		name     string
+		findings []Finding
+		want     []Finding
+	}{
+		{
+			name:     "all baselined",
+			findings: []Finding{finding("a.md", "teh", "f1"), finding("b.md", "recieve", "f2")},
+			want:     []Finding{},
+		},
+		{
+			name:     "edited line",
+			findings: []Finding{finding("b.md", "recieve", "f3")},
+			want:     []Finding{finding("b.md", "recieve", "f3")},
+		},
+		{
+			name:     "other file",
+			findings: []Finding{finding("c.md", "teh", "f1")},
+			want:     []Finding{finding("c.md", "teh", "f1")},
+		},
+		{
+			name: "duplicated line",
+			findings: []Finding{
+				finding("a.md", "teh", "f1"),
+				finding("a.md", "teh", "f1"),
+				finding("a.md", "teh", "f1"),
+			},
+			want: []Finding{finding("a.md", "teh", "f1")},
+		},
+		{
+			name:     "backslashes",
+			findings: []Finding{finding(filepath.FromSlash("a.md"), "teh", "f1")},
+			want:     []Finding{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := baseline.Filter(tt.findings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaselinePrune(t *testing.T) {
+	initial := []Finding{
+		finding("a.md", "teh", "f1"),
+		finding("a.md", "teh", "f1"),
+		finding("a.md", "wrod", "f2"),
+		finding("b.md", "recieve", "f3"),
+	}
+
+	tests := []struct {
+		name        string
+		findings    []Finding
+		paths       []string
+		wantRemoved int
+		want        []BaselineEntry
+	}{
+		{
+			name:        "nothing fixed",
+			findings:    initial,
+			wantRemoved: 0,
+			want: []BaselineEntry{
+				{Path: "a.md", Token: "teh", Fingerprint: "f1", Count: 2},
+				{Path: "a.md", Token: "wrod", Fingerprint: "f2"},
+				{Path: "b.md", Token: "recieve", Fingerprint: "f3"},
+			},
+		},
+		{
+			name:        "fixed everywhere",
+			findings:    []Finding{finding("a.md", "teh", "f1"), finding("a.md", "new", "f9")},
+			wantRemoved: 3,
+			want: []BaselineEntry{
+				{Path: "a.md", Token: "teh", Fingerprint: "f1"},
+			},
+		},
+		{
+			name:        "only the given paths",
+			findings:    []Finding{finding("a.md", "wrod", "f2")},
+			paths:       []string{"a.md"},
+			wantRemoved: 2,
+			want: []BaselineEntry{
+				{Path: "a.md", Token: "wrod", Fingerprint: "f2"},
+				{Path: "b.md", Token: "recieve", Fingerprint: "f3"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline := NewBaseline(initial)
+			if removed := baseline.Prune(tt.findings, tt.paths...); removed != tt.wantRemoved {
+				t.Errorf("removed %d, want %d", removed, tt.wantRemoved)
+			}
+			if got := baseline.Entries(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaselineUpdate(t *testing.T) {
+	initial := []Finding{
+		finding("a.md", "teh", "f1"),
+		finding("a.md", "teh", "f1"),
+		finding("b.md", "recieve", "f3"),
+	}
+
+	tests := []struct {
+		name        string
+		findings    []Finding
+		paths       []string
+		wantAdded   int
+		wantRemoved int
+		want        []BaselineEntry
+	}{
+		{
+			name:        "whole baseline",
+			findings:    []Finding{finding("a.md", "teh", "f1"), finding("c.md", "wrod", "f4")},
+			wantAdded:   1,
+			wantRemoved: 2,
+			want: []BaselineEntry{
+				{Path: "a.md", Token: "teh", Fingerprint: "f1"},
+				{Path: "c.md", Token: "wrod", Fingerprint: "f4"},
+			},
+		},
+		{
+			name:        "only the given paths",
+			findings:    []Finding{finding("a.md", "teh", "f1"), finding("a.md", "wrod", "f2")},
+			paths:       []string{"a.md"},
+			wantAdded:   1,
+			wantRemoved: 1,
+			want: []BaselineEntry{
+				{Path: "a.md", Token: "teh", Fingerprint: "f1"},
+				{Path: "a.md", Token: "wrod", Fingerprint: "f2"},
+				{Path: "b.md", Token: "recieve", Fingerprint: "f3"},
+			},
+		},
+		{
+			name:        "fixed file",
+			findings:    []Finding{},
+			paths:       []string{"b.md"},
+			wantRemoved: 1,
+			want: []BaselineEntry{
+				{Path: "a.md", Token: "teh", Fingerprint: "f1", Count: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline := NewBaseline(initial)
+			added, removed := baseline.Update(tt.findings, tt.paths...)
+			if added != tt.wantAdded || removed != tt.wantRemoved {
+				t.Errorf("added %d, removed %d; want %d, %d", added, removed, tt.wantAdded, tt.wantRemoved)
+			}
+			if got := baseline.Entries(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaselineWriteRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultBaselineFile)
+
+	baseline := NewBaseline([]Finding{
+		finding("b.md", "recieve", "f3"),
+		finding("a.md", "teh", "f1"),
+		finding("a.md", "teh", "f1"),
+	})
+	if err := baseline.Write(path); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.Entries(), baseline.Entries()) || read.Len() != 3 {
+		t.Errorf("read %+v (%d findings), want %+v", read.Entries(), read.Len(), baseline.Entries())
+	}
+}
@@ -0,0 +1,200 @@
+package lint
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// Check checks each segment using checker and returns the flagged tokens,
+// with offsets relative to the text the segments were extracted from
+//
+//  Notes
+//    Checking stops at the first segment that fails; its error (or the
+//    first error of its error response) is returned.
+//
+//    Wrap checker with bingSpellCheck.CachedChecker to only send the
+//    segments that changed when a text is checked again
+//
+func Check(
+	ctx context.Context,
+	checker bingSpellCheck.Checker,
+	segments []bingSpellCheck.Segment,
+	opts *bingSpellCheck.CheckOptions) ([]bingSpellCheck.FlaggedToken, error) {
+
+	tokens := []bingSpellCheck.FlaggedToken{}
+
+	for _, segment := range segments {
+		scr, err := checker.Check(ctx, segment.Text, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err = responseError(scr); err != nil {
+			return nil, err
+		}
+
+		for _, token := range scr.FlaggedTokens {
+			token.Offset += segment.Offset
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens, nil
+}
+
+// responseError returns the first error of an error response, or nil
+func responseError(scr *bingSpellCheck.SpellCheckResponse) error {
+	if !scr.IsErrorResponse() {
+		return nil
+	}
+	if len(scr.Errors) > 0 {
+		return scr.Errors[0]
+	}
+
+	return bingSpellCheck.Error{Code: bingSpellCheck.ServerErrorCode, Message: "error response without errors"}
+}
+
+// CheckText extracts the segments of text with extract, checks them and
+// returns the flagged tokens that are not suppressed by the inline
+// directives of text (see Directives)
+func CheckText(
+	ctx context.Context,
+	checker bingSpellCheck.Checker,
+	text string,
+	extract Extractor,
+	opts *bingSpellCheck.CheckOptions) ([]bingSpellCheck.FlaggedToken, error) {
+
+	tokens, err := Check(ctx, checker, extract(text), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseDirectives(text).Filter(tokens), nil
+}
+
+// CheckFile extracts the segments of text (the content of the file at
+// path) and checks them, applying inline directives
+//
+//  Notes
+//    Files of unsupported types have no findings
+//
+func CheckFile(
+	ctx context.Context,
+	checker bingSpellCheck.Checker,
+	path, text string,
+	opts *bingSpellCheck.CheckOptions) ([]bingSpellCheck.FlaggedToken, error) {
+
+	extract := ExtractorFor(path)
+	if extract == nil {
+		return []bingSpellCheck.FlaggedToken{}, nil
+	}
+
+	return CheckText(ctx, checker, text, extract, opts)
+}
+
+// AutoCorrect checks text (the content of the file at path) as a whole and
+// corrects it with the first suggestion of each flagged token, as
+// bingSpellCheck.BuildAutoCorrectedText does
+//
+//  Notes
+//    Tokens suppressed by the inline directives of text are not corrected.
+//    The words, market and mode config sets for path are used, and text is
+//    returned unchanged if config ignores path. config may be nil, and path
+//    "" for text that is not from a file.
+//
+func AutoCorrect(
+	ctx context.Context,
+	checker bingSpellCheck.Checker,
+	config *Config,
+	path, text string,
+	opts *bingSpellCheck.CheckOptions) (string, error) {
+
+	if path != "" && config.Ignored(path) {
+		return text, nil
+	}
+
+	checker, opts = config.Apply(path, checker, opts)
+
+	scr, err := checker.Check(ctx, text, opts)
+	if err != nil {
+		return "", err
+	}
+	if err = responseError(scr); err != nil {
+		return "", err
+	}
+
+	scr = scr.Clone()
+	scr.FlaggedTokens = ParseDirectives(text).Filter(scr.FlaggedTokens)
+
+	return bingSpellCheck.BuildAutoCorrectedText(text, scr)
+}
+
+// Finding is a flagged token located in a file
+//
+//  Fields
+//    Path        - The file the token is in
+//    Line        - The line of the token (1 based)
+//    Column      - The column of the token (1 based, in characters)
+//    Fingerprint - Identifies the token by its line's text rather than its
+//                  position, so it survives lines moving (see Baseline)
+//
+type Finding struct {
+	Path        string `json:"path"`
+	Line        int    `json:"line"`
+	Column      int    `json:"column"`
+	Fingerprint string `json:"fingerprint"`
+	bingSpellCheck.FlaggedToken
+}
+
+// String formats the finding as path:line:column: message
+func (finding Finding) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", finding.Path, finding.Line, finding.Column, Message(finding.FlaggedToken))
+}
+
+// Findings locates tokens flagged in text, the content of the file at path
+func Findings(path, text string, tokens []bingSpellCheck.FlaggedToken) []Finding {
+	index := NewLineIndex(text)
+
+	findings := make([]Finding, len(tokens))
+	for i, token := range tokens {
+		line, column := index.Location(token.Offset)
+		findings[i] = Finding{
+			Path:         path,
+			Line:         line,
+			Column:       column,
+			Fingerprint:  fingerprint(token, index.line(line-1)),
+			FlaggedToken: token,
+		}
+	}
+
+	return findings
+}
+
+// fingerprint hashes the token with the text of its line, ignoring changes
+// to whitespace (such as indentation)
+func fingerprint(token bingSpellCheck.FlaggedToken, line string) string {
+	sum := sha256.Sum256([]byte(token.Type + "\x00" + token.Token + "\x00" + strings.Join(strings.Fields(line), " ")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Message describes a flagged token, e.g. `"teh" is misspelled (the)`
+func Message(token bingSpellCheck.FlaggedToken) string {
+	if token.IsRepeatedToken() {
+		return fmt.Sprintf("%q is repeated", token.Token)
+	}
+
+	message := fmt.Sprintf("%q is misspelled", token.Token)
+	if len(token.Suggestions) > 0 {
+		suggestions := make([]string, len(token.Suggestions))
+		for i, suggestion := range token.Suggestions {
+			suggestions[i] = suggestion.Suggestion
+		}
+		message += " (" + strings.Join(suggestions, ", ") + ")"
+	}
+
+	return message
+}
@@ -0,0 +1,143 @@
+package lint
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// checkpoint statuses
+const (
+	// CheckpointChecked is the status of a file that was checked
+	CheckpointChecked = "checked"
+
+	// CheckpointFailed is the status of a file that could not be checked
+	CheckpointFailed = "failed"
+)
+
+// CheckpointEntry records the outcome of a file
+//
+//  Fields
+//    Path     - The path of the file (relative to the crawled directory)
+//    Hash     - The SHA-256 of the file's content, hex encoded
+//    Status   - CheckpointChecked or CheckpointFailed
+//    Findings - The number of findings of a checked file
+//
+type CheckpointEntry struct {
+	Path     string `json:"path"`
+	Hash     string `json:"hash"`
+	Status   string `json:"status"`
+	Findings int    `json:"findings,omitempty"`
+}
+
+// Checkpoint is an append only log of the files a Crawler has processed,
+// so a crawl that is interrupted can resume where it stopped
+//
+//  Notes
+//    Each entry is a line of JSON, written as soon as its file is done, and
+//    the last entry of a path wins. A line left incomplete by a crash is
+//    ignored when the checkpoint is opened again
+//
+type Checkpoint struct {
+	mu      sync.Mutex
+	file    *os.File
+	entries map[string]CheckpointEntry
+}
+
+// OpenCheckpoint opens the checkpoint file at path, creating it if needed,
+// and reads its entries
+func OpenCheckpoint(path string) (*Checkpoint, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &Checkpoint{file: file, entries: map[string]CheckpointEntry{}}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry CheckpointEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.Path != "" {
+			cp.entries[entry.Path] = entry
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// start a new line after an incomplete one
+	if err = terminateLine(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+// terminateLine appends a line break to file unless it is empty or ends
+// with one
+func terminateLine(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+
+	last := make([]byte, 1)
+	if _, err = file.ReadAt(last, info.Size()-1); err != nil {
+		return err
+	}
+	if last[0] == '\n' {
+		return nil
+	}
+
+	_, err = file.WriteString("\n")
+	return err
+}
+
+// Done reports whether the file at path was checked with content of hash
+func (cp *Checkpoint) Done(path, hash string) bool {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	entry, ok := cp.entries[path]
+	return ok && entry.Hash == hash && entry.Status == CheckpointChecked
+}
+
+// Record appends entry to the checkpoint
+func (cp *Checkpoint) Record(entry CheckpointEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if _, err = cp.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	cp.entries[entry.Path] = entry
+
+	return nil
+}
+
+// Entries returns the current entry of each path
+func (cp *Checkpoint) Entries() map[string]CheckpointEntry {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	entries := make(map[string]CheckpointEntry, len(cp.entries))
+	for path, entry := range cp.entries {
+		entries[path] = entry
+	}
+
+	return entries
+}
+
+// Close closes the checkpoint file
+func (cp *Checkpoint) Close() error {
+	return cp.file.Close()
+}
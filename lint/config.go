@@ -0,0 +1,287 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// DefaultConfigFile is the conventional name of a project config file,
+// at the root of the repository
+const DefaultConfigFile = ".bingspell.json"
+
+// Config is a project's spell check configuration
+//
+//  Fields
+//    Ignore       - Globs of paths that are never checked
+//    Market       - The market to check with (optional)
+//    Mode         - ProofMode or SpellMode (optional)
+//    Words        - Words to accept
+//    Dictionaries - Files of words to accept, one per line, relative to the
+//                   config file
+//    Overrides    - Settings for the paths that match their globs, applied
+//                   in order after the settings above
+//
+//  Notes
+//    Paths are matched relative to the repository root, with forward
+//    slashes. Globs follow .gitignore: * and ? do not match /, ** matches
+//    any number of directories, a glob without a / matches a name at any
+//    depth, a leading / anchors a glob to the root, and a glob that matches
+//    a directory matches everything in it.
+//
+//    For example:
+//
+//      {
+//        "ignore": ["vendor/", "*.min.js", "/testdata/**/*.txt"],
+//        "market": "en-US",
+//        "words": ["gotomgo"],
+//        "dictionaries": [".bingspell-words.txt"],
+//        "overrides": [
+//          {"paths": ["docs/fr/**"], "market": "fr-FR", "mode": "spell"}
+//        ]
+//      }
+//
+type Config struct {
+	Ignore       []string                  `json:"ignore,omitempty"`
+	Market       bingSpellCheck.MarketCode `json:"market,omitempty"`
+	Mode         string                    `json:"mode,omitempty"`
+	Words        []string                  `json:"words,omitempty"`
+	Dictionaries []string                  `json:"dictionaries,omitempty"`
+	Overrides    []ConfigOverride          `json:"overrides,omitempty"`
+}
+
+// ConfigOverride is the settings of the paths that match any of Paths
+//
+//  Fields
+//    Paths        - Globs of the paths the override applies to
+//    Market       - Replaces the market (optional)
+//    Mode         - Replaces the mode (optional)
+//    Words        - Words to accept in addition to the Config's
+//    Dictionaries - Files of words to accept in addition to the Config's
+//
+type ConfigOverride struct {
+	Paths        []string                  `json:"paths"`
+	Market       bingSpellCheck.MarketCode `json:"market,omitempty"`
+	Mode         string                    `json:"mode,omitempty"`
+	Words        []string                  `json:"words,omitempty"`
+	Dictionaries []string                  `json:"dictionaries,omitempty"`
+}
+
+// FileSettings are the settings of one file
+type FileSettings struct {
+	Market bingSpellCheck.MarketCode
+	Mode   string
+	Words  []string
+}
+
+// LoadConfig reads the config file at configPath and the dictionaries it
+// references, whose words are added to Words
+func LoadConfig(configPath string) (*Config, error) {
+	data, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err = json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("%s: %v", configPath, err)
+	}
+
+	if err = config.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", configPath, err)
+	}
+
+	dir := filepath.Dir(configPath)
+	if config.Words, err = readDictionaries(dir, config.Words, config.Dictionaries); err != nil {
+		return nil, err
+	}
+	for i := range config.Overrides {
+		override := &config.Overrides[i]
+		if override.Words, err = readDictionaries(dir, override.Words, override.Dictionaries); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// validate checks the globs and modes of the config
+func (config *Config) validate() error {
+	globs := append([]string{}, config.Ignore...)
+	modes := []string{config.Mode}
+	for i, override := range config.Overrides {
+		if len(override.Paths) == 0 {
+			return fmt.Errorf("override %d has no paths", i)
+		}
+		globs = append(globs, override.Paths...)
+		modes = append(modes, override.Mode)
+	}
+
+	for _, glob := range globs {
+		if _, err := path.Match(strings.Replace(glob, "**", "*", -1), ""); err != nil {
+			return fmt.Errorf("invalid glob %q", glob)
+		}
+	}
+
+	for _, mode := range modes {
+		if mode != "" && mode != bingSpellCheck.ProofMode && mode != bingSpellCheck.SpellMode {
+			return fmt.Errorf("invalid mode %q", mode)
+		}
+	}
+
+	return nil
+}
+
+// readDictionaries appends the words of the dictionary files (relative to
+// dir) to words
+func readDictionaries(dir string, words []string, dictionaries []string) ([]string, error) {
+	for _, dictionary := range dictionaries {
+		if !filepath.IsAbs(dictionary) {
+			dictionary = filepath.Join(dir, filepath.FromSlash(dictionary))
+		}
+
+		more, err := ReadDictionary(dictionary)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, more...)
+	}
+
+	return words, nil
+}
+
+// Ignored reports whether the file at path is ignored
+//
+//  Notes
+//    A nil Config ignores nothing
+//
+func (config *Config) Ignored(path string) bool {
+	return config != nil && matchAny(config.Ignore, path)
+}
+
+// Settings returns the settings of the file at path
+func (config *Config) Settings(path string) FileSettings {
+	if config == nil {
+		return FileSettings{}
+	}
+
+	settings := FileSettings{
+		Market: config.Market,
+		Mode:   config.Mode,
+		Words:  append([]string{}, config.Words...),
+	}
+
+	for _, override := range config.Overrides {
+		if !matchAny(override.Paths, path) {
+			continue
+		}
+		if override.Market != "" {
+			settings.Market = override.Market
+		}
+		if override.Mode != "" {
+			settings.Mode = override.Mode
+		}
+		settings.Words = append(settings.Words, override.Words...)
+	}
+
+	return settings
+}
+
+// Apply returns the checker and options to check the file at path with:
+// checker accepting the words of the file's settings, and opts with its
+// market and mode
+//
+//  Notes
+//    A nil Config returns checker and opts
+//
+func (config *Config) Apply(
+	path string,
+	checker bingSpellCheck.Checker,
+	opts *bingSpellCheck.CheckOptions) (bingSpellCheck.Checker, *bingSpellCheck.CheckOptions) {
+
+	settings := config.Settings(path)
+
+	if len(settings.Words) > 0 {
+		checker = bingSpellCheck.NewDictionaryChecker(checker, settings.Words...)
+	}
+
+	if settings.Market != "" || settings.Mode != "" {
+		merged := bingSpellCheck.CheckOptions{}
+		if opts != nil {
+			merged = *opts
+		}
+		if settings.Market != "" {
+			merged.Market = settings.Market
+		}
+		if settings.Mode != "" {
+			merged.Mode = settings.Mode
+		}
+		opts = &merged
+	}
+
+	return checker, opts
+}
+
+// matchAny reports whether name matches any of globs
+func matchAny(globs []string, name string) bool {
+	name = strings.TrimPrefix(filepath.ToSlash(name), "./")
+
+	for _, glob := range globs {
+		if matchGlob(glob, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchGlob reports whether name matches glob, following .gitignore (see
+// Config)
+func matchGlob(glob, name string) bool {
+	glob = strings.TrimSuffix(glob, "/")
+	if glob == "" {
+		return false
+	}
+
+	if strings.HasPrefix(glob, "/") {
+		glob = glob[1:]
+	} else if !strings.Contains(glob, "/") {
+		glob = "**/" + glob
+	}
+
+	pattern := strings.Split(glob, "/")
+	segments := strings.Split(name, "/")
+
+	// a glob that matches a directory matches everything in it
+	return matchSegments(pattern, segments) || matchSegments(append(pattern, "**"), segments)
+}
+
+// matchSegments matches the segments of a path with the segments of a glob
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+
+		pattern, segments = pattern[1:], segments[1:]
+	}
+
+	return len(segments) == 0
+}
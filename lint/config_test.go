@@ -0,0 +1,133 @@
+package lint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		glob string
+		name string
+		want bool
+	}{
+		{"*.md", "README.md", true},
+		{"*.md", "docs/guide/intro.md", true},
+		{"*.md", "README.txt", false},
+		{"vendor/", "vendor/a/b.go", true},
+		{"vendor", "src/vendor/a.go", true},
+		{"/vendor", "src/vendor/a.go", false},
+		{"/vendor", "vendor/a.go", true},
+		{"docs/*.md", "docs/a.md", true},
+		{"docs/*.md", "docs/sub/a.md", false},
+		{"docs/*.md", "src/docs/a.md", false},
+		{"docs/**/*.md", "docs/a.md", true},
+		{"docs/**/*.md", "docs/sub/deeper/a.md", true},
+		{"**/testdata", "a/b/testdata/x.txt", true},
+		{"a?c", "abc", true},
+		{"a?c", "a/c", false},
+		{"*", "anything/at/all", true},
+		{"", "a.md", false},
+		{"/", "a.md", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.glob, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.glob, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConfigIgnored(t *testing.T) {
+	config := &Config{Ignore: []string{"vendor/", "*.min.js"}}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"vendor/x/y.md", true},
+		{"./web/app.min.js", true},
+		{filepath.Join("web", "app.min.js"), true},
+		{"web/app.js", false},
+	}
+
+	for _, tt := range tests {
+		if got := config.Ignored(tt.path); got != tt.want {
+			t.Errorf("Ignored(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	var none *Config
+	if none.Ignored("vendor/x.md") {
+		t.Error("a nil Config ignores a path")
+	}
+}
+
+func TestConfigSettings(t *testing.T) {
+	config := &Config{
+		Market: "en-US",
+		Words:  []string{"gotomgo"},
+		Overrides: []ConfigOverride{
+			{Paths: []string{"docs/fr/**"}, Market: "fr-FR", Mode: bingSpellCheck.SpellMode, Words: []string{"bonjour"}},
+			{Paths: []string{"*.go"}, Words: []string{"ctx"}},
+		},
+	}
+
+	tests := []struct {
+		path string
+		want FileSettings
+	}{
+		{"README.md", FileSettings{Market: "en-US", Words: []string{"gotomgo"}}},
+		{"docs/fr/intro.md", FileSettings{Market: "fr-FR", Mode: bingSpellCheck.SpellMode, Words: []string{"gotomgo", "bonjour"}}},
+		{"docs/fr/main.go", FileSettings{Market: "fr-FR", Mode: bingSpellCheck.SpellMode, Words: []string{"gotomgo", "bonjour", "ctx"}}},
+		{"main.go", FileSettings{Market: "en-US", Words: []string{"gotomgo", "ctx"}}},
+	}
+
+	for _, tt := range tests {
+		if got := config.Settings(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Settings(%q) = %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		wantErr   bool
+		wantWords []string
+	}{
+		{"valid", `{"ignore": ["vendor/"], "words": ["a"], "dictionaries": ["words.txt"]}`, false, []string{"a", "b", "c"}},
+		{"invalid json", `{"ignore": `, true, nil},
+		{"invalid glob", `{"ignore": ["[a"]}`, true, nil},
+		{"invalid mode", `{"mode": "fix"}`, true, nil},
+		{"override without paths", `{"overrides": [{"market": "fr-FR"}]}`, true, nil},
+		{"missing dictionary", `{"dictionaries": ["missing.txt"]}`, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := ioutil.WriteFile(filepath.Join(dir, "words.txt"), []byte("b\nc\n"), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(dir, DefaultConfigFile)
+			if err := ioutil.WriteFile(path, []byte(tt.config), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(config.Words, tt.wantWords) {
+				t.Errorf("words = %v, want %v", config.Words, tt.wantWords)
+			}
+		})
+	}
+}
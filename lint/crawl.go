@@ -0,0 +1,335 @@
+package lint
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// DefaultCrawlBatchSize is the default number of segments checked per batch
+const DefaultCrawlBatchSize = 64
+
+// skippedDirs are never crawled
+var skippedDirs = map[string]bool{".git": true, ".hg": true, ".svn": true, "node_modules": true}
+
+// CrawlResult is the outcome of one file of a crawl
+//
+//  Fields
+//    Path     - The path of the file, relative to the crawled directory
+//    Hash     - The SHA-256 of the file's content, hex encoded
+//    Findings - The findings of the file (with Path relative to the
+//               crawled directory)
+//    Error    - Why the file could not be checked ("" when it was)
+//
+type CrawlResult struct {
+	Path     string    `json:"path"`
+	Hash     string    `json:"hash"`
+	Findings []Finding `json:"findings"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// CrawlStats counts the files of a crawl
+//
+//  Fields
+//    Files    - Files of supported types that were not ignored
+//    Checked  - Files that were checked
+//    Skipped  - Files the checkpoint had checked with the same content
+//    Failed   - Files that could not be checked
+//    Findings - The findings of the checked files
+//
+type CrawlStats struct {
+	Files    int
+	Checked  int
+	Skipped  int
+	Failed   int
+	Findings int
+}
+
+// Crawler checks every supported file of a directory tree, for archives
+// too large to check in one sitting
+//
+//  Notes
+//    Files are dispatched to their extractor (see ExtractorFor), and the
+//    segments of several files are checked together with a
+//    bingSpellCheck.BatchChecker. Each file's result is written to the
+//    results as soon as its segments are checked, and then recorded in the
+//    checkpoint, so a crawl run again after a crash (or with a changed
+//    tree) skips the files it already checked and that have not changed.
+//    A file can be reported twice if the crawl stops between the two.
+//
+//    OnResult, if set, is called with the result of each checked or failed
+//    file, in the order they are written
+//
+type Crawler struct {
+	OnResult func(result CrawlResult)
+
+	checker     bingSpellCheck.Checker
+	concurrency int
+	batchSize   int
+	opts        *bingSpellCheck.CheckOptions
+	config      *Config
+	checkpoint  *Checkpoint
+	results     io.Writer
+}
+
+// NewCrawler creates a Crawler that makes up to concurrency checks at a
+// time using checker
+func NewCrawler(checker bingSpellCheck.Checker, concurrency int) *Crawler {
+	return &Crawler{checker: checker, concurrency: concurrency, batchSize: DefaultCrawlBatchSize}
+}
+
+// WithOptions sets the options of each check
+func (crawler *Crawler) WithOptions(opts *bingSpellCheck.CheckOptions) *Crawler {
+	crawler.opts = opts
+	return crawler
+}
+
+// WithBatchSize sets the number of segments checked per batch
+func (crawler *Crawler) WithBatchSize(size int) *Crawler {
+	if size < 1 {
+		size = DefaultCrawlBatchSize
+	}
+
+	crawler.batchSize = size
+	return crawler
+}
+
+// WithConfig ignores paths and applies per path settings from config, with
+// paths relative to the crawled directory
+func (crawler *Crawler) WithConfig(config *Config) *Crawler {
+	crawler.config = config
+	return crawler
+}
+
+// WithCheckpoint skips the files checkpoint has checked, and records each
+// file in it
+func (crawler *Crawler) WithCheckpoint(checkpoint *Checkpoint) *Crawler {
+	crawler.checkpoint = checkpoint
+	return crawler
+}
+
+// WithResults writes each result to w as a line of JSON
+func (crawler *Crawler) WithResults(w io.Writer) *Crawler {
+	crawler.results = w
+	return crawler
+}
+
+// crawlFile is a file waiting to be checked
+type crawlFile struct {
+	path     string
+	hash     string
+	text     string
+	segments []bingSpellCheck.Segment
+	settings FileSettings
+}
+
+// Crawl checks the files under dir and returns the counts
+//
+//  Notes
+//    Version control directories and node_modules are not crawled. Files
+//    and directories that cannot be read are recorded as failed and the
+//    crawl goes on. An error is returned if dir cannot be read, a result
+//    cannot be written, or ctx is done; the files recorded until then are
+//    not checked again
+//
+func (crawler *Crawler) Crawl(ctx context.Context, dir string) (CrawlStats, error) {
+	var stats CrawlStats
+
+	var pending []*crawlFile
+	segments := 0
+
+	// flush checks the pending files, which share their settings
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		err := crawler.checkBatch(ctx, pending, &stats)
+		pending, segments = nil, 0
+		return err
+	}
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		// an unreadable directory is skipped, and an unreadable file fails
+		if walkErr != nil {
+			if rel == "." {
+				return walkErr
+			}
+			return crawler.record(CrawlResult{Path: rel, Error: walkErr.Error()}, &stats)
+		}
+
+		if entry.IsDir() {
+			if rel != "." && (skippedDirs[entry.Name()] || crawler.config.Ignored(rel)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		extract := ExtractorFor(rel)
+		if !entry.Type().IsRegular() || extract == nil || crawler.config.Ignored(rel) {
+			return nil
+		}
+		stats.Files++
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return crawler.record(CrawlResult{Path: rel, Error: err.Error()}, &stats)
+		}
+
+		sum := sha256.Sum256(data)
+		file := &crawlFile{path: rel, hash: hex.EncodeToString(sum[:]), text: string(data)}
+
+		if crawler.checkpoint != nil && crawler.checkpoint.Done(file.path, file.hash) {
+			stats.Skipped++
+			return nil
+		}
+
+		file.segments = extract(file.text)
+		file.settings = crawler.config.Settings(rel)
+
+		// a batch is checked with the settings of its files
+		if len(pending) > 0 && !sameSettings(pending[0].settings, file.settings) {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+
+		pending = append(pending, file)
+		segments += len(file.segments)
+
+		if segments >= crawler.batchSize {
+			return flush()
+		}
+
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+
+	return stats, err
+}
+
+// sameSettings reports whether two files have the same settings
+func sameSettings(a, b FileSettings) bool {
+	if a.Market != b.Market || a.Mode != b.Mode || len(a.Words) != len(b.Words) {
+		return false
+	}
+
+	for i := range a.Words {
+		if a.Words[i] != b.Words[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkBatch checks the segments of files and writes their results
+func (crawler *Crawler) checkBatch(ctx context.Context, files []*crawlFile, stats *CrawlStats) error {
+	var texts []string
+	for _, file := range files {
+		for _, segment := range file.segments {
+			texts = append(texts, segment.Text)
+		}
+	}
+
+	checker, opts := crawler.config.Apply(files[0].path, crawler.checker, crawler.opts)
+	results := bingSpellCheck.NewBatchChecker(checker, crawler.concurrency).CheckBatch(ctx, texts, opts)
+
+	// results that failed because the crawl was stopped are not recorded
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	i := 0
+	for _, file := range files {
+		result := CrawlResult{Path: file.path, Hash: file.hash}
+
+		tokens := []bingSpellCheck.FlaggedToken{}
+		for _, segment := range file.segments {
+			batchResult := results[i]
+			i++
+
+			if result.Error != "" {
+				continue
+			}
+			if err := batchError(batchResult); err != nil {
+				result.Error = err.Error()
+				continue
+			}
+			for _, token := range batchResult.Response.FlaggedTokens {
+				token.Offset += segment.Offset
+				tokens = append(tokens, token)
+			}
+		}
+
+		if result.Error == "" {
+			result.Findings = Findings(file.path, file.text, ParseDirectives(file.text).Filter(tokens))
+		}
+
+		if err := crawler.record(result, stats); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// batchError returns the error of a batch result, including the first
+// error of an error response
+func batchError(result bingSpellCheck.BatchResult) error {
+	if result.Err != nil {
+		return result.Err
+	}
+
+	return responseError(result.Response)
+}
+
+// record writes result and then records it in the checkpoint
+func (crawler *Crawler) record(result CrawlResult, stats *CrawlStats) error {
+	entry := CheckpointEntry{Path: result.Path, Hash: result.Hash, Status: CheckpointChecked, Findings: len(result.Findings)}
+	if result.Error != "" {
+		entry.Status = CheckpointFailed
+		stats.Failed++
+	} else {
+		stats.Checked++
+		stats.Findings += len(result.Findings)
+	}
+
+	if crawler.results != nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		if _, err = crawler.results.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+
+	if crawler.OnResult != nil {
+		crawler.OnResult(result)
+	}
+
+	if crawler.checkpoint != nil {
+		return crawler.checkpoint.Record(entry)
+	}
+
+	return nil
+}
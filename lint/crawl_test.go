@@ -0,0 +1,200 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// crawlChecker flags "teh", fails texts that contain "fail" and counts the
+// texts it checks
+type crawlChecker struct {
+	checked int64
+}
+
+func (checker *crawlChecker) Check(
+	ctx context.Context,
+	text string,
+	opts *bingSpellCheck.CheckOptions) (*bingSpellCheck.SpellCheckResponse, error) {
+
+	atomic.AddInt64(&checker.checked, 1)
+
+	if strings.Contains(text, "fail") {
+		return nil, errors.New("check failed")
+	}
+
+	scr := &bingSpellCheck.SpellCheckResponse{Type: "SpellCheck", FlaggedTokens: []bingSpellCheck.FlaggedToken{}}
+	if offset := strings.Index(text, "teh"); offset >= 0 {
+		scr.FlaggedTokens = append(scr.FlaggedTokens, bingSpellCheck.FlaggedToken{Offset: offset, Token: "teh", Type: "UnknownToken"})
+	}
+
+	return scr, nil
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCrawlCheckpointResume(t *testing.T) {
+	dir := t.TempDir()
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+
+	writeFiles(t, dir, map[string]string{
+		"a.txt":                  "teh cat",
+		"docs/b.md":              "all fine",
+		"c.txt":                  "this will fail",
+		"image.png":              "not text",
+		"node_modules/x/d.txt":   "teh",
+		".git/COMMIT_EDITMSG":    "teh",
+		"vendor/ignored/e.txt":   "teh",
+		"docs/ignored/also.text": "teh",
+	})
+	config := &Config{Ignore: []string{"vendor/", "docs/ignored"}}
+
+	tests := []struct {
+		name        string
+		files       map[string]string
+		want        CrawlStats
+		wantChecked int64
+	}{
+		{
+			name:        "first crawl",
+			want:        CrawlStats{Files: 3, Checked: 2, Failed: 1, Findings: 1},
+			wantChecked: 3,
+		},
+		{
+			name:        "resumed crawl retries failures",
+			want:        CrawlStats{Files: 3, Skipped: 2, Failed: 1},
+			wantChecked: 1,
+		},
+		{
+			name:        "changed files are checked again",
+			files:       map[string]string{"docs/b.md": "teh change", "c.txt": "fixed"},
+			want:        CrawlStats{Files: 3, Checked: 2, Skipped: 1, Findings: 1},
+			wantChecked: 2,
+		},
+		{
+			name:        "new files are checked",
+			files:       map[string]string{"f.txt": "new"},
+			want:        CrawlStats{Files: 4, Checked: 1, Skipped: 3},
+			wantChecked: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		writeFiles(t, dir, tt.files)
+
+		checkpoint, err := OpenCheckpoint(checkpointPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checker := &crawlChecker{}
+		var results []CrawlResult
+		crawler := NewCrawler(checker, 2).WithConfig(config).WithCheckpoint(checkpoint)
+		crawler.OnResult = func(result CrawlResult) { results = append(results, result) }
+
+		stats, err := crawler.Crawl(context.Background(), dir)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if err = checkpoint.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if stats != tt.want {
+			t.Errorf("%s: stats = %+v, want %+v", tt.name, stats, tt.want)
+		}
+		if checker.checked != tt.wantChecked {
+			t.Errorf("%s: checked %d texts, want %d", tt.name, checker.checked, tt.wantChecked)
+		}
+		if len(results) != tt.want.Checked+tt.want.Failed {
+			t.Errorf("%s: got %d results, want %d", tt.name, len(results), tt.want.Checked+tt.want.Failed)
+		}
+	}
+}
+
+func TestCheckpointDone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+
+	checkpoint, err := OpenCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := []CheckpointEntry{
+		{Path: "a.md", Hash: "1", Status: CheckpointChecked},
+		{Path: "b.md", Hash: "2", Status: CheckpointFailed},
+		{Path: "c.md", Hash: "3", Status: CheckpointFailed},
+		{Path: "c.md", Hash: "3", Status: CheckpointChecked, Findings: 2},
+	}
+	for _, entry := range entries {
+		if err = checkpoint.Record(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = checkpoint.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a line left incomplete by a crash
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.WriteString(`{"path":"d.md","hash":"4","sta`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	checkpoint, err = OpenCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = checkpoint.Record(CheckpointEntry{Path: "e.md", Hash: "5", Status: CheckpointChecked}); err != nil {
+		t.Fatal(err)
+	}
+	checkpoint.Close()
+
+	checkpoint, err = OpenCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checkpoint.Close()
+
+	tests := []struct {
+		path string
+		hash string
+		want bool
+	}{
+		{"a.md", "1", true},
+		{"a.md", "changed", false},
+		{"b.md", "2", false},
+		{"c.md", "3", true},
+		{"d.md", "4", false},
+		{"e.md", "5", true},
+		{"missing.md", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := checkpoint.Done(tt.path, tt.hash); got != tt.want {
+			t.Errorf("Done(%q, %q) = %v, want %v", tt.path, tt.hash, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package lint
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// ReadDictionary reads a dictionary file of one word per line, ignoring
+// blank lines and lines that start with #
+func ReadDictionary(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" && !strings.HasPrefix(word, "#") {
+			words = append(words, word)
+		}
+	}
+
+	return words, scanner.Err()
+}
@@ -0,0 +1,243 @@
+package lint
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// FileDiff is the change to one file in a unified diff
+//
+//  Fields
+//    Path    - The path of the new file (relative to the repository root)
+//    OldPath - The path of the old file ("" for added files)
+//    Lines   - The added or modified lines of the new file (1 based, sorted)
+//
+type FileDiff struct {
+	Path    string
+	OldPath string
+	Lines   []int
+}
+
+// Changed reports whether line (1 based) was added or modified
+func (fd FileDiff) Changed(line int) bool {
+	i := sort.SearchInts(fd.Lines, line)
+	return i < len(fd.Lines) && fd.Lines[i] == line
+}
+
+// ParseDiff parses a unified diff (such as the output of git diff) and
+// returns the files that have added or modified lines
+//
+//  Notes
+//    Deleted files, binary files and files with only removed lines are
+//    omitted. Any amount of context is accepted, although --unified=0 keeps
+//    the diff smallest
+//
+func ParseDiff(r io.Reader) ([]FileDiff, error) {
+	files := []FileDiff{}
+
+	var file *FileDiff
+	flush := func() {
+		if file != nil && file.Path != "" && len(file.Lines) > 0 {
+			files = append(files, *file)
+		}
+		file = nil
+	}
+
+	// the next line number of the new file, and the lines left in the hunk
+	line, oldLeft, newLeft := 0, 0, 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				file.Lines = append(file.Lines, line)
+				line++
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				oldLeft--
+			case strings.HasPrefix(text, " "), text == "":
+				line++
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "diff "):
+			flush()
+			file = &FileDiff{}
+		case file == nil:
+			// preamble (e.g. the commit header of git show)
+		case strings.HasPrefix(text, "--- "):
+			file.OldPath = diffPath(text[len("--- "):])
+		case strings.HasPrefix(text, "+++ "):
+			file.Path = diffPath(text[len("+++ "):])
+		case strings.HasPrefix(text, "@@ "):
+			var err error
+			if line, oldLeft, newLeft, err = parseHunkHeader(text); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return files, nil
+}
+
+// diffPath returns the path of a ---/+++ line, without the a/ or b/ prefix,
+// or "" for /dev/null
+func diffPath(path string) string {
+	// git appends a tab when the path contains spaces
+	path = strings.TrimSuffix(path, "\t")
+
+	if strings.HasPrefix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			path = unquoted
+		}
+	}
+
+	if path == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		return path[2:]
+	}
+
+	return path
+}
+
+// parseHunkHeader parses "@@ -start,count +start,count @@" and returns the
+// first line of the new file and the line counts of the old and new file
+func parseHunkHeader(header string) (line, oldCount, newCount int, err error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, fmt.Errorf("invalid hunk header: %s", header)
+	}
+
+	if _, oldCount, err = parseRange(fields[1][1:]); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid hunk header: %s", header)
+	}
+	if line, newCount, err = parseRange(fields[2][1:]); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid hunk header: %s", header)
+	}
+
+	return line, oldCount, newCount, nil
+}
+
+// parseRange parses start[,count] where count defaults to 1
+func parseRange(s string) (start, count int, err error) {
+	count = 1
+	if i := strings.IndexByte(s, ','); i >= 0 {
+		if count, err = strconv.Atoi(s[i+1:]); err != nil {
+			return 0, 0, err
+		}
+		s = s[:i]
+	}
+
+	start, err = strconv.Atoi(s)
+	return start, count, err
+}
+
+// CheckDiff checks the changed lines of each file in files and returns the
+// findings, with positions in the new files
+//
+//  Notes
+//    readFile returns the content of the new file at a path. Files of
+//    unsupported types, and files ignored by config (which may be nil), are
+//    skipped without being read. Each file is checked with the settings of
+//    config and its inline directives.
+//
+//    Segments (paragraphs, comments) that touch a changed line are checked
+//    whole so Bing has the context of the sentence, but only findings on
+//    changed lines are reported
+//
+func CheckDiff(
+	ctx context.Context,
+	checker bingSpellCheck.Checker,
+	config *Config,
+	files []FileDiff,
+	readFile func(path string) (string, error),
+	opts *bingSpellCheck.CheckOptions) ([]Finding, error) {
+
+	findings := []Finding{}
+
+	for _, file := range files {
+		extract := ExtractorFor(file.Path)
+		if extract == nil || config.Ignored(file.Path) {
+			continue
+		}
+
+		text, err := readFile(file.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		index := NewLineIndex(text)
+
+		var segments []bingSpellCheck.Segment
+		for _, segment := range extract(text) {
+			first := index.Position(segment.Offset).Line + 1
+			last := index.Position(segment.Offset+len(segment.Text)).Line + 1
+			if changedBetween(file, first, last) {
+				segments = append(segments, segment)
+			}
+		}
+		if len(segments) == 0 {
+			continue
+		}
+
+		fileChecker, fileOpts := config.Apply(file.Path, checker, opts)
+
+		tokens, err := Check(ctx, fileChecker, segments, fileOpts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file.Path, err)
+		}
+
+		for _, finding := range Findings(file.Path, text, ParseDirectives(text).Filter(tokens)) {
+			if file.Changed(finding.Line) {
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// changedBetween reports whether any line from first to last was changed
+func changedBetween(file FileDiff, first, last int) bool {
+	i := sort.SearchInts(file.Lines, first)
+	return i < len(file.Lines) && file.Lines[i] <= last
+}
+
+// CheckCommitMessage checks a commit message (the content of the file at
+// path, such as .git/COMMIT_EDITMSG), applying inline directives, and
+// returns the findings
+func CheckCommitMessage(
+	ctx context.Context,
+	checker bingSpellCheck.Checker,
+	path, text string,
+	opts *bingSpellCheck.CheckOptions) ([]Finding, error) {
+
+	tokens, err := CheckText(ctx, checker, text, ExtractCommitMessage, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return Findings(path, text, tokens), nil
+}
@@ -0,0 +1,196 @@
+package lint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want []FileDiff
+	}{
+		{
+			name: "empty",
+			diff: "",
+			want: []FileDiff{},
+		},
+		{
+			name: "zero context",
+			diff: `diff --git a/README.md b/README.md
+index 1111111..2222222 100644
+--- a/README.md
++++ b/README.md
+@@ -3 +3 @@ intro
+-old
++new
+@@ -10,0 +11,2 @@
++added one
++added two
+`,
+			want: []FileDiff{{Path: "README.md", OldPath: "README.md", Lines: []int{3, 11, 12}}},
+		},
+		{
+			name: "context lines",
+			diff: `diff --git a/doc.txt b/doc.txt
+--- a/doc.txt
++++ b/doc.txt
+@@ -1,4 +1,5 @@
+ one
+-two
++2
++2.5
+ three
+
+`,
+			want: []FileDiff{{Path: "doc.txt", OldPath: "doc.txt", Lines: []int{2, 3}}},
+		},
+		{
+			name: "added, deleted and renamed files",
+			diff: `diff --git a/new.md b/new.md
+new file mode 100644
+--- /dev/null
++++ b/new.md
+@@ -0,0 +1,2 @@
++hello
++world
+diff --git a/gone.md b/gone.md
+deleted file mode 100644
+--- a/gone.md
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/old name.md b/new name.md
+--- "a/old name.md"
++++ "b/new name.md"
+@@ -1 +1 @@
+-a
++b
+`,
+			want: []FileDiff{
+				{Path: "new.md", OldPath: "", Lines: []int{1, 2}},
+				{Path: "new name.md", OldPath: "old name.md", Lines: []int{1}},
+			},
+		},
+		{
+			name: "only removed lines and binary files",
+			diff: `diff --git a/a.md b/a.md
+--- a/a.md
++++ b/a.md
+@@ -2,2 +1,0 @@
+-x
+-y
+diff --git a/img.png b/img.png
+Binary files a/img.png and b/img.png differ
+`,
+			want: []FileDiff{},
+		},
+		{
+			name: "preamble and lines that look like headers",
+			diff: `commit 0123456789abcdef
+Author: someone
+
+    message
+
+diff --git a/a.md b/a.md
+--- a/a.md
++++ b/a.md
+@@ -1,2 +1,2 @@
+---- a/b
++++++ b/c
+ diff kept
+`,
+			want: []FileDiff{{Path: "a.md", OldPath: "a.md", Lines: []int{1}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDiff(strings.NewReader(tt.diff))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDiffInvalidHunk(t *testing.T) {
+	diff := "diff --git a/a.md b/a.md\n--- a/a.md\n+++ b/a.md\n@@ -x +1 @@\n+a\n"
+
+	if _, err := ParseDiff(strings.NewReader(diff)); err == nil {
+		t.Error("got no error for an invalid hunk header")
+	}
+}
+
+func TestParseHunkHeader(t *testing.T) {
+	tests := []struct {
+		header                   string
+		line, oldCount, newCount int
+		wantErr                  bool
+	}{
+		{"@@ -1,3 +1,4 @@", 1, 3, 4, false},
+		{"@@ -5 +7 @@ func main() {", 7, 1, 1, false},
+		{"@@ -0,0 +1,2 @@", 1, 0, 2, false},
+		{"@@ -3,2 +2,0 @@", 2, 2, 0, false},
+		{"@@ -a +1 @@", 0, 0, 0, true},
+		{"@@ 1 2 @@", 0, 0, 0, true},
+		{"@@", 0, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		line, oldCount, newCount, err := parseHunkHeader(tt.header)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseHunkHeader(%q) error = %v, want error %v", tt.header, err, tt.wantErr)
+			continue
+		}
+		if line != tt.line || oldCount != tt.oldCount || newCount != tt.newCount {
+			t.Errorf("parseHunkHeader(%q) = %d, %d, %d; want %d, %d, %d",
+				tt.header, line, oldCount, newCount, tt.line, tt.oldCount, tt.newCount)
+		}
+	}
+}
+
+func TestFileDiffChanged(t *testing.T) {
+	fd := FileDiff{Lines: []int{2, 5, 6}}
+
+	tests := []struct {
+		line int
+		want bool
+	}{
+		{1, false},
+		{2, true},
+		{4, false},
+		{6, true},
+		{7, false},
+	}
+
+	for _, tt := range tests {
+		if got := fd.Changed(tt.line); got != tt.want {
+			t.Errorf("Changed(%d) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestSubcommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"diff", "--cached"}, "diff"},
+		{[]string{"-c", "core.quotePath=false", "ls-files", "-z"}, "ls-files"},
+		{[]string{"-C", "dir", "--no-pager", "show", ":a"}, "show"},
+		{[]string{"--version"}, "command"},
+		{nil, "command"},
+	}
+
+	for _, tt := range tests {
+		if got := subcommand(tt.args); got != tt.want {
+			t.Errorf("subcommand(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// directivePrefix introduces an inline directive
+const directivePrefix = "bingspell:"
+
+// inlineDirective matches an inline directive and the rest of its line;
+// longer names come first so disable-next-line is not read as disable
+var inlineDirective = regexp.MustCompile(`bingspell:(ignore|disable-next-line|disable-line|disable|enable)\b([^\n]*)`)
+
+// commentClosers end a comment after the words of an ignore directive
+var commentClosers = map[string]bool{"*/": true, "-->": true, "--]]": true, "#}": true, "%>": true}
+
+// Directives are the inline directives of a text, which suppress findings
+//
+//  Notes
+//    Directives can be written in any comment syntax (or in plain text):
+//
+//      bingspell:ignore word1 word2  accepts the words in the whole file
+//      bingspell:disable-next-line   suppresses the findings of the next line
+//      bingspell:disable-line        suppresses the findings of its line
+//      bingspell:disable             suppresses findings until the next
+//      bingspell:enable              enable (or the end of the file)
+//
+//    Words are matched case insensitively, and the directives themselves
+//    are never flagged
+//
+type Directives struct {
+	words  map[string]bool
+	ranges [][2]int
+}
+
+// ParseDirectives finds the directives of text
+func ParseDirectives(text string) *Directives {
+	directives := &Directives{words: map[string]bool{}}
+	if !strings.Contains(text, directivePrefix) {
+		return directives
+	}
+
+	index := NewLineIndex(text)
+	disabled := -1
+
+	for _, match := range inlineDirective.FindAllStringSubmatchIndex(text, -1) {
+		line := index.Position(match[0]).Line
+		start, end := index.lineBounds(line)
+
+		switch text[match[2]:match[3]] {
+		case "ignore":
+			for _, word := range strings.FieldsFunc(text[match[4]:match[5]], isWordSeparator) {
+				if commentClosers[word] {
+					break
+				}
+				directives.words[strings.ToLower(word)] = true
+			}
+		case "disable-next-line":
+			nextStart, nextEnd := index.lineBounds(line + 1)
+			directives.ranges = append(directives.ranges, [2]int{nextStart, nextEnd})
+		case "disable-line":
+			directives.ranges = append(directives.ranges, [2]int{start, end})
+		case "disable":
+			if disabled < 0 {
+				disabled = start
+			}
+		case "enable":
+			if disabled >= 0 {
+				directives.ranges = append(directives.ranges, [2]int{disabled, end})
+				disabled = -1
+			}
+		}
+
+		directives.ranges = append(directives.ranges, [2]int{match[0], match[1]})
+	}
+
+	if disabled >= 0 {
+		directives.ranges = append(directives.ranges, [2]int{disabled, len(text)})
+	}
+
+	return directives
+}
+
+// isWordSeparator separates the words of an ignore directive
+func isWordSeparator(r rune) bool {
+	return r == ' ' || r == '\t' || r == ',' || r == '\r'
+}
+
+// Suppressed reports whether a directive suppresses token
+func (directives *Directives) Suppressed(token bingSpellCheck.FlaggedToken) bool {
+	if directives.words[strings.ToLower(token.Token)] {
+		return true
+	}
+
+	for _, r := range directives.ranges {
+		if token.Offset >= r[0] && token.Offset < r[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter returns the tokens that are not suppressed
+func (directives *Directives) Filter(tokens []bingSpellCheck.FlaggedToken) []bingSpellCheck.FlaggedToken {
+	if len(directives.words) == 0 && len(directives.ranges) == 0 {
+		return tokens
+	}
+
+	filtered := make([]bingSpellCheck.FlaggedToken, 0, len(tokens))
+	for _, token := range tokens {
+		if !directives.Suppressed(token) {
+			filtered = append(filtered, token)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,114 @@
+package lint
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+func TestDirectivesSuppressed(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		word string
+		want bool
+	}{
+		{"no directives", "teh cat", "teh", false},
+		{"ignore", "// bingspell:ignore teh wrod\nteh cat", "teh", true},
+		{"ignore is case insensitive", "# bingspell:ignore Teh\nTEH cat", "TEH", true},
+		{"ignore with commas", "bingspell:ignore foo,wrod\nwrod", "wrod", true},
+		{"ignore ends at the comment", "/* bingspell:ignore teh */ mispeled\nmispeled", "mispeled", false},
+		{"ignore ends at an html comment", "<!-- bingspell:ignore teh --> mispeled\nmispeled", "mispeled", false},
+		{"ignore only lists words", "bingspell:ignore teh\nwrod", "wrod", false},
+		{"disable-next-line", "// bingspell:disable-next-line\nteh cat\nwrod", "teh", true},
+		{"disable-next-line only", "// bingspell:disable-next-line\nteh cat\nwrod", "wrod", false},
+		{"disable-line", "teh cat // bingspell:disable-line\nwrod", "teh", true},
+		{"disable-line only", "teh cat // bingspell:disable-line\nwrod", "wrod", false},
+		{"disable", "one\n// bingspell:disable\nteh\n// bingspell:enable\nwrod", "teh", true},
+		{"after enable", "one\n// bingspell:disable\nteh\n// bingspell:enable\nwrod", "wrod", false},
+		{"before disable", "wrod\n// bingspell:disable\nteh", "wrod", false},
+		{"disable to the end", "wrod\n// bingspell:disable\nteh", "teh", true},
+		{"directive itself", "// bingspell:ignore teh", "bingspell", true},
+		{"unknown directive", "// bingspell:disabled\nteh", "teh", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset := strings.LastIndex(tt.text, tt.word)
+			if offset < 0 {
+				t.Fatalf("%q is not in the text", tt.word)
+			}
+			token := bingSpellCheck.FlaggedToken{Offset: offset, Token: tt.word}
+
+			if got := ParseDirectives(tt.text).Suppressed(token); got != tt.want {
+				t.Errorf("Suppressed(%q) = %v, want %v", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirectivesFilter(t *testing.T) {
+	text := "teh\nwrod // bingspell:disable-line\ncta"
+	tokens := []bingSpellCheck.FlaggedToken{
+		{Offset: 0, Token: "teh"},
+		{Offset: 4, Token: "wrod"},
+		{Offset: strings.Index(text, "cta"), Token: "cta"},
+	}
+
+	filtered := ParseDirectives(text).Filter(tokens)
+	if len(filtered) != 2 || filtered[0].Token != "teh" || filtered[1].Token != "cta" {
+		t.Errorf("got %+v", filtered)
+	}
+}
+
+func TestAutoCorrect(t *testing.T) {
+	text := "teh cat\nwrod // bingspell:disable-line\n"
+	checker := bingSpellCheck.CheckerFunc(func(
+		ctx context.Context,
+		text string,
+		opts *bingSpellCheck.CheckOptions) (*bingSpellCheck.SpellCheckResponse, error) {
+
+		return &bingSpellCheck.SpellCheckResponse{
+			Type: "SpellCheck",
+			FlaggedTokens: []bingSpellCheck.FlaggedToken{
+				{
+					Offset:      0,
+					Token:       "teh",
+					Type:        "UnknownToken",
+					Suggestions: []bingSpellCheck.TokenSuggestion{{Score: 0.9, Suggestion: "the"}},
+				},
+				{
+					Offset:      8,
+					Token:       "wrod",
+					Type:        "UnknownToken",
+					Suggestions: []bingSpellCheck.TokenSuggestion{{Score: 0.9, Suggestion: "word"}},
+				},
+			},
+		}, nil
+	})
+
+	tests := []struct {
+		name   string
+		config *Config
+		path   string
+		want   string
+	}{
+		{"directives", nil, "", "the cat\nwrod // bingspell:disable-line\n"},
+		{"config words", &Config{Words: []string{"teh"}}, "a.md", text},
+		{"ignored path", &Config{Ignore: []string{"*.md"}}, "a.md", text},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AutoCorrect(context.Background(), checker, tt.config, tt.path, text, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+// Package lint finds the prose in documents, source code and commit
+// messages, and spell checks it with a bingSpellCheck.Checker
+//
+//  Notes
+//    Extractors blank out what should not be checked (code, URLs, comment
+//    markers, etc.) and return the remaining paragraphs as segments, with
+//    byte offsets into the original text. Blanking keeps every offset
+//    unchanged, so findings map straight back to the original text.
+//
+package lint
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// Extractor returns the segments of text that should be spell checked
+type Extractor func(text string) []bingSpellCheck.Segment
+
+var (
+	urlPattern        = regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^\s<>()"'` + "`" + `]+`)
+	inlineCodePattern = regexp.MustCompile("(`+)[^`]*?(`+)")
+	htmlTagPattern    = regexp.MustCompile(`</?[a-zA-Z][^<>\n]*>`)
+	linkTargetPattern = regexp.MustCompile(`\]\([^)\n]*\)`)
+	linkRefPattern    = regexp.MustCompile(`(?m)^ {0,3}\[[^\]\n]+\]:.*$`)
+	fencePattern      = regexp.MustCompile(`^ {0,3}(` + "```" + `|~~~)`)
+	trailerPattern    = regexp.MustCompile(`^[A-Z][A-Za-z]*(-[A-Za-z]+)+: |^(Fixes|Closes|Refs|Change-Id): `)
+	directivePattern  = regexp.MustCompile(`^([a-zA-Z-]+:\S|\+build|nolint|eslint|!)`)
+)
+
+// ExtractorFor returns the Extractor for the file at path, or nil if the
+// file type is not supported
+func ExtractorFor(path string) Extractor {
+	base := filepath.Base(path)
+	switch base {
+	case "COMMIT_EDITMSG", "MERGE_MSG", "TAG_EDITMSG", "SQUASH_MSG":
+		return ExtractCommitMessage
+	case "Makefile", "Dockerfile":
+		return commentExtractor(hashComments)
+	}
+
+	ext := strings.ToLower(filepath.Ext(base))
+	switch ext {
+	case ".md", ".markdown", ".mdx":
+		return ExtractMarkdown
+	case ".txt", ".text":
+		return ExtractText
+	case ".gitcommit":
+		return ExtractCommitMessage
+	}
+
+	if style, ok := commentStyles[ext]; ok {
+		return commentExtractor(style)
+	}
+
+	return nil
+}
+
+// ExtractorForLanguage returns the Extractor for an LSP language
+// identifier (e.g. "markdown", "go", "git-commit"), or nil if the language
+// is not supported
+func ExtractorForLanguage(languageID string) Extractor {
+	switch languageID {
+	case "markdown":
+		return ExtractMarkdown
+	case "plaintext":
+		return ExtractText
+	case "git-commit", "gitcommit":
+		return ExtractCommitMessage
+	}
+
+	if ext, ok := languageExtensions[languageID]; ok {
+		return commentExtractor(commentStyles[ext])
+	}
+
+	return nil
+}
+
+// ExtractText returns the paragraphs of plain text, without URLs
+func ExtractText(text string) []bingSpellCheck.Segment {
+	masked := []byte(text)
+	blankMatches(masked, urlPattern)
+
+	return segments(text, masked)
+}
+
+// ExtractMarkdown returns the paragraphs of a Markdown document, without
+// front matter, code blocks, inline code, HTML tags, link targets and URLs
+func ExtractMarkdown(text string) []bingSpellCheck.Segment {
+	masked := []byte(text)
+
+	lines := lineSpans(text)
+
+	// YAML front matter
+	first := 0
+	if len(lines) > 0 && isFrontMatterFence(text, lines[0]) {
+		for end := 1; end < len(lines); end++ {
+			if isFrontMatterFence(text, lines[end]) {
+				blank(masked, 0, lines[end][1])
+				first = end + 1
+				break
+			}
+		}
+	}
+
+	inFence := false
+	fence := ""
+	for _, span := range lines[first:] {
+		line := text[span[0]:span[1]]
+
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			if !inFence {
+				inFence, fence = true, m[1]
+			} else if m[1] == fence {
+				inFence = false
+			}
+			blank(masked, span[0], span[1])
+			continue
+		}
+
+		if inFence {
+			blank(masked, span[0], span[1])
+		}
+	}
+
+	for _, re := range []*regexp.Regexp{linkRefPattern, inlineCodePattern, linkTargetPattern, htmlTagPattern, urlPattern} {
+		blankMatches(masked, re)
+	}
+
+	return segments(text, masked)
+}
+
+func isFrontMatterFence(text string, span [2]int) bool {
+	return strings.TrimRight(text[span[0]:span[1]], "\r") == "---"
+}
+
+// ExtractCommitMessage returns the paragraphs of a git commit message,
+// without comment lines, the diff below the scissors line, trailers (e.g.
+// Signed-off-by), inline code and URLs
+func ExtractCommitMessage(text string) []bingSpellCheck.Segment {
+	masked := []byte(text)
+
+	for _, span := range lineSpans(text) {
+		line := text[span[0]:span[1]]
+		if strings.HasPrefix(line, "# ------------------------ >8 ------------------------") {
+			blank(masked, span[0], len(text))
+			break
+		}
+		if strings.HasPrefix(line, "#") || trailerPattern.MatchString(line) {
+			blank(masked, span[0], span[1])
+		}
+	}
+
+	blankMatches(masked, inlineCodePattern)
+	blankMatches(masked, urlPattern)
+
+	return segments(text, masked)
+}
+
+// commentStyle describes the comments and string literals of a language
+type commentStyle struct {
+	line       []string
+	blockStart string
+	blockEnd   string
+	quotes     string
+}
+
+var (
+	slashComments = commentStyle{line: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'`"}
+	hashComments  = commentStyle{line: []string{"#"}, quotes: "\"'"}
+	dashComments  = commentStyle{line: []string{"--"}, quotes: "\"'"}
+	cssComments   = commentStyle{blockStart: "/*", blockEnd: "*/", quotes: "\"'"}
+)
+
+var commentStyles = map[string]commentStyle{
+	".go": slashComments, ".c": slashComments, ".h": slashComments, ".cc": slashComments,
+	".cpp": slashComments, ".hpp": slashComments, ".java": slashComments, ".js": slashComments,
+	".jsx": slashComments, ".ts": slashComments, ".tsx": slashComments, ".cs": slashComments,
+	".swift": slashComments, ".kt": slashComments, ".rs": slashComments, ".scala": slashComments,
+	".php": slashComments, ".dart": slashComments, ".proto": slashComments,
+	".py": hashComments, ".rb": hashComments, ".sh": hashComments, ".bash": hashComments,
+	".zsh": hashComments, ".yaml": hashComments, ".yml": hashComments, ".toml": hashComments,
+	".pl": hashComments, ".r": hashComments, ".tf": hashComments,
+	".sql": dashComments, ".lua": dashComments, ".hs": dashComments,
+	".css": cssComments, ".scss": slashComments, ".less": slashComments,
+}
+
+// languageExtensions maps LSP language identifiers to file extensions
+var languageExtensions = map[string]string{
+	"go": ".go", "c": ".c", "cpp": ".cpp", "java": ".java", "javascript": ".js",
+	"javascriptreact": ".jsx", "typescript": ".ts", "typescriptreact": ".tsx",
+	"csharp": ".cs", "swift": ".swift", "kotlin": ".kt", "rust": ".rs", "scala": ".scala",
+	"php": ".php", "dart": ".dart", "proto": ".proto", "python": ".py", "ruby": ".rb",
+	"shellscript": ".sh", "yaml": ".yaml", "toml": ".toml", "perl": ".pl", "r": ".r",
+	"terraform": ".tf", "sql": ".sql", "lua": ".lua", "haskell": ".hs", "css": ".css",
+	"scss": ".scss", "less": ".less",
+}
+
+// commentExtractor returns an Extractor for the comments of a language
+func commentExtractor(style commentStyle) Extractor {
+	return func(text string) []bingSpellCheck.Segment {
+		return extractComments(text, style)
+	}
+}
+
+// extractComments returns the paragraphs of the comments in source code,
+// without comment markers, directives (e.g. //go:generate) and URLs
+func extractComments(text string, style commentStyle) []bingSpellCheck.Segment {
+	masked := []byte(text)
+	blank(masked, 0, len(text))
+
+	keep := func(start, end int) {
+		copy(masked[start:end], text[start:end])
+	}
+
+	for i := 0; i < len(text); {
+		switch {
+		case strings.IndexByte(style.quotes, text[i]) >= 0:
+			i = skipString(text, i)
+
+		case style.blockStart != "" && strings.HasPrefix(text[i:], style.blockStart):
+			start := i + len(style.blockStart)
+			end := strings.Index(text[start:], style.blockEnd)
+			if end < 0 {
+				end = len(text)
+			} else {
+				end += start
+			}
+			keep(start, end)
+			blankBlockMarkers(masked, text, start, end)
+			i = end + len(style.blockEnd)
+
+		default:
+			marker := lineMarker(text[i:], style.line)
+			if marker == "" {
+				i++
+				continue
+			}
+
+			start := i + len(marker)
+			end := strings.IndexByte(text[start:], '\n')
+			if end < 0 {
+				end = len(text)
+			} else {
+				end += start
+			}
+
+			// doc comment markers such as /// and #!
+			for start < end && text[start] == marker[len(marker)-1] {
+				start++
+			}
+			if !directivePattern.MatchString(text[i+len(marker) : end]) {
+				keep(start, end)
+			}
+			i = end
+		}
+	}
+
+	blankMatches(masked, urlPattern)
+
+	return segments(text, masked)
+}
+
+// lineMarker returns the line comment marker s starts with, if any
+func lineMarker(s string, markers []string) string {
+	for _, marker := range markers {
+		if strings.HasPrefix(s, marker) {
+			return marker
+		}
+	}
+
+	return ""
+}
+
+// skipString returns the offset after the string literal that starts at i
+func skipString(text string, i int) int {
+	quote := text[i]
+	for j := i + 1; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			if quote != '`' {
+				j++
+			}
+		case quote:
+			return j + 1
+		case '\n':
+			// unterminated (or a quote in a language without that literal)
+			if quote != '`' {
+				return j
+			}
+		}
+	}
+
+	return len(text)
+}
+
+// blankBlockMarkers blanks the leading "*" of the lines of a block comment
+func blankBlockMarkers(masked []byte, text string, start, end int) {
+	for i := start; i < end; i++ {
+		if text[i] != '\n' {
+			continue
+		}
+		j := i + 1
+		for j < end && (text[j] == ' ' || text[j] == '\t') {
+			j++
+		}
+		if j < end && text[j] == '*' {
+			masked[j] = ' '
+		}
+	}
+}
+
+// lineSpans returns the start and end offsets of each line of text,
+// excluding the newline
+func lineSpans(text string) [][2]int {
+	var spans [][2]int
+
+	start := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			spans = append(spans, [2]int{start, i})
+			start = i + 1
+		}
+	}
+	if start < len(text) {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+
+	return spans
+}
+
+// blank replaces masked[start:end] with spaces, keeping newlines
+func blank(masked []byte, start, end int) {
+	for i := start; i < end; i++ {
+		if masked[i] != '\n' {
+			masked[i] = ' '
+		}
+	}
+}
+
+func blankMatches(masked []byte, re *regexp.Regexp) {
+	for _, loc := range re.FindAllIndex(masked, -1) {
+		blank(masked, loc[0], loc[1])
+	}
+}
+
+// segments returns the non blank paragraphs of masked, trimmed, as segments
+// of text
+func segments(text string, masked []byte) []bingSpellCheck.Segment {
+	var result []bingSpellCheck.Segment
+
+	for _, paragraph := range bingSpellCheck.SplitParagraphs(string(masked)) {
+		trimmed := strings.TrimLeft(paragraph.Text, " \t\r\n")
+		offset := paragraph.Offset + len(paragraph.Text) - len(trimmed)
+		trimmed = strings.TrimRight(trimmed, " \t\r\n")
+		if trimmed == "" {
+			continue
+		}
+
+		result = append(result, bingSpellCheck.Segment{Offset: offset, Text: trimmed})
+	}
+
+	return result
+}
@@ -0,0 +1,158 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gotomgo/bingSpellCheck"
+)
+
+// GitDiff selects the changes of a git repository to check
+//
+//  Fields
+//    Dir    - A directory in the repository ("" for the current directory)
+//    Base   - The ref to compare with ("" for HEAD)
+//    Head   - The ref with the changes ("" for the working tree)
+//    Staged - Compare the index (the staged changes) with Base instead,
+//             ignoring Head
+//    Config - The project config (optional, see CheckDiff)
+//
+//  Notes
+//    Mirrors git diff: {Base: "main", Head: "HEAD"} is git diff main HEAD,
+//    {Staged: true} is git diff --cached (what a pre-commit hook checks) and
+//    the zero value is git diff HEAD
+//
+type GitDiff struct {
+	Dir    string
+	Base   string
+	Head   string
+	Staged bool
+	Config *Config
+
+	root string
+}
+
+// Files returns the files with added or modified lines
+//
+//  Notes
+//    Renamed and copied files are compared with their source, so only the
+//    lines that changed are returned
+//
+func (gd *GitDiff) Files(ctx context.Context) ([]FileDiff, error) {
+	args := []string{
+		"-c", "core.quotePath=false",
+		"diff", "--unified=0", "--no-color", "--no-ext-diff", "--find-renames", "--diff-filter=ACMR",
+	}
+
+	base := gd.Base
+	if base == "" {
+		base = "HEAD"
+	}
+
+	switch {
+	case gd.Staged:
+		args = append(args, "--cached", base)
+	case gd.Head != "":
+		args = append(args, base, gd.Head)
+	default:
+		args = append(args, base)
+	}
+
+	out, err := gd.git(ctx, append(args, "--")...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseDiff(bytes.NewReader(out))
+}
+
+// ReadFile returns the content of the new version of the file at path
+// (relative to the repository root)
+func (gd *GitDiff) ReadFile(ctx context.Context, path string) (string, error) {
+	switch {
+	case gd.Staged:
+		out, err := gd.git(ctx, "show", ":"+path)
+		return string(out), err
+	case gd.Head != "":
+		out, err := gd.git(ctx, "show", gd.Head+":"+path)
+		return string(out), err
+	}
+
+	if gd.root == "" {
+		out, err := gd.git(ctx, "rev-parse", "--show-toplevel")
+		if err != nil {
+			return "", err
+		}
+		gd.root = strings.TrimSpace(string(out))
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(gd.root, filepath.FromSlash(path)))
+	return string(data), err
+}
+
+// Check checks the changed lines of the diff and returns the findings
+func (gd *GitDiff) Check(
+	ctx context.Context,
+	checker bingSpellCheck.Checker,
+	opts *bingSpellCheck.CheckOptions) ([]Finding, error) {
+
+	files, err := gd.Files(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	readFile := func(path string) (string, error) {
+		return gd.ReadFile(ctx, path)
+	}
+
+	return CheckDiff(ctx, checker, gd.Config, files, readFile, opts)
+}
+
+// git runs git in gd.Dir and returns its output
+func (gd *GitDiff) git(ctx context.Context, args ...string) ([]byte, error) {
+	return Git(ctx, gd.Dir, args...)
+}
+
+// Git runs git with args in dir ("" for the current directory) and returns
+// its output
+//
+//  Notes
+//    The error includes what git wrote to stderr
+//
+func Git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", subcommand(args), msg)
+		}
+		return nil, fmt.Errorf("git %s: %v", subcommand(args), err)
+	}
+
+	return out, nil
+}
+
+// subcommand returns the git subcommand of args, skipping global options
+// such as -c name=value and -C dir
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-c" || arg == "-C":
+			i++
+		case !strings.HasPrefix(arg, "-"):
+			return arg
+		}
+	}
+
+	return "command"
+}
@@ -0,0 +1,132 @@
+package lint
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Position is a zero based line and character, where characters are
+// counted in UTF-16 code units (as in the Language Server Protocol)
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// LineIndex converts between byte offsets in a text and Positions
+type LineIndex struct {
+	text   string
+	starts []int
+}
+
+// NewLineIndex creates a LineIndex for text
+func NewLineIndex(text string) *LineIndex {
+	starts := []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+
+	return &LineIndex{text: text, starts: starts}
+}
+
+// Position returns the position of the byte offset
+func (li *LineIndex) Position(offset int) Position {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(li.text) {
+		offset = len(li.text)
+	}
+
+	line := sort.Search(len(li.starts), func(i int) bool { return li.starts[i] > offset }) - 1
+
+	return Position{Line: line, Character: utf16Len(li.text[li.starts[line]:offset])}
+}
+
+// Location returns the line and column of the byte offset, both 1 based,
+// with the column counted in characters (for messages such as
+// path:line:column)
+func (li *LineIndex) Location(offset int) (line, column int) {
+	pos := li.Position(offset)
+	start := li.starts[pos.Line]
+	end := li.Offset(pos)
+
+	return pos.Line + 1, utf8.RuneCountInString(li.text[start:end]) + 1
+}
+
+// Offset returns the byte offset of pos
+//
+//  Notes
+//    Positions past the end of a line are clamped to the end of the line,
+//    and positions past the last line to the end of the text
+//
+func (li *LineIndex) Offset(pos Position) int {
+	if pos.Line < 0 {
+		return 0
+	}
+	if pos.Line >= len(li.starts) {
+		return len(li.text)
+	}
+
+	offset := li.starts[pos.Line]
+	end := len(li.text)
+	if pos.Line+1 < len(li.starts) {
+		end = li.starts[pos.Line+1] - 1
+	}
+
+	for units := 0; offset < end; {
+		r, size := utf8.DecodeRuneInString(li.text[offset:end])
+		width := 1
+		if r >= 0x10000 {
+			width = 2
+		}
+		if units+width > pos.Character {
+			break
+		}
+		units += width
+		offset += size
+	}
+
+	return offset
+}
+
+// line returns the text of a line (0 based), without its line break
+func (li *LineIndex) line(n int) string {
+	start, end := li.lineBounds(n)
+	return strings.TrimSuffix(li.text[start:end], "\r")
+}
+
+// lineBounds returns the byte offsets of the start and end of a line (0
+// based), excluding its line break; lines past the last line are empty at
+// the end of the text
+func (li *LineIndex) lineBounds(n int) (start, end int) {
+	if n < 0 {
+		n = 0
+	}
+	if n >= len(li.starts) {
+		return len(li.text), len(li.text)
+	}
+
+	end = len(li.text)
+	if n+1 < len(li.starts) {
+		end = li.starts[n+1] - 1
+	}
+
+	return li.starts[n], end
+}
+
+// utf16Len returns the number of UTF-16 code units of s
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,104 @@
+package lint
+
+import "testing"
+
+// positionText has a character outside the BMP (two UTF-16 code units), a
+// two byte character and an empty line
+const positionText = "a😀b\ncafé\n\nx"
+
+func TestLineIndexPosition(t *testing.T) {
+	li := NewLineIndex(positionText)
+
+	tests := []struct {
+		offset     int
+		want       Position
+		wantLine   int
+		wantColumn int
+	}{
+		{0, Position{0, 0}, 1, 1},
+		{1, Position{0, 1}, 1, 2},
+		{5, Position{0, 3}, 1, 3},
+		{6, Position{0, 4}, 1, 4},
+		{7, Position{1, 0}, 2, 1},
+		{10, Position{1, 3}, 2, 4},
+		{12, Position{1, 4}, 2, 5},
+		{13, Position{2, 0}, 3, 1},
+		{14, Position{3, 0}, 4, 1},
+		{15, Position{3, 1}, 4, 2},
+		{-1, Position{0, 0}, 1, 1},
+		{99, Position{3, 1}, 4, 2},
+	}
+
+	for _, tt := range tests {
+		if got := li.Position(tt.offset); got != tt.want {
+			t.Errorf("Position(%d) = %+v, want %+v", tt.offset, got, tt.want)
+		}
+		if line, column := li.Location(tt.offset); line != tt.wantLine || column != tt.wantColumn {
+			t.Errorf("Location(%d) = %d:%d, want %d:%d", tt.offset, line, column, tt.wantLine, tt.wantColumn)
+		}
+	}
+}
+
+func TestLineIndexOffset(t *testing.T) {
+	li := NewLineIndex(positionText)
+
+	tests := []struct {
+		pos  Position
+		want int
+	}{
+		{Position{0, 0}, 0},
+		{Position{0, 1}, 1},
+		{Position{0, 2}, 1}, // inside the surrogate pair
+		{Position{0, 3}, 5},
+		{Position{0, 100}, 6},
+		{Position{1, 3}, 10},
+		{Position{1, 4}, 12},
+		{Position{2, 5}, 13},
+		{Position{3, 0}, 14},
+		{Position{3, 1}, 15},
+		{Position{9, 0}, 15},
+		{Position{-1, 0}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := li.Offset(tt.pos); got != tt.want {
+			t.Errorf("Offset(%+v) = %d, want %d", tt.pos, got, tt.want)
+		}
+	}
+}
+
+func TestLineIndexRoundTrip(t *testing.T) {
+	li := NewLineIndex(positionText)
+
+	for offset := 0; offset <= len(positionText); offset++ {
+		// offsets inside a multi byte character are not character boundaries
+		if offset < len(positionText) && positionText[offset]&0xC0 == 0x80 {
+			continue
+		}
+		if got := li.Offset(li.Position(offset)); got != offset {
+			t.Errorf("Offset(Position(%d)) = %d", offset, got)
+		}
+	}
+}
+
+func TestLineIndexLine(t *testing.T) {
+	li := NewLineIndex("one\r\ntwo\n\nthree")
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "one"},
+		{1, "two"},
+		{2, ""},
+		{3, "three"},
+		{4, ""},
+		{-1, "one"},
+	}
+
+	for _, tt := range tests {
+		if got := li.line(tt.n); got != tt.want {
+			t.Errorf("line(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
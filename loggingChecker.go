@@ -0,0 +1,46 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// LoggingChecker is a Checker that logs each check made using another
+// Checker
+//
+//  Notes
+//    The text being checked is never logged, only its length
+//
+type LoggingChecker struct {
+	next   Checker
+	logger *log.Logger
+}
+
+// NewLoggingChecker creates a LoggingChecker that logs to logger, or to the
+// standard logger if logger is nil
+func NewLoggingChecker(next Checker, logger *log.Logger) *LoggingChecker {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &LoggingChecker{next: next, logger: logger}
+}
+
+// Check checks text using the wrapped Checker and logs the outcome
+func (lc *LoggingChecker) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	start := time.Now()
+	scr, err := lc.next.Check(ctx, text, opts)
+	elapsed := time.Since(start)
+
+	switch {
+	case err != nil:
+		lc.logger.Printf("spell check failed: length=%d elapsed=%s error=%v", len(text), elapsed, err)
+	case scr.IsErrorResponse():
+		lc.logger.Printf("spell check error response: length=%d elapsed=%s errors=%v", len(text), elapsed, scr.Errors)
+	default:
+		lc.logger.Printf("spell check: length=%d elapsed=%s flagged=%d", len(text), elapsed, len(scr.FlaggedTokens))
+	}
+
+	return scr, err
+}
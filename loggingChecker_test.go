@@ -0,0 +1,41 @@
+package bingSpellCheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestLoggingChecker(t *testing.T) {
+	const text = "secret teh text"
+
+	tests := []struct {
+		name string
+		stub *stubChecker
+		want string
+	}{
+		{"success", flagging(unknown(7, "teh", "the")), "spell check: length=15 "},
+		{"error", failing(errors.New("unavailable")), "spell check failed: length=15 "},
+		{"error response", responding(errorResponse(ServerErrorCode)), "spell check error response: length=15 "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			lc := NewLoggingChecker(tt.stub, log.New(&buf, "", 0))
+
+			lc.Check(context.Background(), text, nil)
+
+			got := buf.String()
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("logged %q, want prefix %q", got, tt.want)
+			}
+			if strings.Contains(got, "secret") || strings.Contains(got, "teh") {
+				t.Errorf("logged the checked text: %q", got)
+			}
+		})
+	}
+}
@@ -29,7 +29,7 @@ const (
 	// MktDenmark is the language code for Denmark, Danish
 	MktDenmark MarketCode = "da-DK"
 	// MktFinland is the language code for Finland, Finnish
-	MktFinland MarketCode = "fi-FL"
+	MktFinland MarketCode = "fi-FI"
 	// MktFrance is the language code for France, French
 	MktFrance MarketCode = "fr-FR"
 	// MktGermany is the language code for Germany, German
@@ -0,0 +1,183 @@
+package bingSpellCheck
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Names of the metrics recorded by Client and the Checker decorators
+const (
+	// MetricRequests counts requests sent to Bing, labeled by market, tag
+	// and status (the HTTP status code, or "error" if no response was
+	// received)
+	MetricRequests = "bingspellcheck_requests_total"
+
+	// MetricTransactions counts successful (billable) requests, labeled by
+	// market and tag
+	MetricTransactions = "bingspellcheck_transactions_total"
+
+	// MetricRequestDuration is a histogram of request latency in seconds,
+	// labeled by market and tag
+	MetricRequestDuration = "bingspellcheck_request_duration_seconds"
+
+	// MetricErrors counts errors, labeled by market, tag, code and sub_code
+	// (Error.Code and Error.SubCode, or "RequestFailed" if no response was
+	// received)
+	MetricErrors = "bingspellcheck_errors_total"
+
+	// MetricFlaggedTokens is a histogram of the number of flagged tokens per
+	// successful request, labeled by market and tag
+	MetricFlaggedTokens = "bingspellcheck_flagged_tokens"
+
+	// MetricRetries counts retries made by RetryChecker, labeled by tag
+	MetricRetries = "bingspellcheck_retries_total"
+
+	// MetricCacheHits counts responses served by CachedChecker, labeled by
+	// tag
+	MetricCacheHits = "bingspellcheck_cache_hits_total"
+
+	// MetricCacheMisses counts checks CachedChecker could not serve, labeled
+	// by tag
+	MetricCacheMisses = "bingspellcheck_cache_misses_total"
+
+	// MetricRateLimiterWait is a histogram of the time RateLimitedChecker
+	// waited before a check, in seconds, labeled by tag
+	MetricRateLimiterWait = "bingspellcheck_rate_limiter_wait_seconds"
+)
+
+// Metric label names
+const (
+	MarketLabel  = "market"
+	TagLabel     = "tag"
+	StatusLabel  = "status"
+	CodeLabel    = "code"
+	SubCodeLabel = "sub_code"
+)
+
+// RequestFailedCode is the code label of MetricErrors when no response was
+// received
+const RequestFailedCode = "RequestFailed"
+
+// metricHelp describes the metrics, for adapters that expose descriptions
+var metricHelp = map[string]string{
+	MetricRequests:        "Requests sent to the Bing Spell Check API.",
+	MetricTransactions:    "Successful (billable) Bing Spell Check API requests.",
+	MetricRequestDuration: "Bing Spell Check API request latency in seconds.",
+	MetricErrors:          "Bing Spell Check API errors by code and sub code.",
+	MetricFlaggedTokens:   "Flagged tokens per successful request.",
+	MetricRetries:         "Retried spell checks.",
+	MetricCacheHits:       "Spell checks served from the cache.",
+	MetricCacheMisses:     "Spell checks not served from the cache.",
+	MetricRateLimiterWait: "Time spent waiting for the rate limiter in seconds.",
+}
+
+// Labels are the dimensions of a metric, e.g. {"market": "en-US"}
+type Labels map[string]string
+
+// key returns the labels in a canonical order, as name="value" pairs
+// separated by commas (the Prometheus text format)
+func (labels Labels) key() string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + labelEscaper.Replace(labels[name]) + `"`
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// labelEscaper escapes label values for the Prometheus text format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Metrics receives the metrics recorded by Client and the Checker
+// decorators (see the Metric constants for names and labels)
+//
+//  Notes
+//    Add increments a counter and Observe records a value in a histogram.
+//    Implementations must be safe for concurrent use. See
+//    PrometheusMetrics and ExpvarMetrics.
+//
+type Metrics interface {
+	Add(name string, labels Labels, delta float64)
+	Observe(name string, labels Labels, value float64)
+}
+
+// WithMetrics records metrics for each request the client sends
+func (client *Client) WithMetrics(metrics Metrics) *Client {
+	client.metrics = metrics
+	return client
+}
+
+// WithMetrics records MetricRetries
+func (rc *RetryChecker) WithMetrics(metrics Metrics) *RetryChecker {
+	rc.metrics = metrics
+	return rc
+}
+
+// WithMetrics records MetricCacheHits and MetricCacheMisses
+func (cc *CachedChecker) WithMetrics(metrics Metrics) *CachedChecker {
+	cc.metrics = metrics
+	return cc
+}
+
+// WithMetrics records MetricRateLimiterWait
+func (rlc *RateLimitedChecker) WithMetrics(metrics Metrics) *RateLimitedChecker {
+	rlc.metrics = metrics
+	return rlc
+}
+
+// tagLabels returns the labels of metrics that are only labeled by tag
+func tagLabels(opts *CheckOptions) Labels {
+	if opts == nil {
+		return Labels{TagLabel: ""}
+	}
+
+	return Labels{TagLabel: opts.Tag}
+}
+
+// recordRequest records the metrics of a request sent to Bing
+func recordRequest(metrics Metrics, market, tag string, seconds float64, scr *SpellCheckResponse, err error) {
+	labels := Labels{MarketLabel: market, TagLabel: tag}
+	metrics.Observe(MetricRequestDuration, labels, seconds)
+
+	withLabel := func(name, value string) Labels {
+		merged := Labels{name: value}
+		for k, v := range labels {
+			merged[k] = v
+		}
+		return merged
+	}
+
+	switch {
+	case err != nil:
+		metrics.Add(MetricRequests, withLabel(StatusLabel, "error"), 1)
+		errLabels := withLabel(CodeLabel, RequestFailedCode)
+		errLabels[SubCodeLabel] = ""
+		metrics.Add(MetricErrors, errLabels, 1)
+		return
+
+	case scr.Meta != nil:
+		metrics.Add(MetricRequests, withLabel(StatusLabel, strconv.Itoa(scr.Meta.StatusCode)), 1)
+
+	default:
+		metrics.Add(MetricRequests, withLabel(StatusLabel, ""), 1)
+	}
+
+	if scr.IsErrorResponse() {
+		for _, bingErr := range scr.Errors {
+			errLabels := withLabel(CodeLabel, bingErr.Code)
+			errLabels[SubCodeLabel] = bingErr.SubCode
+			metrics.Add(MetricErrors, errLabels, 1)
+		}
+		return
+	}
+
+	metrics.Add(MetricTransactions, labels, 1)
+	metrics.Observe(MetricFlaggedTokens, labels, float64(len(scr.FlaggedTokens)))
+}
@@ -0,0 +1,184 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MergeStrategy determines how a MultiChecker combines the findings of its
+// checkers
+type MergeStrategy int
+
+const (
+	// MergeUnion reports every token flagged by any checker
+	MergeUnion MergeStrategy = iota
+
+	// MergeVote reports tokens flagged by at least a quorum of the checkers
+	// that responded successfully
+	MergeVote
+)
+
+// MultiChecker is a Checker that queries several checkers concurrently and
+// merges their findings
+//
+//  Notes
+//    Flagged tokens are considered the same when they have the same Offset,
+//    Token and Type. Suggestions for the same token are merged, keeping the
+//    highest score for each suggestion, and ordered by decreasing score.
+//
+//    Checkers may tokenize differently, so a token that overlaps an earlier
+//    one (for example "recieve" within "recieve it") is dropped; of tokens
+//    at the same offset, the one flagged by the most checkers is kept.
+//
+type MultiChecker struct {
+	// Quorum is the number of checkers that must flag a token for it to be
+	// reported by MergeVote. When 0, a majority of the successful responses
+	// is required.
+	Quorum int
+
+	strategy MergeStrategy
+	checkers []Checker
+}
+
+// NewMultiChecker creates a MultiChecker that merges the findings of
+// checkers using strategy
+func NewMultiChecker(strategy MergeStrategy, checkers ...Checker) *MultiChecker {
+	return &MultiChecker{strategy: strategy, checkers: checkers}
+}
+
+// Check checks text with every checker and merges the results
+//
+//  Notes
+//    Checkers that fail or return an error response are ignored. If they all
+//    fail, the first error (or error response) is returned.
+//
+func (mc *MultiChecker) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	responses := make([]*SpellCheckResponse, len(mc.checkers))
+	errs := make([]error, len(mc.checkers))
+
+	var wg sync.WaitGroup
+	for i, checker := range mc.checkers {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			responses[i], errs[i] = checker.Check(ctx, text, opts)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	var succeeded []*SpellCheckResponse
+	for i, scr := range responses {
+		if errs[i] == nil && !scr.IsErrorResponse() {
+			succeeded = append(succeeded, scr)
+		}
+	}
+
+	if len(succeeded) == 0 {
+		for i, err := range errs {
+			if err != nil || responses[i] != nil {
+				return responses[i], err
+			}
+		}
+		return &SpellCheckResponse{Type: SpellCheckResponseType, FlaggedTokens: []FlaggedToken{}}, nil
+	}
+
+	return mc.merge(succeeded), nil
+}
+
+type tokenKey struct {
+	offset int
+	token  string
+	kind   string
+}
+
+type tokenVotes struct {
+	token FlaggedToken
+	votes int
+}
+
+func (mc *MultiChecker) merge(responses []*SpellCheckResponse) *SpellCheckResponse {
+	merged := map[tokenKey]*tokenVotes{}
+	var order []tokenKey
+
+	for _, scr := range responses {
+		seen := map[tokenKey]bool{}
+		for _, token := range scr.FlaggedTokens {
+			key := tokenKey{offset: token.Offset, token: token.Token, kind: token.Type}
+
+			tv, ok := merged[key]
+			if !ok {
+				tv = &tokenVotes{token: FlaggedToken{Offset: token.Offset, Token: token.Token, Type: token.Type}}
+				merged[key] = tv
+				order = append(order, key)
+			}
+			if !seen[key] {
+				seen[key] = true
+				tv.votes++
+			}
+			tv.token.Suggestions = mergeSuggestions(tv.token.Suggestions, token.Suggestions)
+		}
+	}
+
+	quorum := mc.Quorum
+	if quorum <= 0 {
+		quorum = len(responses)/2 + 1
+	}
+
+	var kept []*tokenVotes
+	for _, key := range order {
+		tv := merged[key]
+		if mc.strategy == MergeVote && tv.votes < quorum {
+			continue
+		}
+		kept = append(kept, tv)
+	}
+
+	result := &SpellCheckResponse{Type: SpellCheckResponseType, FlaggedTokens: []FlaggedToken{}}
+
+	// BuildAutoCorrectedText expects tokens ordered by offset
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].token.Offset != kept[j].token.Offset {
+			return kept[i].token.Offset < kept[j].token.Offset
+		}
+		return kept[i].votes > kept[j].votes
+	})
+
+	// and that they do not overlap
+	end := 0
+	for _, tv := range kept {
+		if tv.token.Offset < end {
+			continue
+		}
+		result.FlaggedTokens = append(result.FlaggedTokens, tv.token)
+		end = tv.token.Offset + len(tv.token.Token)
+	}
+
+	return result
+}
+
+// mergeSuggestions adds suggestions to merged, keeping the highest score for
+// duplicates, ordered by decreasing score
+func mergeSuggestions(merged, suggestions []TokenSuggestion) []TokenSuggestion {
+	for _, suggestion := range suggestions {
+		found := false
+		for i := range merged {
+			if merged[i].Suggestion == suggestion.Suggestion {
+				found = true
+				if suggestion.Score > merged[i].Score {
+					merged[i].Score = suggestion.Score
+				}
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, suggestion)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+
+	return merged
+}
@@ -0,0 +1,151 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMultiChecker(t *testing.T) {
+	teh := unknown(0, "teh", "the")
+	tehTen := unknown(0, "teh", "ten")
+	wrod := unknown(8, "wrod", "word")
+	repeated := FlaggedToken{Offset: 4, Token: "cat", Type: RepeatedTokenType}
+	failed := failing(errors.New("unavailable"))
+
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		quorum   int
+		checkers []Checker
+		want     []FlaggedToken
+	}{
+		{
+			name:     "union",
+			strategy: MergeUnion,
+			checkers: []Checker{flagging(wrod), flagging(teh), flagging(repeated)},
+			want:     []FlaggedToken{teh, repeated, wrod},
+		},
+		{
+			name:     "union merges suggestions",
+			strategy: MergeUnion,
+			checkers: []Checker{flagging(teh), flagging(tehTen)},
+			want: []FlaggedToken{{
+				Offset:      0,
+				Token:       "teh",
+				Type:        UnknownTokenType,
+				Suggestions: []TokenSuggestion{{Score: 0.9, Suggestion: "the"}, {Score: 0.9, Suggestion: "ten"}},
+			}},
+		},
+		{
+			name:     "union ignores failed checkers",
+			strategy: MergeUnion,
+			checkers: []Checker{failed, flagging(teh), responding(errorResponse(ServerErrorCode))},
+			want:     []FlaggedToken{teh},
+		},
+		{
+			name:     "vote majority",
+			strategy: MergeVote,
+			checkers: []Checker{flagging(teh, wrod), flagging(teh), flagging(teh, repeated)},
+			want:     []FlaggedToken{teh},
+		},
+		{
+			name:     "vote counts successful checkers",
+			strategy: MergeVote,
+			checkers: []Checker{flagging(teh, wrod), flagging(teh), failed},
+			want:     []FlaggedToken{teh},
+		},
+		{
+			name:     "vote counts a checker once",
+			strategy: MergeVote,
+			checkers: []Checker{flagging(wrod, wrod), flagging(teh), flagging(teh)},
+			want:     []FlaggedToken{teh},
+		},
+		{
+			name:     "vote quorum",
+			strategy: MergeVote,
+			quorum:   1,
+			checkers: []Checker{flagging(wrod), flagging(teh), flagging(teh)},
+			want:     []FlaggedToken{teh, wrod},
+		},
+		{
+			name:     "vote quorum not reached",
+			strategy: MergeVote,
+			quorum:   3,
+			checkers: []Checker{flagging(teh, wrod), flagging(teh), flagging(wrod)},
+			want:     []FlaggedToken{},
+		},
+		{
+			name:     "overlapping tokens",
+			strategy: MergeUnion,
+			checkers: []Checker{flagging(unknown(0, "recieve it", "receive it")), flagging(unknown(0, "recieve", "receive"), unknown(11, "nwo", "now"))},
+			want:     []FlaggedToken{unknown(0, "recieve it", "receive it"), unknown(11, "nwo", "now")},
+		},
+		{
+			name:     "overlapping tokens with more votes",
+			strategy: MergeUnion,
+			checkers: []Checker{
+				flagging(unknown(0, "recieve it", "receive it")),
+				flagging(unknown(0, "recieve", "receive")),
+				flagging(unknown(0, "recieve", "receive")),
+			},
+			want: []FlaggedToken{unknown(0, "recieve", "receive")},
+		},
+		{
+			name:     "token inside an earlier token",
+			strategy: MergeUnion,
+			checkers: []Checker{flagging(unknown(0, "teh wrod", "the word")), flagging(unknown(4, "wrod", "word"))},
+			want:     []FlaggedToken{unknown(0, "teh wrod", "the word")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := NewMultiChecker(tt.strategy, tt.checkers...)
+			mc.Quorum = tt.quorum
+
+			scr, err := mc.Check(context.Background(), "teh cat wrod", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(scr.FlaggedTokens, tt.want) {
+				t.Errorf("got %+v, want %+v", scr.FlaggedTokens, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiCheckerAllFail(t *testing.T) {
+	err := errors.New("first")
+	mc := NewMultiChecker(MergeUnion, failing(err), failing(errors.New("second")), responding(errorResponse(ServerErrorCode)))
+
+	if _, got := mc.Check(context.Background(), "text", nil); got != err {
+		t.Errorf("got error %v, want %v", got, err)
+	}
+
+	mc = NewMultiChecker(MergeVote, responding(errorResponse(ServerErrorCode)))
+	scr, got := mc.Check(context.Background(), "text", nil)
+	if got != nil || !scr.IsErrorResponse() {
+		t.Errorf("got %+v, %v; want the error response", scr, got)
+	}
+}
+
+func TestMultiCheckerAutoCorrectOverlap(t *testing.T) {
+	bing := flagging(unknown(0, "recieve it", "receive it"))
+	hunspell := flagging(unknown(0, "recieve", "receive"), unknown(11, "tomorow", "tomorrow"))
+
+	text := "recieve it tomorow"
+	scr, err := NewMultiChecker(MergeUnion, bing, hunspell).Check(context.Background(), text, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := BuildAutoCorrectedText(text, scr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "receive it tomorrow"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"regexp"
+	"unicode/utf8"
+)
+
+// DefaultMinConfidence is the default MultilingualChecker.MinConfidence
+const DefaultMinConfidence = 0.2
+
+var paragraphBreak = regexp.MustCompile(`\n[ \t\r]*\n\s*`)
+
+// Segment is a part of a larger text
+type Segment struct {
+	// Offset is the byte offset of the segment in the larger text
+	Offset int
+	Text   string
+}
+
+// SplitParagraphs splits text into paragraphs separated by blank lines
+func SplitParagraphs(text string) []Segment {
+	var segments []Segment
+
+	start := 0
+	for _, loc := range paragraphBreak.FindAllStringIndex(text, -1) {
+		if loc[0] > start {
+			segments = append(segments, Segment{Offset: start, Text: text[start:loc[0]]})
+		}
+		start = loc[1]
+	}
+	if start < len(text) {
+		segments = append(segments, Segment{Offset: start, Text: text[start:]})
+	}
+
+	return segments
+}
+
+// SegmentResult is the result of checking one segment of a multilingual text
+//
+//  Fields
+//    Segment   - The text that was checked and its offset in the full text
+//    Detection - The language detected for the segment
+//    Market    - The market the segment was checked with ("" when the
+//      default market was used)
+//    Response  - The response for the segment; offsets are relative to the
+//      segment
+//
+type SegmentResult struct {
+	Segment
+	Detection Detection
+	Market    MarketCode
+	Response  *SpellCheckResponse
+}
+
+// MultilingualChecker is a Checker that detects the language of each
+// paragraph of a text and checks it using the matching market
+//
+//  Notes
+//    Adjacent paragraphs in the same language are checked in one request.
+//    Paragraphs whose language cannot be detected with at least
+//    MinConfidence are checked with the previous paragraph, or with the
+//    next one if they come first. Text in which no language can be
+//    detected is checked with the market given in the CheckOptions.
+//
+type MultilingualChecker struct {
+	// MinConfidence is the confidence needed to use a detected language
+	MinConfidence float64
+
+	next Checker
+}
+
+// NewMultilingualChecker creates a MultilingualChecker that routes each
+// paragraph to next with the market of its detected language
+func NewMultilingualChecker(next Checker) *MultilingualChecker {
+	return &MultilingualChecker{MinConfidence: DefaultMinConfidence, next: next}
+}
+
+// Check checks text and merges the results of each segment into a single
+// response, with offsets relative to text
+//
+//  Notes
+//    If any segment fails, its error (or error response) is returned
+//
+func (mc *MultilingualChecker) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	results, err := mc.CheckSegments(ctx, text, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &SpellCheckResponse{Type: SpellCheckResponseType, FlaggedTokens: []FlaggedToken{}}
+	for _, result := range results {
+		if result.Response.IsErrorResponse() {
+			return result.Response, nil
+		}
+		for _, token := range result.Response.FlaggedTokens {
+			token.Offset += result.Offset
+			merged.FlaggedTokens = append(merged.FlaggedTokens, token)
+		}
+	}
+
+	return merged, nil
+}
+
+// CheckSegments checks text one language segment at a time and returns the
+// result for each segment, including the detected language and confidence
+func (mc *MultilingualChecker) CheckSegments(ctx context.Context, text string, opts *CheckOptions) ([]SegmentResult, error) {
+	if opts == nil {
+		opts = &CheckOptions{}
+	}
+
+	segments := mc.plan(text)
+
+	results := make([]SegmentResult, 0, len(segments))
+	for i, result := range segments {
+		segmentOpts := *opts
+		if i > 0 {
+			segmentOpts.PreContext = ""
+		}
+		if i < len(segments)-1 {
+			segmentOpts.PostContext = ""
+		}
+		if result.Market != "" {
+			segmentOpts.Market = result.Market
+			if !result.Market.SupportsMode(ProofMode) {
+				segmentOpts.Mode = SpellMode
+			}
+		}
+
+		scr, err := mc.next.Check(ctx, result.Text, &segmentOpts)
+		if err != nil {
+			return results, err
+		}
+
+		result.Response = scr
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// plan splits text into paragraphs, detects their language, and merges
+// adjacent paragraphs that use the same market
+func (mc *MultilingualChecker) plan(text string) []SegmentResult {
+	var planned []SegmentResult
+
+	for _, paragraph := range SplitParagraphs(text) {
+		detection := DetectLanguage(paragraph.Text)
+
+		market := detection.Market
+		confident := market != "" && detection.Confidence >= mc.MinConfidence
+
+		if len(planned) > 0 {
+			last := &planned[len(planned)-1]
+			end := paragraph.Offset + len(paragraph.Text)
+
+			// leading paragraphs that could not be identified (e.g. a title)
+			// are checked with the first paragraph that could
+			adopt := confident && last.Market == "" && len(planned) == 1
+
+			fits := utf8.RuneCountInString(text[last.Offset:end]) <= MaxPostTextLength
+			if (!confident || adopt || last.Market == market) && fits {
+				last.Text = text[last.Offset:end]
+				if adopt || (confident && detection.Confidence > last.Detection.Confidence) {
+					last.Detection = detection
+					last.Market = market
+				}
+				continue
+			}
+		}
+
+		if !confident {
+			market = ""
+		}
+
+		planned = append(planned, SegmentResult{Segment: paragraph, Detection: detection, Market: market})
+	}
+
+	return planned
+}
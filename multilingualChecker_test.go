@@ -0,0 +1,181 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const (
+	englishParagraph = "The weather was nice, so we went for a walk in the park with the children after lunch."
+	frenchParagraph  = "Il faisait beau, alors nous sommes allés nous promener dans le parc avec les enfants après le déjeuner."
+	germanParagraph  = "Das Wetter war schön, also sind wir nach dem Mittagessen mit den Kindern im Park spazieren gegangen."
+	russianParagraph = "Погода была хорошая, и мы пошли гулять в парк с детьми после обеда. "
+)
+
+func TestSplitParagraphs(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"one", []string{"one"}},
+		{"one\ntwo", []string{"one\ntwo"}},
+		{"one\n\ntwo", []string{"one", "two"}},
+		{"one\r\n \r\n\n two\n\n", []string{"one\r", "two"}},
+		{"\n\none", []string{"one"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, segment := range SplitParagraphs(tt.text) {
+			if tt.text[segment.Offset:segment.Offset+len(segment.Text)] != segment.Text {
+				t.Errorf("%q: segment %q is not at %d", tt.text, segment.Text, segment.Offset)
+			}
+			got = append(got, segment.Text)
+		}
+
+		if !equalStrings(got, tt.want) {
+			t.Errorf("SplitParagraphs(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// segmentChecker returns a stubChecker that records the options of each
+// check and flags the first word of each text
+func segmentChecker(sent *[]CheckOptions) *stubChecker {
+	return &stubChecker{respond: func(text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+		*sent = append(*sent, *opts)
+
+		word := strings.Fields(text)[0]
+		return spellCheckResponse(unknown(0, word)), nil
+	}}
+}
+
+func TestMultilingualCheckerSegments(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		markets []MarketCode
+		modes   []string
+	}{
+		{
+			name:    "one language",
+			text:    englishParagraph + "\n\n" + englishParagraph,
+			markets: []MarketCode{MktUnitedStates},
+			modes:   []string{""},
+		},
+		{
+			name:    "languages",
+			text:    englishParagraph + "\n\n" + frenchParagraph + "\n\n" + germanParagraph,
+			markets: []MarketCode{MktUnitedStates, MktFrance, MktGermany},
+			modes:   []string{"", SpellMode, SpellMode},
+		},
+		{
+			name:    "title adopted by the first paragraph",
+			text:    "Le 14 juillet\n\n" + frenchParagraph,
+			markets: []MarketCode{MktFrance},
+			modes:   []string{SpellMode},
+		},
+		{
+			name:    "undetected paragraph joins the previous one",
+			text:    germanParagraph + "\n\n12345\n\n" + germanParagraph,
+			markets: []MarketCode{MktGermany},
+			modes:   []string{SpellMode},
+		},
+		{
+			name:    "nothing detected",
+			text:    "12345\n\n67890",
+			markets: []MarketCode{"en-GB"},
+			modes:   []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []CheckOptions
+			opts := &CheckOptions{Market: "en-GB", PreContext: "pre", PostContext: "post"}
+
+			results, err := NewMultilingualChecker(segmentChecker(&sent)).CheckSegments(context.Background(), tt.text, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != len(tt.markets) {
+				t.Fatalf("got %d segments, want %d", len(results), len(tt.markets))
+			}
+
+			for i, result := range results {
+				if sent[i].Market != tt.markets[i] || sent[i].Mode != tt.modes[i] {
+					t.Errorf("segment %d checked with %s (%q), want %s (%q)", i, sent[i].Market, sent[i].Mode, tt.markets[i], tt.modes[i])
+				}
+				if tt.text[result.Offset:result.Offset+len(result.Text)] != result.Text {
+					t.Errorf("segment %d %q is not at %d", i, result.Text, result.Offset)
+				}
+				if (i == 0) != (sent[i].PreContext == "pre") || (i == len(results)-1) != (sent[i].PostContext == "post") {
+					t.Errorf("segment %d sent with context %q, %q", i, sent[i].PreContext, sent[i].PostContext)
+				}
+			}
+		})
+	}
+}
+
+func TestMultilingualCheckerMergesOffsets(t *testing.T) {
+	var sent []CheckOptions
+	text := englishParagraph + "\n\n" + frenchParagraph
+
+	scr, err := NewMultilingualChecker(segmentChecker(&sent)).Check(context.Background(), text, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int{0, strings.Index(text, "Il")}
+	if len(scr.FlaggedTokens) != len(want) {
+		t.Fatalf("flagged %+v", scr.FlaggedTokens)
+	}
+	for i, token := range scr.FlaggedTokens {
+		if token.Offset != want[i] {
+			t.Errorf("token %q at %d, want %d", token.Token, token.Offset, want[i])
+		}
+	}
+
+	failed := NewMultilingualChecker(responding(errorResponse(InvalidRequestErrorCode)))
+	if scr, err := failed.Check(context.Background(), text, nil); err != nil || !scr.IsErrorResponse() {
+		t.Errorf("got %+v, %v; want the error response", scr, err)
+	}
+}
+
+func TestMultilingualCheckerSegmentLength(t *testing.T) {
+	// each paragraph is 4000 characters, but 7000 or more bytes
+	paragraph := strings.Repeat(russianParagraph, 4000/utf8.RuneCountInString(russianParagraph))
+	if utf8.RuneCountInString(paragraph) > 4000 || len(paragraph) < 7000 {
+		t.Fatalf("paragraph of %d characters, %d bytes", utf8.RuneCountInString(paragraph), len(paragraph))
+	}
+
+	tests := []struct {
+		name       string
+		paragraphs int
+		want       int
+	}{
+		{"fits in characters, not in bytes", 2, 1},
+		{"too many characters", 3, 2},
+	}
+
+	for _, tt := range tests {
+		var sent []CheckOptions
+		text := strings.Repeat(paragraph+"\n\n", tt.paragraphs)
+
+		results, err := NewMultilingualChecker(segmentChecker(&sent)).CheckSegments(context.Background(), text, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != tt.want {
+			t.Errorf("%s: got %d segments, want %d", tt.name, len(results), tt.want)
+		}
+		for _, result := range results {
+			if n := utf8.RuneCountInString(result.Text); n > MaxPostTextLength {
+				t.Errorf("%s: segment of %d characters", tt.name, n)
+			}
+		}
+	}
+}
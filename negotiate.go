@@ -0,0 +1,208 @@
+package bingSpellCheck
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LanguageRange is a language range from an Accept-Language header
+type LanguageRange struct {
+	Tag     string
+	Quality float64
+}
+
+// defaultMarkets is the market used for a language when there is no market
+// for the requested region
+var defaultMarkets = map[string]MarketCode{
+	"da": MktDenmark,
+	"de": MktGermany,
+	"en": MktUnitedStates,
+	"es": MktSpain,
+	"fi": MktFinland,
+	"fr": MktFrance,
+	"it": MktItaly,
+	"ja": MktJapan,
+	"ko": MktKorea,
+	"nl": MktNetherlands,
+	"no": MktNorway,
+	"pl": MktPoland,
+	"pt": MktBrazil,
+	"ru": MktRussia,
+	"sv": MktSweden,
+	"tr": MktTurkey,
+	"zh": MktChina,
+}
+
+// languageAliases maps language subtags to the language used by the market
+// catalog
+var languageAliases = map[string]string{
+	"nb":  "no",
+	"nn":  "no",
+	"nob": "no",
+	"nno": "no",
+}
+
+// ParseAcceptLanguage parses an RFC 7231 Accept-Language header into
+// language ranges ordered by decreasing quality
+//
+//  Notes
+//    Ranges with equal quality keep their order in the header. Ranges with a
+//    quality of 0 (not acceptable) and malformed ranges are dropped.
+//
+func ParseAcceptLanguage(header string) []LanguageRange {
+	var ranges []LanguageRange
+
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		valid := true
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(param[2:], 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			quality = q
+		}
+
+		if valid && quality > 0 {
+			ranges = append(ranges, LanguageRange{Tag: tag, Quality: quality})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].Quality > ranges[j].Quality
+	})
+
+	return ranges
+}
+
+// MatchMarket returns the supported market that best matches a BCP 47
+// language tag such as "en-GB", "de-LU", "pt" or "zh-Hant-TW"
+//
+//  Notes
+//    An exact language and region match is preferred. Otherwise the
+//    language's default market is used, e.g. "de-LU" matches de-DE and "pt"
+//    matches pt-BR. Traditional Chinese script ("zh-Hant") matches zh-TW.
+//
+func MatchMarket(tag string) (MarketCode, bool) {
+	lang, script, region := splitLanguageTag(tag)
+	if lang == "" {
+		return "", false
+	}
+
+	if region != "" {
+		if info, ok := LookupMarket(MarketCode(lang + "-" + region)); ok {
+			return info.Code, true
+		}
+	}
+
+	if lang == "zh" && strings.EqualFold(script, "hant") {
+		return MktTaiwan, true
+	}
+
+	market, ok := defaultMarkets[lang]
+	return market, ok
+}
+
+// NegotiateMarket chooses the supported market that best matches the user's
+// locale preferences and/or an Accept-Language header
+//
+//  Notes
+//    locales (BCP 47 tags, most preferred first) take precedence over
+//    acceptLanguage. The first preference that matches a market (see
+//    MatchMarket) wins. False is returned if nothing matches.
+//
+func NegotiateMarket(acceptLanguage string, locales ...string) (MarketCode, bool) {
+	for _, locale := range locales {
+		if market, ok := MatchMarket(locale); ok {
+			return market, true
+		}
+	}
+
+	for _, languageRange := range ParseAcceptLanguage(acceptLanguage) {
+		if market, ok := MatchMarket(languageRange.Tag); ok {
+			return market, true
+		}
+	}
+
+	return "", false
+}
+
+// ConfigureMarket sets market on params and headers so the request is
+// consistent: mkt is set, the mutually exclusive cc and setLang parameters
+// are removed, Accept-Language is set to the market's language, and
+// SpellMode is used if the market does not support ProofMode
+func ConfigureMarket(params *SpellCheckParams, headers *SpellCheckHeaders, market MarketCode) {
+	params.WithMarket(market)
+	params.Values.Del(CountryCodeParam)
+	params.Values.Del(LanguageParam)
+
+	headers.WithAcceptLanguages(string(market) + "," + market.Language() + ";q=0.9")
+
+	if !market.SupportsMode(ProofMode) {
+		params.WithSpellMode()
+	}
+}
+
+// WithNegotiatedMarket negotiates a market (see NegotiateMarket) and, if one
+// matches, configures the client's Params and Headers for it (see
+// ConfigureMarket)
+func (client *Client) WithNegotiatedMarket(acceptLanguage string, locales ...string) *Client {
+	if market, ok := NegotiateMarket(acceptLanguage, locales...); ok {
+		ConfigureMarket(client.Params, client.Headers, market)
+	}
+
+	return client
+}
+
+// splitLanguageTag returns the lower case language, title case script and
+// upper case region subtags of a BCP 47 (or POSIX style) language tag
+func splitLanguageTag(tag string) (lang, script, region string) {
+	tag = strings.TrimSpace(tag)
+
+	// POSIX locales, e.g. "en_US.UTF-8"
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	subtags := strings.Split(strings.Replace(tag, "_", "-", -1), "-")
+
+	lang = strings.ToLower(subtags[0])
+	if len(lang) < 2 || len(lang) > 3 || !isAlpha(lang) {
+		return "", "", ""
+	}
+	if alias, ok := languageAliases[lang]; ok {
+		lang = alias
+	}
+
+	for _, subtag := range subtags[1:] {
+		switch {
+		case len(subtag) == 4 && isAlpha(subtag) && script == "" && region == "":
+			script = strings.ToUpper(subtag[:1]) + strings.ToLower(subtag[1:])
+		case len(subtag) == 2 && isAlpha(subtag) && region == "":
+			region = strings.ToUpper(subtag)
+		}
+	}
+
+	return lang, script, region
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
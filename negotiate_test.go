@@ -0,0 +1,141 @@
+package bingSpellCheck
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []LanguageRange
+	}{
+		{"", nil},
+		{"en-US", []LanguageRange{{"en-US", 1}}},
+		{
+			"fr;q=0.5, en-GB, de;q=0.8",
+			[]LanguageRange{{"en-GB", 1}, {"de", 0.8}, {"fr", 0.5}},
+		},
+		{
+			"da, en-GB;q=0.8, en;q=0.8",
+			[]LanguageRange{{"da", 1}, {"en-GB", 0.8}, {"en", 0.8}},
+		},
+		{
+			"ja;q=0, ko;q=high, pt-BR;q=1.5, , nl",
+			[]LanguageRange{{"nl", 1}},
+		},
+		{"*;q=0.1, it;level=1", []LanguageRange{{"it", 1}, {"*", 0.1}}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseAcceptLanguage(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAcceptLanguage(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestMatchMarket(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   MarketCode
+		wantOK bool
+	}{
+		{"en-GB", MktUnitedKingdom, true},
+		{"EN-gb", MktUnitedKingdom, true},
+		{"en_GB.UTF-8", MktUnitedKingdom, true},
+		{"fr-CA", "fr-CA", true},
+		{"en", MktUnitedStates, true},
+		{"es", MktSpain, true},
+		{"es-US", MktUnitedStatesSpanish, true},
+		{"pt", MktBrazil, true},
+		{"pt-PT", MktBrazil, true},
+		{"de-LU", MktGermany, true},
+		{"nb-NO", MktNorway, true},
+		{"nn", MktNorway, true},
+		{"zh-Hant-TW", MktTaiwan, true},
+		{"zh-Hant", MktTaiwan, true},
+		{"zh-Hans", MktChina, true},
+		{"zh-HK", MktHongKong, true},
+		{"el-GR", "", false},
+		{"*", "", false},
+		{"e", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := MatchMarket(tt.tag)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("MatchMarket(%q) = %q, %v; want %q, %v", tt.tag, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNegotiateMarket(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		locales        []string
+		want           MarketCode
+		wantOK         bool
+	}{
+		{"accept-language", "el, de-LU;q=0.9, en;q=0.8", nil, MktGermany, true},
+		{"quality order", "en;q=0.5, ja", nil, MktJapan, true},
+		{"locales first", "fr-FR", []string{"el-GR", "it_IT.UTF-8"}, MktItaly, true},
+		{"locales without a match", "fr-FR", []string{"el-GR"}, MktFrance, true},
+		{"no match", "el, he", nil, "", false},
+		{"nothing", "", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := NegotiateMarket(tt.acceptLanguage, tt.locales...)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: got %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestConfigureMarket(t *testing.T) {
+	tests := []struct {
+		market         MarketCode
+		acceptLanguage string
+		mode           string
+	}{
+		{MktSpain, "es-ES,es;q=0.9", ""},
+		{MktUnitedStatesSpanish, "es-US,es;q=0.9", SpellMode},
+		{MktUnitedKingdom, "en-GB,en;q=0.9", SpellMode},
+	}
+
+	for _, tt := range tests {
+		params := paramsOf(TextParam, "text", CountryCodeParam, "US", LanguageParam, "en")
+		headers := NewSpellCheckHeaders("key")
+
+		ConfigureMarket(params, headers, tt.market)
+
+		if got := params.Values.Get(MarketParam); got != string(tt.market) {
+			t.Errorf("%s: mkt = %q", tt.market, got)
+		}
+		if got := params.Values.Get(ModeParam); got != tt.mode {
+			t.Errorf("%s: mode = %q, want %q", tt.market, got, tt.mode)
+		}
+		if got := headers.Headers.Get(AcceptLanguageHeader); got != tt.acceptLanguage {
+			t.Errorf("%s: Accept-Language = %q, want %q", tt.market, got, tt.acceptLanguage)
+		}
+
+		// the configured request is consistent
+		if err := ValidateRequest(params, headers); err != nil {
+			t.Errorf("%s: %v", tt.market, err)
+		}
+	}
+}
+
+func TestWithNegotiatedMarket(t *testing.T) {
+	client := NewClient("key").WithNegotiatedMarket("pt-PT, en;q=0.5")
+	if got := client.Params.Values.Get(MarketParam); got != string(MktBrazil) {
+		t.Errorf("mkt = %q, want %q", got, MktBrazil)
+	}
+
+	client = NewClient("key").WithNegotiatedMarket("el")
+	if got := client.Params.Values.Get(MarketParam); got != "" {
+		t.Errorf("mkt = %q without a match", got)
+	}
+}
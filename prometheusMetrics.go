@@ -0,0 +1,253 @@
+package bingSpellCheck
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the default histogram buckets of the
+// PrometheusMetrics duration histograms, in seconds
+var DefaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// DefaultTokenBuckets are the default histogram buckets of
+// MetricFlaggedTokens
+var DefaultTokenBuckets = []float64{0, 1, 2, 5, 10, 20, 50, 100}
+
+// PrometheusContentType is the Content-Type of the Prometheus text format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusMetrics is a Metrics that keeps metrics in memory and exposes
+// them in the Prometheus text format
+//
+//  Notes
+//    PrometheusMetrics is an http.Handler, so it can be mounted directly,
+//    e.g. http.Handle("/metrics", metrics). It does not depend on the
+//    Prometheus client library.
+//
+type PrometheusMetrics struct {
+	mu         sync.Mutex
+	buckets    map[string][]float64
+	counters   map[string]map[string]float64
+	histograms map[string]map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusMetrics creates an empty PrometheusMetrics
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		buckets: map[string][]float64{
+			MetricFlaggedTokens: DefaultTokenBuckets,
+		},
+		counters:   map[string]map[string]float64{},
+		histograms: map[string]map[string]*histogram{},
+	}
+}
+
+// WithBuckets sets the upper bounds of the buckets of histogram name
+//
+//  Notes
+//    Buckets must be set before the histogram is first observed; once it
+//    has been, the call is ignored so the counts keep matching the buckets
+//
+func (pm *PrometheusMetrics) WithBuckets(name string, buckets ...float64) *PrometheusMetrics {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if _, observed := pm.histograms[name]; observed {
+		return pm
+	}
+
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	pm.buckets[name] = sorted
+	return pm
+}
+
+// Add increments counter name
+func (pm *PrometheusMetrics) Add(name string, labels Labels, delta float64) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	series, ok := pm.counters[name]
+	if !ok {
+		series = map[string]float64{}
+		pm.counters[name] = series
+	}
+
+	series[labels.key()] += delta
+}
+
+// Observe records value in histogram name
+func (pm *PrometheusMetrics) Observe(name string, labels Labels, value float64) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	series, ok := pm.histograms[name]
+	if !ok {
+		series = map[string]*histogram{}
+		pm.histograms[name] = series
+	}
+
+	buckets := pm.bucketsOf(name)
+
+	key := labels.key()
+	h, ok := series[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(buckets))}
+		series[key] = h
+	}
+
+	for i, bound := range buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+func (pm *PrometheusMetrics) bucketsOf(name string) []float64 {
+	if buckets, ok := pm.buckets[name]; ok {
+		return buckets
+	}
+
+	return DefaultLatencyBuckets
+}
+
+// WriteTo writes the metrics to w in the Prometheus text format
+func (pm *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	counterNames := make([]string, 0, len(pm.counters))
+	for name := range pm.counters {
+		counterNames = append(counterNames, name)
+	}
+	sort.Strings(counterNames)
+
+	histogramNames := make([]string, 0, len(pm.histograms))
+	for name := range pm.histograms {
+		histogramNames = append(histogramNames, name)
+	}
+	sort.Strings(histogramNames)
+
+	for _, name := range counterNames {
+		pm.writeHeader(cw, name, "counter")
+		series := pm.counters[name]
+		for _, key := range sortedCounterKeys(series) {
+			fmt.Fprintf(cw, "%s%s %s\n", name, braces(key), formatMetricValue(series[key]))
+		}
+	}
+
+	for _, name := range histogramNames {
+		pm.writeHeader(cw, name, "histogram")
+		buckets := pm.bucketsOf(name)
+		series := pm.histograms[name]
+		for _, key := range sortedHistogramKeys(series) {
+			h := series[key]
+			for i, bound := range buckets {
+				fmt.Fprintf(cw, "%s_bucket%s %d\n", name, braces(joinLabels(key, `le="`+formatMetricValue(bound)+`"`)), h.counts[i])
+			}
+			fmt.Fprintf(cw, "%s_bucket%s %d\n", name, braces(joinLabels(key, `le="+Inf"`)), h.count)
+			fmt.Fprintf(cw, "%s_sum%s %s\n", name, braces(key), formatMetricValue(h.sum))
+			fmt.Fprintf(cw, "%s_count%s %d\n", name, braces(key), h.count)
+		}
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+
+	return cw.n, cw.w.Flush()
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (pm *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", PrometheusContentType)
+	_, _ = pm.WriteTo(w)
+}
+
+func (pm *PrometheusMetrics) writeHeader(w io.Writer, name, metricType string) {
+	if help, ok := metricHelp[name]; ok {
+		fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	}
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+
+	return "{" + labels + "}"
+}
+
+func joinLabels(labels, label string) string {
+	if labels == "" {
+		return label
+	}
+
+	return labels + "," + label
+}
+
+func formatMetricValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedCounterKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedHistogramKeys(m map[string]*histogram) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// countingWriter counts the bytes written and keeps the first error
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
@@ -0,0 +1,145 @@
+package bingSpellCheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusMetricsWriteTo(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(pm *PrometheusMetrics)
+		want   string
+	}{
+		{
+			name:   "empty",
+			record: func(pm *PrometheusMetrics) {},
+			want:   "",
+		},
+		{
+			name: "counter without labels",
+			record: func(pm *PrometheusMetrics) {
+				pm.Add("test_total", nil, 1)
+				pm.Add("test_total", Labels{}, 2.5)
+			},
+			want: "# TYPE test_total counter\n" +
+				"test_total 3.5\n",
+		},
+		{
+			name: "counter series sorted with escaped labels",
+			record: func(pm *PrometheusMetrics) {
+				pm.Add(MetricRequests, Labels{TagLabel: "b", MarketLabel: "en-US"}, 1)
+				pm.Add(MetricRequests, Labels{TagLabel: "a\"\\\n", MarketLabel: "en-US"}, 2)
+			},
+			want: "# HELP bingspellcheck_requests_total Requests sent to the Bing Spell Check API.\n" +
+				"# TYPE bingspellcheck_requests_total counter\n" +
+				"bingspellcheck_requests_total{market=\"en-US\",tag=\"a\\\"\\\\\\n\"} 2\n" +
+				"bingspellcheck_requests_total{market=\"en-US\",tag=\"b\"} 1\n",
+		},
+		{
+			name: "histogram",
+			record: func(pm *PrometheusMetrics) {
+				pm.WithBuckets("test_seconds", 1, 0.5)
+				pm.Observe("test_seconds", Labels{TagLabel: "x"}, 0.25)
+				pm.Observe("test_seconds", Labels{TagLabel: "x"}, 0.75)
+				pm.Observe("test_seconds", Labels{TagLabel: "x"}, 2)
+			},
+			want: "# TYPE test_seconds histogram\n" +
+				"test_seconds_bucket{tag=\"x\",le=\"0.5\"} 1\n" +
+				"test_seconds_bucket{tag=\"x\",le=\"1\"} 2\n" +
+				"test_seconds_bucket{tag=\"x\",le=\"+Inf\"} 3\n" +
+				"test_seconds_sum{tag=\"x\"} 3\n" +
+				"test_seconds_count{tag=\"x\"} 3\n",
+		},
+		{
+			name: "buckets ignored once observed",
+			record: func(pm *PrometheusMetrics) {
+				pm.WithBuckets("test_seconds", 1)
+				pm.Observe("test_seconds", nil, 0.5)
+				pm.WithBuckets("test_seconds", 0.1, 0.2, 0.3)
+				pm.Observe("test_seconds", nil, 5)
+			},
+			want: "# TYPE test_seconds histogram\n" +
+				"test_seconds_bucket{le=\"1\"} 1\n" +
+				"test_seconds_bucket{le=\"+Inf\"} 2\n" +
+				"test_seconds_sum 5.5\n" +
+				"test_seconds_count 2\n",
+		},
+		{
+			name: "counters before histograms",
+			record: func(pm *PrometheusMetrics) {
+				pm.WithBuckets("a_seconds", 1)
+				pm.Observe("a_seconds", nil, 1)
+				pm.Add("z_total", nil, 1)
+			},
+			want: "# TYPE z_total counter\n" +
+				"z_total 1\n" +
+				"# TYPE a_seconds histogram\n" +
+				"a_seconds_bucket{le=\"1\"} 1\n" +
+				"a_seconds_bucket{le=\"+Inf\"} 1\n" +
+				"a_seconds_sum 1\n" +
+				"a_seconds_count 1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm := NewPrometheusMetrics()
+			tt.record(pm)
+
+			var buf strings.Builder
+			n, err := pm.WriteTo(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if n != int64(buf.Len()) {
+				t.Errorf("WriteTo returned %d, wrote %d bytes", n, buf.Len())
+			}
+		})
+	}
+}
+
+func TestPrometheusMetricsDefaultBuckets(t *testing.T) {
+	pm := NewPrometheusMetrics()
+	pm.Observe(MetricRequestDuration, nil, 0.3)
+	pm.Observe(MetricFlaggedTokens, nil, 3)
+
+	var buf strings.Builder
+	if _, err := pm.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		count int
+	}{
+		{MetricRequestDuration + "_bucket", len(DefaultLatencyBuckets) + 1},
+		{MetricFlaggedTokens + "_bucket", len(DefaultTokenBuckets) + 1},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Count(buf.String(), tt.name+"{"); got != tt.count {
+			t.Errorf("%s has %d buckets, want %d", tt.name, got, tt.count)
+		}
+	}
+}
+
+func TestPrometheusMetricsServeHTTP(t *testing.T) {
+	pm := NewPrometheusMetrics()
+	pm.Add("test_total", nil, 1)
+
+	rec := httptest.NewRecorder()
+	pm.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != PrometheusContentType {
+		t.Errorf("Content-Type = %q, want %q", got, PrometheusContentType)
+	}
+	if got := rec.Body.String(); got != "# TYPE test_total counter\ntest_total 1\n" {
+		t.Errorf("body = %q", got)
+	}
+}
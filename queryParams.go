@@ -1,6 +1,9 @@
 package bingSpellCheck
 
-import "net/url"
+import (
+	"net/url"
+	"unicode/utf8"
+)
 
 const (
 	// ActionTypeParam is string that's used by logging to determine whether
@@ -129,6 +132,16 @@ func NewSpellCheckParams() *SpellCheckParams {
 	return &SpellCheckParams{Values: url.Values{}}
 }
 
+// Clone returns a deep copy of the parameters
+func (scp *SpellCheckParams) Clone() *SpellCheckParams {
+	values := make(url.Values, len(scp.Values))
+	for param, v := range scp.Values {
+		values[param] = append([]string(nil), v...)
+	}
+
+	return &SpellCheckParams{Values: values}
+}
+
 // SetParam sets the value of a parameter, or if value is empty, removes it
 func (scp *SpellCheckParams) SetParam(param, value string) *SpellCheckParams {
 	if len(value) > 0 {
@@ -220,7 +233,10 @@ func (scp *SpellCheckParams) WithLangauge(lang string) *SpellCheckParams {
 	return scp.SetParam(LanguageParam, lang)
 }
 
-// TotalTextLength returns the sum of the length of all text fields
+// TotalTextLength returns the sum of the length, in characters, of all text
+// fields
 func (scp *SpellCheckParams) TotalTextLength() int {
-	return len(scp.Values[TextParam]) + len(scp.Values[PreContextTextParam]) + len(scp.Values[PostContextTextParam])
+	return utf8.RuneCountInString(scp.Values.Get(TextParam)) +
+		utf8.RuneCountInString(scp.Values.Get(PreContextTextParam)) +
+		utf8.RuneCountInString(scp.Values.Get(PostContextTextParam))
 }
@@ -0,0 +1,123 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter that allows perSecond events per
+// second on average, with bursts of up to burst events
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow reports whether an event may happen now, consuming a token if so
+func (rl *RateLimiter) Allow() bool {
+	return rl.reserve(1) == 0
+}
+
+// AllowN reports whether n events may happen now, consuming n tokens if so
+//
+//  Notes
+//    Either all n tokens are consumed or none are, so a partly allowed
+//    batch does not use up tokens. n larger than Burst is never allowed.
+//
+func (rl *RateLimiter) AllowN(n int) bool {
+	return rl.reserve(float64(n)) == 0
+}
+
+// Burst returns the most events that can happen at once
+func (rl *RateLimiter) Burst() int {
+	return int(rl.burst)
+}
+
+// Wait blocks until an event may happen or ctx is done
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := rl.reserve(1)
+		if delay == 0 {
+			return nil
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve consumes n tokens and returns 0, or returns how long to wait
+// until n tokens are available
+func (rl *RateLimiter) reserve(n float64) time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	rl.tokens += now.Sub(rl.last).Seconds() * rl.rate
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+	rl.last = now
+
+	if rl.tokens >= n {
+		rl.tokens -= n
+		return 0
+	}
+
+	if rl.rate <= 0 {
+		return time.Second
+	}
+
+	return time.Duration((n - rl.tokens) / rl.rate * float64(time.Second))
+}
+
+// RateLimitedChecker is a Checker that limits the rate of checks made using
+// another Checker
+type RateLimitedChecker struct {
+	next    Checker
+	limiter *RateLimiter
+	metrics Metrics
+}
+
+// NewRateLimitedChecker creates a RateLimitedChecker that allows perSecond
+// checks per second using next, with bursts of up to burst checks
+//
+//  Notes
+//    The Bing free tier allows 1 request per second, S1 allows 100
+//
+func NewRateLimitedChecker(next Checker, perSecond float64, burst int) *RateLimitedChecker {
+	return &RateLimitedChecker{next: next, limiter: NewRateLimiter(perSecond, burst)}
+}
+
+// Check waits until the rate limit allows a check, then checks text using
+// the wrapped Checker
+func (rlc *RateLimitedChecker) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	start := time.Now()
+	if err := rlc.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	if rlc.metrics != nil {
+		rlc.metrics.Observe(MetricRateLimiterWait, tagLabels(opts), time.Since(start).Seconds())
+	}
+
+	return rlc.next.Check(ctx, text, opts)
+}
@@ -0,0 +1,69 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllowN(t *testing.T) {
+	tests := []struct {
+		name  string
+		burst int
+		n     []int
+		want  []bool
+	}{
+		{"within burst", 5, []int{2, 3}, []bool{true, true}},
+		{"exhausted", 5, []int{4, 2, 1}, []bool{true, false, true}},
+		{"larger than burst", 5, []int{6, 5}, []bool{false, true}},
+		{"burst below 1", 0, []int{1, 1}, []bool{true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// slow enough that no tokens are added during the test
+			rl := NewRateLimiter(0.001, tt.burst)
+			for i, n := range tt.n {
+				if got := rl.AllowN(n); got != tt.want[i] {
+					t.Errorf("AllowN(%d) #%d = %v, want %v", n, i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	rl := NewRateLimiter(1000, 1)
+	if !rl.Allow() {
+		t.Fatal("first event not allowed")
+	}
+
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRateLimitedChecker(t *testing.T) {
+	stub := flagging()
+	rlc := NewRateLimitedChecker(stub, 0.001, 1)
+
+	if _, err := rlc.Check(context.Background(), "a", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := rlc.Check(ctx, "b", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("waited %s after ctx was done", elapsed)
+	}
+	if calls := stub.count(); calls != 1 {
+		t.Errorf("made %d checks, want 1", calls)
+	}
+}
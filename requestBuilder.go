@@ -0,0 +1,147 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// FormContentType is the Content-Type of POST requests
+const FormContentType = "application/x-www-form-urlencoded"
+
+// BuildRequest returns the request SpellCheck would send to the Bing Spell
+// Check API for params and headers, without sending it
+//
+//  Notes
+//    The request is a GET unless the text and context are longer than
+//    MaxGetTextLength characters, in which case it is a form encoded POST
+//
+func BuildRequest(params *SpellCheckParams, headers *SpellCheckHeaders) (*http.Request, error) {
+	return BuildRequestContext(context.Background(), GetSpellCheckURL(), params, headers)
+}
+
+// BuildRequestContext is BuildRequest with a context and target URL
+func BuildRequestContext(
+	ctx context.Context,
+	targetURL string,
+	params *SpellCheckParams,
+	headers *SpellCheckHeaders) (*http.Request, error) {
+
+	var r *http.Request
+	var err error
+
+	// if the length of the text is excessively long we need to POST, not GET
+	if params.TotalTextLength() > MaxGetTextLength {
+		r, err = http.NewRequestWithContext(ctx, http.MethodPost, targetURL, strings.NewReader(params.Values.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		// form encoded
+		r.Header.Set("Content-Type", FormContentType)
+	} else {
+		r, err = http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		r.URL.RawQuery = params.Values.Encode()
+	}
+
+	// copy (rather than assign) so the POST Content-Type is preserved
+	for header, values := range headers.Headers {
+		r.Header[header] = append([]string(nil), values...)
+	}
+
+	return r, nil
+}
+
+// DumpCurl renders r as an equivalent curl command, with the subscription
+// key redacted
+//
+//  Notes
+//    The body of r is not consumed, so r can still be sent
+//
+func DumpCurl(r *http.Request) (string, error) {
+	var b strings.Builder
+
+	b.WriteString("curl")
+	if r.Method != http.MethodGet {
+		b.WriteString(" -X " + r.Method)
+	}
+	b.WriteString(" " + shellQuote(r.URL.String()))
+
+	names := make([]string, 0, len(r.Header))
+	for name := range r.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range r.Header[name] {
+			if http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(SubscriptionKeyHeader) {
+				value = redactedValue
+			}
+			b.WriteString(" \\\n  -H " + shellQuote(name+": "+value))
+		}
+	}
+
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			return "", err
+		}
+		if len(data) > 0 {
+			b.WriteString(" \\\n  --data-raw " + shellQuote(string(data)))
+		}
+	}
+
+	return b.String(), nil
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// WithDryRun enables or disables dry run mode, in which requests are logged
+// as curl commands (see DumpCurl) to logger, or to the standard logger if
+// logger is nil, instead of being sent
+//
+//  Notes
+//    A dry run returns a SpellCheck response with no flagged tokens.
+//    Unlike LoggingChecker, the logged command includes the text.
+//
+func (client *Client) WithDryRun(enabled bool, logger *log.Logger) *Client {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	client.dryRun = enabled
+	client.dryRunLogger = logger
+	return client
+}
+
+// logDryRun logs the request that would have been sent
+func (client *Client) logDryRun(ctx context.Context, params *SpellCheckParams, headers *SpellCheckHeaders) (*SpellCheckResponse, error) {
+	r, err := BuildRequestContext(ctx, client.spellCheckURL, params, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	curl, err := DumpCurl(r)
+	if err != nil {
+		return nil, err
+	}
+
+	client.dryRunLogger.Printf("spell check dry run:\n%s", curl)
+
+	return &SpellCheckResponse{Type: SpellCheckResponseType, FlaggedTokens: []FlaggedToken{}}, nil
+}
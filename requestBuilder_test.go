@@ -0,0 +1,198 @@
+package bingSpellCheck
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBuildRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *SpellCheckParams
+		method string
+	}{
+		{"short text", paramsOf(TextParam, "Is teh data good?", MarketParam, "en-US"), http.MethodGet},
+		{"at the limit", paramsOf(TextParam, strings.Repeat("a", MaxGetTextLength)), http.MethodGet},
+		{"at the limit in characters", paramsOf(TextParam, strings.Repeat("é", MaxGetTextLength)), http.MethodGet},
+		{"long text", paramsOf(TextParam, strings.Repeat("a", MaxGetTextLength+1)), http.MethodPost},
+		{"long with context", paramsOf(TextParam, strings.Repeat("a", MaxGetTextLength), PreContextTextParam, "b"), http.MethodPost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := NewSpellCheckHeaders("key").WithAcceptLanguages("en-US")
+
+			r, err := BuildRequest(tt.params, headers)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if r.Method != tt.method {
+				t.Errorf("method = %s, want %s", r.Method, tt.method)
+			}
+			if r.URL.Host+r.URL.Path != strings.TrimPrefix(GetSpellCheckURL(), r.URL.Scheme+"://") {
+				t.Errorf("URL = %s", r.URL)
+			}
+			if got := r.Header.Get(SubscriptionKeyHeader); got != "key" {
+				t.Errorf("%s = %q", SubscriptionKeyHeader, got)
+			}
+			if got := r.Header.Get(AcceptLanguageHeader); got != "en-US" {
+				t.Errorf("%s = %q", AcceptLanguageHeader, got)
+			}
+
+			var encoded string
+			if r.Method == http.MethodPost {
+				if got := r.Header.Get("Content-Type"); got != FormContentType {
+					t.Errorf("Content-Type = %q", got)
+				}
+				if r.URL.RawQuery != "" {
+					t.Errorf("POST query = %q", r.URL.RawQuery)
+				}
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				encoded = string(body)
+			} else {
+				if r.Body != nil {
+					t.Error("GET has a body")
+				}
+				encoded = r.URL.RawQuery
+			}
+
+			if encoded != tt.params.Values.Encode() {
+				t.Errorf("sent %q, want %q", encoded, tt.params.Values.Encode())
+			}
+		})
+	}
+}
+
+func TestBuildRequestCopiesHeaders(t *testing.T) {
+	headers := NewSpellCheckHeaders("key")
+
+	r, err := BuildRequest(paramsOf(TextParam, strings.Repeat("a", MaxGetTextLength+1)), headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Header.Add(SubscriptionKeyHeader, "other")
+	if got := headers.Headers.Values(SubscriptionKeyHeader); len(got) != 1 {
+		t.Errorf("request headers share the headers: %q", got)
+	}
+	if headers.Headers.Get("Content-Type") != "" {
+		t.Error("the POST Content-Type was added to the headers")
+	}
+}
+
+func TestDumpCurl(t *testing.T) {
+	url := GetSpellCheckURL()
+
+	tests := []struct {
+		name   string
+		params *SpellCheckParams
+		want   string
+	}{
+		{
+			name:   "get",
+			params: paramsOf(TextParam, "it's"),
+			want: "curl '" + url + "?text=it%27s' \\\n" +
+				"  -H 'Accept: " + AcceptApplicationJSON + "' \\\n" +
+				"  -H 'Ocp-Apim-Subscription-Key: " + redactedValue + "'",
+		},
+		{
+			name:   "post",
+			params: paramsOf(TextParam, "'"+strings.Repeat("a", MaxGetTextLength)),
+			want: "curl -X POST '" + url + "' \\\n" +
+				"  -H 'Accept: " + AcceptApplicationJSON + "' \\\n" +
+				"  -H 'Content-Type: " + FormContentType + "' \\\n" +
+				"  -H 'Ocp-Apim-Subscription-Key: " + redactedValue + "' \\\n" +
+				"  --data-raw 'text=%27" + strings.Repeat("a", MaxGetTextLength) + "'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := BuildRequest(tt.params, NewSpellCheckHeaders("secret"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := DumpCurl(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+			if strings.Contains(got, "secret") {
+				t.Error("the subscription key is not redacted")
+			}
+		})
+	}
+}
+
+func TestDumpCurlKeepsBody(t *testing.T) {
+	params := paramsOf(TextParam, strings.Repeat("a", MaxGetTextLength+1))
+
+	r, err := BuildRequest(params, NewSpellCheckHeaders("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DumpCurl(r); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != params.Values.Encode() {
+		t.Errorf("body after DumpCurl = %q", body)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"", `''`},
+		{"text", `'text'`},
+		{"it's", `'it'\''s'`},
+		{"$HOME `x`", "'$HOME `x`'"},
+	}
+
+	for _, tt := range tests {
+		if got := shellQuote(tt.s); got != tt.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestClientDryRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("a dry run sent a request")
+	}))
+	defer srv.Close()
+
+	var logged bytes.Buffer
+	client := NewClient("secret").WithEndpoint(srv.URL).WithDryRun(true, log.New(&logged, "", 0))
+
+	scr, err := client.Check(context.Background(), "Is teh data good?", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scr.Type != SpellCheckResponseType || len(scr.FlaggedTokens) != 0 {
+		t.Errorf("got %+v", scr)
+	}
+
+	if got := logged.String(); !strings.Contains(got, "curl '"+srv.URL) || !strings.Contains(got, "teh") || strings.Contains(got, "secret") {
+		t.Errorf("logged %q", got)
+	}
+}
@@ -66,6 +66,11 @@ func NewSpellCheckHeaders(subscriptionKey string) *SpellCheckHeaders {
 	return (&SpellCheckHeaders{Headers: http.Header{}}).WithSubscriptionKey(subscriptionKey).WithJSON()
 }
 
+// Clone returns a deep copy of the headers
+func (sch *SpellCheckHeaders) Clone() *SpellCheckHeaders {
+	return &SpellCheckHeaders{Headers: sch.Headers.Clone()}
+}
+
 // SetHeader sets the value of a header, or removes it if value is empty
 func (sch *SpellCheckHeaders) SetHeader(header, value string) *SpellCheckHeaders {
 	if len(value) > 0 {
@@ -83,6 +88,12 @@ func (sch *SpellCheckHeaders) WithJSON() *SpellCheckHeaders {
 }
 
 // WithLinkedJSON sets the accept header to 'application/ld+json'
+//
+//  Notes
+//    JSON-LD responses are normalized, so SpellCheckResponse is used the
+//    same way with either format. See SpellCheckResponse.Linked for the
+//    JSON-LD form.
+//
 func (sch *SpellCheckHeaders) WithLinkedJSON() *SpellCheckHeaders {
 	return sch.SetHeader(AcceptHeader, AcceptApplicationLinkedJSON)
 }
@@ -147,7 +158,8 @@ func (sch *SpellCheckHeaders) WithAcceptLanguages(languages string) *SpellCheckH
 // for values and expected format)
 //
 //  Notes
-//    Passing in an empty string effectively removes the Header
+//    Passing in an empty string effectively removes the Header. See
+//    WithLocation to set the header from a typed SearchLocation
 //
 func (sch *SpellCheckHeaders) WithSearchLocation(location string) *SpellCheckHeaders {
 	return sch.SetHeader(SearchLocationHeader, location)
@@ -0,0 +1,119 @@
+package bingSpellCheck
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// TraceIDHeader is the response header that contains the ID of the log
+	// entry that contains the details of the request. Include it when
+	// contacting support.
+	TraceIDHeader = "BingAPIs-TraceId"
+
+	// MarketHeader is the response header that contains the market used by
+	// the request
+	MarketHeader = "BingAPIs-Market"
+
+	// RetryAfterHeader is the response header that contains the number of
+	// seconds to wait before sending another request, when rate limited
+	RetryAfterHeader = "Retry-After"
+
+	// RateLimitLimitHeader is the response header that contains the number
+	// of calls allowed in the current period, when provided by a gateway
+	RateLimitLimitHeader = "X-RateLimit-Limit"
+
+	// RateLimitRemainingHeader is the response header that contains the
+	// number of calls remaining in the current period, when provided by a
+	// gateway
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+
+	// RateLimitResetHeader is the response header that contains when the
+	// current period ends, as a UNIX timestamp, when provided by a gateway
+	RateLimitResetHeader = "X-RateLimit-Reset"
+)
+
+// RateLimit is the rate limit information returned with a response
+//
+//  Fields
+//    Limit      - Calls allowed in the current period, or -1 if unknown
+//    Remaining  - Calls remaining in the current period, or -1 if unknown
+//    Reset      - When the current period ends (zero if unknown)
+//    RetryAfter - How long to wait before retrying, when rate limited (zero
+//      if not given)
+//
+type RateLimit struct {
+	Limit      int
+	Remaining  int
+	Reset      time.Time
+	RetryAfter time.Duration
+}
+
+// ResponseMeta is information about the HTTP response to a request
+//
+//  Fields
+//    StatusCode - The HTTP status code
+//    Latency    - The time from sending the request to reading the response
+//    TraceID    - The BingAPIs-TraceId header (include it in support
+//      requests)
+//    Market     - The market used (BingAPIs-Market header)
+//    ClientID   - The X-MSEdge-ClientID header, which should be sent with
+//      the user's subsequent requests (see WithClientIDStore)
+//    RateLimit  - Rate limit information
+//    Header     - All of the response headers
+//
+type ResponseMeta struct {
+	StatusCode int
+	Latency    time.Duration
+	TraceID    string
+	Market     string
+	ClientID   string
+	RateLimit  RateLimit
+	Header     http.Header
+}
+
+// newResponseMeta creates a ResponseMeta from resp
+func newResponseMeta(resp *http.Response, latency time.Duration) *ResponseMeta {
+	return &ResponseMeta{
+		StatusCode: resp.StatusCode,
+		Latency:    latency,
+		TraceID:    resp.Header.Get(TraceIDHeader),
+		Market:     resp.Header.Get(MarketHeader),
+		ClientID:   resp.Header.Get(ClientIDHeader),
+		RateLimit:  parseRateLimit(resp.Header),
+		Header:     resp.Header,
+	}
+}
+
+// Clone returns a deep copy of the ResponseMeta
+func (meta *ResponseMeta) Clone() *ResponseMeta {
+	clone := *meta
+	clone.Header = meta.Header.Clone()
+	return &clone
+}
+
+func parseRateLimit(header http.Header) RateLimit {
+	rateLimit := RateLimit{Limit: -1, Remaining: -1}
+
+	if limit, err := strconv.Atoi(header.Get(RateLimitLimitHeader)); err == nil {
+		rateLimit.Limit = limit
+	}
+	if remaining, err := strconv.Atoi(header.Get(RateLimitRemainingHeader)); err == nil {
+		rateLimit.Remaining = remaining
+	}
+	if reset, err := strconv.ParseInt(header.Get(RateLimitResetHeader), 10, 64); err == nil {
+		rateLimit.Reset = time.Unix(reset, 0)
+	}
+
+	// Retry-After is either a number of seconds or an HTTP date
+	if retryAfter := header.Get(RetryAfterHeader); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			rateLimit.RetryAfter = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(retryAfter); err == nil {
+			rateLimit.RetryAfter = time.Until(date)
+		}
+	}
+
+	return rateLimit
+}
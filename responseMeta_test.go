@@ -0,0 +1,102 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// header returns a header with the given name and value pairs
+func header(pairs ...string) http.Header {
+	h := http.Header{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		h.Set(pairs[i], pairs[i+1])
+	}
+	return h
+}
+
+func TestParseRateLimit(t *testing.T) {
+	reset := time.Unix(1800000000, 0)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   RateLimit
+	}{
+		{
+			name:   "none",
+			header: http.Header{},
+			want:   RateLimit{Limit: -1, Remaining: -1},
+		},
+		{
+			name: "gateway headers",
+			header: header(
+				RateLimitLimitHeader, "100",
+				RateLimitRemainingHeader, "7",
+				RateLimitResetHeader, "1800000000",
+			),
+			want: RateLimit{Limit: 100, Remaining: 7, Reset: reset},
+		},
+		{
+			name:   "retry after seconds",
+			header: header(RetryAfterHeader, "30"),
+			want:   RateLimit{Limit: -1, Remaining: -1, RetryAfter: 30 * time.Second},
+		},
+		{
+			name:   "invalid values",
+			header: header(RateLimitLimitHeader, "many", RetryAfterHeader, "soon"),
+			want:   RateLimit{Limit: -1, Remaining: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRateLimit(tt.header); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRateLimitRetryAfterDate(t *testing.T) {
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+
+	got := parseRateLimit(header(RetryAfterHeader, date)).RetryAfter
+	if got <= 0 || got > time.Minute {
+		t.Errorf("RetryAfter = %s, want up to a minute", got)
+	}
+}
+
+func TestResponseMeta(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(TraceIDHeader, "trace")
+		w.Header().Set(MarketHeader, "en-US")
+		w.Header().Set(ClientIDHeader, "client-id")
+		w.Header().Set(RateLimitRemainingHeader, "9")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"_type":"SpellCheck","flaggedTokens":[]}`))
+	}))
+	defer srv.Close()
+
+	scr, err := NewClient("key").WithEndpoint(srv.URL).Check(context.Background(), "text", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta := scr.Meta
+	if meta == nil {
+		t.Fatal("no Meta")
+	}
+	if meta.StatusCode != http.StatusOK || meta.TraceID != "trace" || meta.Market != "en-US" ||
+		meta.ClientID != "client-id" || meta.RateLimit.Remaining != 9 || meta.Latency <= 0 {
+		t.Errorf("got %+v", meta)
+	}
+
+	clone := meta.Clone()
+	clone.Header.Set(TraceIDHeader, "changed")
+	if meta.Header.Get(TraceIDHeader) != "trace" {
+		t.Error("changing the clone's Header changed the original")
+	}
+}
@@ -1,6 +1,9 @@
 package bingSpellCheck
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+)
 
 const (
 	// ErrorResponseType is used as a value for SpellCheckResponse.Type and
@@ -16,6 +19,45 @@ const (
 	UnknownTokenType = "UnknownToken"
 )
 
+const (
+	// ServerErrorCode indicates an unexpected server side error (HTTP 500)
+	ServerErrorCode = "ServerError"
+	// InvalidRequestErrorCode indicates the request was malformed (HTTP 400)
+	InvalidRequestErrorCode = "InvalidRequest"
+	// RateLimitExceededErrorCode indicates the queries per second or monthly
+	// quota was exceeded (HTTP 429)
+	RateLimitExceededErrorCode = "RateLimitExceeded"
+	// InvalidAuthorizationErrorCode indicates the subscription key is missing
+	// or invalid (HTTP 401)
+	InvalidAuthorizationErrorCode = "InvalidAuthorization"
+	// InsufficientAuthorizationErrorCode indicates the subscription key does
+	// not have permission to access the resource (HTTP 403)
+	InsufficientAuthorizationErrorCode = "InsufficientAuthorization"
+
+	// UnexpectedErrorSubCode is a SubCode of ServerErrorCode
+	UnexpectedErrorSubCode = "UnexpectedError"
+	// ResourceErrorSubCode is a SubCode of ServerErrorCode
+	ResourceErrorSubCode = "ResourceError"
+	// NotImplementedSubCode is a SubCode of ServerErrorCode
+	NotImplementedSubCode = "NotImplemented"
+	// ParameterMissingSubCode is a SubCode of InvalidRequestErrorCode
+	ParameterMissingSubCode = "ParameterMissing"
+	// ParameterInvalidValueSubCode is a SubCode of InvalidRequestErrorCode
+	ParameterInvalidValueSubCode = "ParameterInvalidValue"
+	// HTTPNotAllowedSubCode is a SubCode of InvalidRequestErrorCode
+	HTTPNotAllowedSubCode = "HttpNotAllowed"
+	// BlockedSubCode is a SubCode of InvalidRequestErrorCode
+	BlockedSubCode = "Blocked"
+	// AuthorizationMissingSubCode is a SubCode of InvalidAuthorizationErrorCode
+	AuthorizationMissingSubCode = "AuthorizationMissing"
+	// AuthorizationRedundancySubCode is a SubCode of InvalidAuthorizationErrorCode
+	AuthorizationRedundancySubCode = "AuthorizationRedundancy"
+	// AuthorizationDisabledSubCode is a SubCode of InsufficientAuthorizationErrorCode
+	AuthorizationDisabledSubCode = "AuthorizationDisabled"
+	// AuthorizationExpiredSubCode is a SubCode of InsufficientAuthorizationErrorCode
+	AuthorizationExpiredSubCode = "AuthorizationExpired"
+)
+
 // SpellCheckResponse is the main data return from spell check API calls
 //
 //  Fields
@@ -24,6 +66,10 @@ const (
 //      spelled correctly or are grammatically incorrect.
 //    Errors        - A list of errors that describe the reasons why the
 //      request failed
+//    Meta          - Information about the HTTP response (not part of the
+//      JSON response, and nil if the response did not come from Bing)
+//    Linked        - The JSON-LD response this response was normalized from,
+//      or nil if the response was plain JSON (see WithLinkedJSON)
 //
 //  Notes
 //    If no spelling or grammar errors were found, or the specified market is
@@ -36,6 +82,9 @@ type SpellCheckResponse struct {
 	Type          string         `json:"_type"`
 	FlaggedTokens []FlaggedToken `json:"flaggedTokens"`
 	Errors        []Error        `json:"errors"`
+	Meta          *ResponseMeta  `json:"-"`
+
+	Linked *LinkedSpellCheckResponse `json:"-"`
 }
 
 // IsErrorResponse determines if the SpellCheckResponse indicates an error
@@ -48,6 +97,59 @@ func (scr *SpellCheckResponse) IsSpellCheckResponse() bool {
 	return scr.Type == SpellCheckResponseType
 }
 
+// IsUnavailable determines if the SpellCheckResponse is an error that
+// indicates the service cannot currently be used (see Error.IsUnavailable)
+func (scr *SpellCheckResponse) IsUnavailable() bool {
+	if !scr.IsErrorResponse() {
+		return false
+	}
+
+	for _, err := range scr.Errors {
+		if err.IsUnavailable() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsKeyRejected determines if the SpellCheckResponse is an error that
+// indicates the subscription key cannot currently be used, i.e. it is not
+// authorized, or its rate limit or quota has been exceeded
+func (scr *SpellCheckResponse) IsKeyRejected() bool {
+	if !scr.IsErrorResponse() {
+		return false
+	}
+
+	for _, err := range scr.Errors {
+		if err.IsRateLimited() || err.IsAuthorizationError() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Clone returns a deep copy of the SpellCheckResponse
+func (scr *SpellCheckResponse) Clone() *SpellCheckResponse {
+	clone := *scr
+
+	if scr.FlaggedTokens != nil {
+		clone.FlaggedTokens = make([]FlaggedToken, len(scr.FlaggedTokens))
+		for i, token := range scr.FlaggedTokens {
+			token.Suggestions = append([]TokenSuggestion(nil), token.Suggestions...)
+			clone.FlaggedTokens[i] = token
+		}
+	}
+	clone.Errors = append([]Error(nil), scr.Errors...)
+
+	if scr.Meta != nil {
+		clone.Meta = scr.Meta.Clone()
+	}
+
+	return &clone
+}
+
 // HasSuggestions determines if the SpellCheckResponse returned suggestions
 func (scr *SpellCheckResponse) HasSuggestions() bool {
 	return len(scr.FlaggedTokens) > 0
@@ -118,7 +220,9 @@ func (token FlaggedToken) IsUnknownToken() bool {
 //    Value       - The query parameter's value that was not valid
 //
 //  Notes
-//    Code is an HTTP status code
+//    Code is one of the Bing error codes (e.g. InvalidRequestErrorCode), or
+//    an HTTP status code when the error did not come from Bing itself (e.g.
+//    "429" when the API gateway rate limits the subscription)
 //
 type Error struct {
 	Code        string `json:"code"`
@@ -132,3 +236,47 @@ type Error struct {
 func (err Error) Error() string {
 	return fmt.Sprintf("%s: %s. Parameter=%s", err.Code, err.Message, err.Parameter)
 }
+
+// IsRateLimited determines if the error is due to the rate limit or the call
+// volume quota being exceeded
+//
+//  Notes
+//    A 403 (InsufficientAuthorization) means the key is disabled or expired,
+//    which waiting will not fix, so it is an authorization error instead
+//
+func (err Error) IsRateLimited() bool {
+	switch err.Code {
+	case RateLimitExceededErrorCode, "429":
+		return true
+	}
+
+	return false
+}
+
+// IsAuthorizationError determines if the error is due to a missing, invalid,
+// disabled or expired subscription key
+func (err Error) IsAuthorizationError() bool {
+	switch err.Code {
+	case InvalidAuthorizationErrorCode, InsufficientAuthorizationErrorCode, "401", "403":
+		return true
+	}
+
+	return false
+}
+
+// IsServerError determines if the error is a server side error
+func (err Error) IsServerError() bool {
+	if err.Code == ServerErrorCode {
+		return true
+	}
+
+	status, convErr := strconv.Atoi(err.Code)
+	return convErr == nil && status >= 500
+}
+
+// IsUnavailable determines if the error indicates the service cannot
+// currently be used, i.e. it is a server error, or the rate limit or quota has
+// been exceeded
+func (err Error) IsUnavailable() bool {
+	return err.IsRateLimited() || err.IsServerError()
+}
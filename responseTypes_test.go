@@ -0,0 +1,49 @@
+package bingSpellCheck
+
+import "testing"
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		code          string
+		rateLimited   bool
+		authorization bool
+		server        bool
+	}{
+		{RateLimitExceededErrorCode, true, false, false},
+		{"429", true, false, false},
+		{InvalidAuthorizationErrorCode, false, true, false},
+		{"401", false, true, false},
+		{InsufficientAuthorizationErrorCode, false, true, false},
+		{"403", false, true, false},
+		{ServerErrorCode, false, false, true},
+		{"500", false, false, true},
+		{"503", false, false, true},
+		{InvalidRequestErrorCode, false, false, false},
+		{"404", false, false, false},
+	}
+
+	for _, tt := range tests {
+		err := Error{Code: tt.code}
+		if got := err.IsRateLimited(); got != tt.rateLimited {
+			t.Errorf("%s: IsRateLimited = %v, want %v", tt.code, got, tt.rateLimited)
+		}
+		if got := err.IsAuthorizationError(); got != tt.authorization {
+			t.Errorf("%s: IsAuthorizationError = %v, want %v", tt.code, got, tt.authorization)
+		}
+		if got := err.IsServerError(); got != tt.server {
+			t.Errorf("%s: IsServerError = %v, want %v", tt.code, got, tt.server)
+		}
+		if got := err.IsUnavailable(); got != (tt.rateLimited || tt.server) {
+			t.Errorf("%s: IsUnavailable = %v, want %v", tt.code, got, tt.rateLimited || tt.server)
+		}
+
+		scr := errorResponse(tt.code)
+		if got := scr.IsKeyRejected(); got != (tt.rateLimited || tt.authorization) {
+			t.Errorf("%s: IsKeyRejected = %v, want %v", tt.code, got, tt.rateLimited || tt.authorization)
+		}
+	}
+
+	if scr := spellCheckResponse(); scr.IsUnavailable() || scr.IsKeyRejected() {
+		t.Error("a successful response is unavailable or rejected")
+	}
+}
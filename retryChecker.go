@@ -0,0 +1,130 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/url"
+	"time"
+)
+
+// RetryChecker is a Checker that retries another Checker when a request
+// cannot be sent or Bing is unavailable (see SpellCheckResponse.IsUnavailable)
+type RetryChecker struct {
+	next     Checker
+	attempts int
+	backoff  time.Duration
+	metrics  Metrics
+	tracer   Tracer
+}
+
+// NewRetryChecker creates a RetryChecker that makes up to attempts checks
+// using next
+//
+//  Notes
+//    The delay before retry n is backoff * 2^(n-1), plus up to 50% jitter
+//
+func NewRetryChecker(next Checker, attempts int, backoff time.Duration) *RetryChecker {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return &RetryChecker{next: next, attempts: attempts, backoff: backoff}
+}
+
+// Check checks text using the wrapped Checker, retrying as needed
+//
+//  Notes
+//    Only transport errors and unavailable responses (5xx, 429 and quota
+//    errors) are retried; any other error or response is returned at once.
+//    The result of the last attempt is returned when all attempts fail
+//
+func (rc *RetryChecker) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	var scr *SpellCheckResponse
+	var err error
+
+	for attempt := 0; attempt < rc.attempts; attempt++ {
+		if attempt > 0 {
+			if rc.metrics != nil {
+				rc.metrics.Add(MetricRetries, tagLabels(opts), 1)
+			}
+			if waitErr := sleep(ctx, rc.delay(attempt)); waitErr != nil {
+				return scr, err
+			}
+		}
+
+		attemptCtx, span := startSpan(ctx, rc.tracer, SpanAttempt, Attribute{AttemptAttribute, attempt + 1})
+		scr, err = rc.next.Check(attemptCtx, text, opts)
+		endSpan(span, scr, err)
+
+		if err == nil && !scr.IsUnavailable() {
+			return scr, nil
+		}
+		if ctx.Err() != nil || (err != nil && !isRetryable(err)) {
+			break
+		}
+	}
+
+	return scr, err
+}
+
+// isRetryable reports whether err is a transport error that another attempt
+// may not have
+//
+//  Notes
+//    Validation, budget, key pool and context errors (and errors building the
+//    request) would fail again, and errors from other Checkers are unknown,
+//    so none of them are retried
+//
+func isRetryable(err error) bool {
+	var validationErrors ValidationErrors
+	var validationError *ValidationError
+	var budgetErr *BudgetExceededError
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.As(err, &validationErrors), errors.As(err, &validationError), errors.As(err, &budgetErr):
+		return false
+	case errors.Is(err, ErrNoKeysAvailable):
+		return false
+	}
+
+	// http.Client.Do reports transport errors as *url.Error, as does
+	// url.Parse for a bad endpoint
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Op != "parse"
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func (rc *RetryChecker) delay(attempt int) time.Duration {
+	delay := rc.backoff << uint(attempt-1)
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
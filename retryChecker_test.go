@@ -0,0 +1,89 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"transport error", &url.Error{Op: "Post", URL: "https://example.com", Err: errors.New("connection reset")}, true},
+		{"bad endpoint", &url.Error{Op: "parse", URL: ":", Err: errors.New("missing protocol scheme")}, false},
+		{"net error", &net.OpError{Op: "dial", Err: errors.New("refused")}, true},
+		{"unexpected EOF", fmt.Errorf("reading response: %w", io.ErrUnexpectedEOF), true},
+		{"canceled", context.Canceled, false},
+		{"canceled transport error", &url.Error{Op: "Post", Err: context.Canceled}, false},
+		{"deadline", fmt.Errorf("check: %w", context.DeadlineExceeded), false},
+		{"validation error", &ValidationError{}, false},
+		{"validation errors", ValidationErrors{&ValidationError{}}, false},
+		{"budget exceeded", &BudgetExceededError{}, false},
+		{"no keys", ErrNoKeysAvailable, false},
+		{"unknown error", errors.New("something else"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: isRetryable(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetryChecker(t *testing.T) {
+	transportErr := &url.Error{Op: "Post", URL: "https://example.com", Err: errors.New("connection reset")}
+
+	tests := []struct {
+		name      string
+		stub      *stubChecker
+		wantCalls int
+		wantErr   bool
+		wantType  string
+	}{
+		{"success", flagging(), 1, false, SpellCheckResponseType},
+		{"unavailable then success", responding(errorResponse(ServerErrorCode), errorResponse("429"), spellCheckResponse()), 3, false, SpellCheckResponseType},
+		{"unavailable every time", responding(errorResponse("503")), 3, false, ErrorResponseType},
+		{"invalid request", responding(errorResponse(InvalidRequestErrorCode)), 1, false, ErrorResponseType},
+		{"transport error", failing(transportErr), 3, true, ""},
+		{"validation error", failing(ValidationErrors{&ValidationError{}}), 1, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scr, err := NewRetryChecker(tt.stub, 3, 0).Check(context.Background(), "text", nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && scr.Type != tt.wantType {
+				t.Errorf("got a %s, want a %s", scr.Type, tt.wantType)
+			}
+			if calls := tt.stub.count(); calls != tt.wantCalls {
+				t.Errorf("made %d checks, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryCheckerCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stub := &stubChecker{respond: func(text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+		cancel()
+		return errorResponse(ServerErrorCode), nil
+	}}
+
+	scr, err := NewRetryChecker(stub, 5, time.Hour).Check(ctx, "text", nil)
+	if err != nil || !scr.IsUnavailable() {
+		t.Errorf("got %+v, %v; want the unavailable response", scr, err)
+	}
+	if calls := stub.count(); calls != 1 {
+		t.Errorf("made %d checks after ctx was canceled, want 1", calls)
+	}
+}
@@ -0,0 +1,364 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keys of the X-Search-Location header
+const (
+	LatitudeLocationKey         = "lat"
+	LongitudeLocationKey        = "long"
+	RadiusLocationKey           = "re"
+	TimestampLocationKey        = "ts"
+	HeadingLocationKey          = "head"
+	SpeedLocationKey            = "sp"
+	AltitudeLocationKey         = "alt"
+	AltitudeAccuracyLocationKey = "are"
+	DisplayLocationKey          = "disp"
+)
+
+// SearchLocation is the client's geographical location, sent in the
+// X-Search-Location header
+//
+//  Fields
+//    Latitude         - Degrees, from -90 to 90 (required)
+//    Longitude        - Degrees, from -180 to 180 (required)
+//    Radius           - The horizontal accuracy of the coordinates, in meters
+//      (required)
+//    Timestamp        - When the client was at the location (optional)
+//    Heading          - The direction of travel, in degrees clockwise from
+//      true north, from 0 to 360 (optional, only sent if Speed is not zero)
+//    Speed            - Horizontal velocity, in meters per second (optional)
+//    Altitude         - Altitude, in meters (optional)
+//    AltitudeAccuracy - The vertical accuracy of Altitude, in meters
+//      (optional, only sent with Altitude)
+//    Display          - The user's location for display, e.g.
+//      "Seattle, WA" (optional)
+//
+//  Notes
+//    For more information see:
+//      https://docs.microsoft.com/en-us/rest/api/cognitiveservices/bing-spell-check-api-v7-reference#request-headers
+//
+type SearchLocation struct {
+	Latitude         float64
+	Longitude        float64
+	Radius           float64
+	Timestamp        time.Time
+	Heading          *float64
+	Speed            *float64
+	Altitude         *float64
+	AltitudeAccuracy *float64
+	Display          string
+}
+
+// Validate checks the location for values out of range, and returns
+// ValidationErrors listing all of them
+func (loc *SearchLocation) Validate() error {
+	var errs ValidationErrors
+	loc.validate(&errs)
+	return errs.err()
+}
+
+func (loc *SearchLocation) validate(errs *ValidationErrors) {
+	check := func(key string, value float64, ok bool, format string, args ...interface{}) {
+		if !ok || math.IsNaN(value) || math.IsInf(value, 0) {
+			errs.add(SearchLocationHeader, key+":"+formatLocationFloat(value), format, args...)
+		}
+	}
+
+	check(LatitudeLocationKey, loc.Latitude, loc.Latitude >= -90 && loc.Latitude <= 90,
+		"%s must be between -90 and 90", LatitudeLocationKey)
+	check(LongitudeLocationKey, loc.Longitude, loc.Longitude >= -180 && loc.Longitude <= 180,
+		"%s must be between -180 and 180", LongitudeLocationKey)
+	check(RadiusLocationKey, loc.Radius, loc.Radius > 0,
+		"%s is required and must be greater than 0", RadiusLocationKey)
+
+	if loc.Heading != nil {
+		check(HeadingLocationKey, *loc.Heading, *loc.Heading >= 0 && *loc.Heading <= 360,
+			"%s must be between 0 and 360", HeadingLocationKey)
+	}
+	if loc.Speed != nil {
+		check(SpeedLocationKey, *loc.Speed, *loc.Speed >= 0,
+			"%s must not be negative", SpeedLocationKey)
+	}
+	if loc.Altitude != nil {
+		check(AltitudeLocationKey, *loc.Altitude, true, "%s must be a number", AltitudeLocationKey)
+	}
+	if loc.AltitudeAccuracy != nil {
+		check(AltitudeAccuracyLocationKey, *loc.AltitudeAccuracy, *loc.AltitudeAccuracy >= 0,
+			"%s must not be negative", AltitudeAccuracyLocationKey)
+	}
+
+	if strings.ContainsAny(loc.Display, ";\r\n") {
+		errs.add(SearchLocationHeader, DisplayLocationKey+":"+loc.Display, "%s must not contain ';' or line breaks", DisplayLocationKey)
+	}
+}
+
+// String returns the location in the semicolon-delimited X-Search-Location
+// format, e.g. "lat:47.6421;long:-122.1420;re:100"
+func (loc *SearchLocation) String() string {
+	pairs := []string{
+		LatitudeLocationKey + ":" + formatLocationFloat(loc.Latitude),
+		LongitudeLocationKey + ":" + formatLocationFloat(loc.Longitude),
+		RadiusLocationKey + ":" + formatLocationFloat(loc.Radius),
+	}
+
+	if !loc.Timestamp.IsZero() {
+		pairs = append(pairs, TimestampLocationKey+":"+strconv.FormatInt(loc.Timestamp.Unix(), 10))
+	}
+	if loc.Heading != nil && loc.Speed != nil && *loc.Speed != 0 {
+		pairs = append(pairs, HeadingLocationKey+":"+formatLocationFloat(*loc.Heading))
+	}
+	if loc.Speed != nil {
+		pairs = append(pairs, SpeedLocationKey+":"+formatLocationFloat(*loc.Speed))
+	}
+	if loc.Altitude != nil {
+		pairs = append(pairs, AltitudeLocationKey+":"+formatLocationFloat(*loc.Altitude))
+		if loc.AltitudeAccuracy != nil {
+			pairs = append(pairs, AltitudeAccuracyLocationKey+":"+formatLocationFloat(*loc.AltitudeAccuracy))
+		}
+	}
+	if loc.Display != "" {
+		pairs = append(pairs, DisplayLocationKey+":"+loc.Display)
+	}
+
+	return strings.Join(pairs, ";")
+}
+
+// ParseSearchLocation parses an X-Search-Location header value
+//
+//  Notes
+//    Keys are case insensitive. Unknown keys, malformed values, and a
+//    missing lat, long or re are errors (ValidationErrors), as are values
+//    out of range
+//
+func ParseSearchLocation(header string) (*SearchLocation, error) {
+	var errs ValidationErrors
+	loc := &SearchLocation{}
+	seen := map[string]bool{}
+
+	required := map[string]*float64{
+		LatitudeLocationKey:  &loc.Latitude,
+		LongitudeLocationKey: &loc.Longitude,
+		RadiusLocationKey:    &loc.Radius,
+	}
+	optional := map[string]**float64{
+		HeadingLocationKey:          &loc.Heading,
+		SpeedLocationKey:            &loc.Speed,
+		AltitudeLocationKey:         &loc.Altitude,
+		AltitudeAccuracyLocationKey: &loc.AltitudeAccuracy,
+	}
+
+	for _, pair := range strings.Split(header, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			errs.add(SearchLocationHeader, pair, "must be of the form <key>:<value>")
+			continue
+		}
+
+		key, value := strings.ToLower(strings.TrimSpace(parts[0])), strings.TrimSpace(parts[1])
+		if seen[key] {
+			errs.add(SearchLocationHeader, pair, "%s is specified more than once", key)
+			continue
+		}
+		seen[key] = true
+
+		if key == DisplayLocationKey {
+			loc.Display = value
+			continue
+		}
+
+		if key == TimestampLocationKey {
+			ts, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				errs.add(SearchLocationHeader, pair, "%s must be a UNIX timestamp", key)
+				continue
+			}
+			loc.Timestamp = time.Unix(ts, 0).UTC()
+			continue
+		}
+
+		if required[key] == nil && optional[key] == nil {
+			errs.add(SearchLocationHeader, pair, "%q is not a known key", key)
+			continue
+		}
+
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			errs.add(SearchLocationHeader, pair, "%s must be a number", key)
+			continue
+		}
+
+		if field := required[key]; field != nil {
+			*field = f
+		} else {
+			*optional[key] = &f
+		}
+	}
+
+	for _, key := range []string{LatitudeLocationKey, LongitudeLocationKey, RadiusLocationKey} {
+		if !seen[key] {
+			errs.add(SearchLocationHeader, "", "%s is required", key)
+		}
+	}
+
+	if len(errs) == 0 {
+		loc.validate(&errs)
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	return loc, nil
+}
+
+// GeolocationCoordinates are the coordinates of a W3C Geolocation API
+// position, as sent by a browser
+//
+//  Notes
+//    Accuracy and AltitudeAccuracy are in meters, Speed is in meters per
+//    second, and Heading is in degrees clockwise from true north
+//
+type GeolocationCoordinates struct {
+	Latitude         float64  `json:"latitude"`
+	Longitude        float64  `json:"longitude"`
+	Accuracy         float64  `json:"accuracy"`
+	Altitude         *float64 `json:"altitude"`
+	AltitudeAccuracy *float64 `json:"altitudeAccuracy"`
+	Heading          *float64 `json:"heading"`
+	Speed            *float64 `json:"speed"`
+}
+
+// GeolocationPosition is a W3C Geolocation API position, as sent by a
+// browser (navigator.geolocation.getCurrentPosition)
+//
+//  Notes
+//    Timestamp is in milliseconds since the UNIX epoch
+//
+type GeolocationPosition struct {
+	Coords    GeolocationCoordinates `json:"coords"`
+	Timestamp float64                `json:"timestamp"`
+}
+
+// SearchLocation converts the position to a SearchLocation
+func (pos *GeolocationPosition) SearchLocation() *SearchLocation {
+	loc := &SearchLocation{
+		Latitude:         pos.Coords.Latitude,
+		Longitude:        pos.Coords.Longitude,
+		Radius:           pos.Coords.Accuracy,
+		Speed:            pos.Coords.Speed,
+		Altitude:         pos.Coords.Altitude,
+		AltitudeAccuracy: pos.Coords.AltitudeAccuracy,
+	}
+
+	// browsers report a heading of NaN (null in JSON) when stationary
+	if pos.Coords.Heading != nil && !math.IsNaN(*pos.Coords.Heading) {
+		loc.Heading = pos.Coords.Heading
+	}
+
+	if pos.Timestamp > 0 {
+		loc.Timestamp = time.Unix(0, int64(pos.Timestamp)*int64(time.Millisecond)).UTC()
+	}
+
+	return loc
+}
+
+// SearchLocationFromGeolocation creates a SearchLocation from a JSON
+// encoded W3C Geolocation API position (see GeolocationPosition)
+func SearchLocationFromGeolocation(payload []byte) (*SearchLocation, error) {
+	var pos GeolocationPosition
+	if err := json.Unmarshal(payload, &pos); err != nil {
+		return nil, fmt.Errorf("bingSpellCheck: invalid geolocation payload: %w", err)
+	}
+
+	loc := pos.SearchLocation()
+	if err := loc.Validate(); err != nil {
+		return nil, err
+	}
+
+	return loc, nil
+}
+
+// IPLocator determines the location of an IP address, e.g. using a GeoIP
+// database or service
+type IPLocator interface {
+	Locate(ctx context.Context, ip net.IP) (*SearchLocation, error)
+}
+
+// IPLocatorFunc adapts a function to the IPLocator interface
+type IPLocatorFunc func(ctx context.Context, ip net.IP) (*SearchLocation, error)
+
+// Locate calls fn(ctx, ip)
+func (fn IPLocatorFunc) Locate(ctx context.Context, ip net.IP) (*SearchLocation, error) {
+	return fn(ctx, ip)
+}
+
+// SearchLocationFromIP creates a SearchLocation for a client IP address
+// using locator
+//
+//  Notes
+//    It is an error for locator to return neither a location nor an error.
+//
+//    If a location is not needed, the ClientIP header (see
+//    SpellCheckHeaders.WithClientIP) lets Bing locate the client instead
+//
+func SearchLocationFromIP(ctx context.Context, locator IPLocator, clientIP string) (*SearchLocation, error) {
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return nil, &ValidationError{Parameter: ClientIPHeader, Value: clientIP, Message: "must be an IPv4 or IPv6 address"}
+	}
+
+	loc, err := locator.Locate(ctx, ip)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		return nil, fmt.Errorf("bingSpellCheck: no location for %s", clientIP)
+	}
+
+	if err := loc.Validate(); err != nil {
+		return nil, err
+	}
+
+	return loc, nil
+}
+
+// WithLocation sets the SearchLocation header to loc
+//
+//  Notes
+//    Passing in nil effectively removes the Header
+//
+func (sch *SpellCheckHeaders) WithLocation(loc *SearchLocation) *SpellCheckHeaders {
+	if loc == nil {
+		return sch.SetHeader(SearchLocationHeader, "")
+	}
+
+	return sch.SetHeader(SearchLocationHeader, loc.String())
+}
+
+// Location returns the parsed SearchLocation header, or nil if it is not
+// set
+func (sch *SpellCheckHeaders) Location() (*SearchLocation, error) {
+	header := sch.Headers.Get(SearchLocationHeader)
+	if header == "" {
+		return nil, nil
+	}
+
+	return ParseSearchLocation(header)
+}
+
+func formatLocationFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
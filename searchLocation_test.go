@@ -0,0 +1,230 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func TestSearchLocationRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		loc    SearchLocation
+		header string
+	}{
+		{
+			name:   "required",
+			loc:    SearchLocation{Latitude: 47.6421, Longitude: -122.142, Radius: 100},
+			header: "lat:47.6421;long:-122.142;re:100",
+		},
+		{
+			name: "all",
+			loc: SearchLocation{
+				Latitude:         47.6421,
+				Longitude:        -122.142,
+				Radius:           22,
+				Timestamp:        time.Unix(1326499200, 0).UTC(),
+				Heading:          float(90),
+				Speed:            float(3.5),
+				Altitude:         float(20.5),
+				AltitudeAccuracy: float(5),
+				Display:          "Redmond, WA",
+			},
+			header: "lat:47.6421;long:-122.142;re:22;ts:1326499200;head:90;sp:3.5;alt:20.5;are:5;disp:Redmond, WA",
+		},
+		{
+			name:   "stationary",
+			loc:    SearchLocation{Latitude: 0, Longitude: 0, Radius: 1, Speed: float(0)},
+			header: "lat:0;long:0;re:1;sp:0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.loc.String(); got != tt.header {
+				t.Fatalf("String() = %q, want %q", got, tt.header)
+			}
+
+			parsed, err := ParseSearchLocation(tt.header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*parsed, tt.loc) {
+				t.Errorf("ParseSearchLocation = %+v, want %+v", *parsed, tt.loc)
+			}
+		})
+	}
+}
+
+func TestParseSearchLocation(t *testing.T) {
+	tests := []struct {
+		header  string
+		wantErr string
+	}{
+		{"LAT:1; Long:2 ;re:3", ""},
+		{"lat:1;long:2", "re is required"},
+		{"lat:1;long:2;re:3;lat:4", "specified more than once"},
+		{"lat:1;long:2;re:3;city:Paris", "not a known key"},
+		{"lat:north;long:2;re:3", "lat must be a number"},
+		{"lat:1;long:2;re:3;ts:yesterday", "UNIX timestamp"},
+		{"lat:1;long:2;re:3;disp", "<key>:<value>"},
+		{"lat:91;long:2;re:3", "between -90 and 90"},
+		{"lat:1;long:-181;re:3", "between -180 and 180"},
+		{"lat:1;long:2;re:0", "greater than 0"},
+		{"lat:1;long:2;re:3;head:361", "between 0 and 360"},
+		{"lat:1;long:2;re:3;sp:-1", "sp must not be negative"},
+		{"lat:1;long:2;re:3;are:-1", "are must not be negative"},
+		{"lat:NaN;long:2;re:3", "between -90 and 90"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseSearchLocation(tt.header)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("ParseSearchLocation(%q): %v", tt.header, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("ParseSearchLocation(%q) = %v, want an error containing %q", tt.header, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSearchLocationValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		loc  SearchLocation
+		want int
+	}{
+		{"valid", SearchLocation{Latitude: -90, Longitude: 180, Radius: 1}, 0},
+		{"no radius", SearchLocation{Latitude: 1, Longitude: 2}, 1},
+		{"everything wrong", SearchLocation{
+			Latitude:  -91,
+			Longitude: 181,
+			Heading:   float(-1),
+			Speed:     float(-1),
+			Display:   "a;b",
+		}, 6},
+	}
+
+	for _, tt := range tests {
+		err := tt.loc.Validate()
+
+		var errs ValidationErrors
+		if err != nil && !errors.As(err, &errs) {
+			t.Fatalf("%s: %T is not ValidationErrors", tt.name, err)
+		}
+		if len(errs) != tt.want {
+			t.Errorf("%s: got %d errors (%v), want %d", tt.name, len(errs), err, tt.want)
+		}
+	}
+}
+
+func TestSearchLocationFromGeolocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "browser position",
+			payload: `{"coords":{"latitude":47.6421,"longitude":-122.142,"accuracy":30,"altitude":null,"altitudeAccuracy":null,"heading":null,"speed":null},"timestamp":1326499200123}`,
+			want:    "lat:47.6421;long:-122.142;re:30;ts:1326499200",
+		},
+		{
+			name:    "moving",
+			payload: `{"coords":{"latitude":1,"longitude":2,"accuracy":5,"altitude":10,"altitudeAccuracy":2,"heading":180,"speed":12}}`,
+			want:    "lat:1;long:2;re:5;head:180;sp:12;alt:10;are:2",
+		},
+		{
+			name:    "no accuracy",
+			payload: `{"coords":{"latitude":1,"longitude":2}}`,
+			wantErr: true,
+		},
+		{
+			name:    "not json",
+			payload: `lat:1;long:2;re:3`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := SearchLocationFromGeolocation([]byte(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && loc.String() != tt.want {
+				t.Errorf("got %q, want %q", loc.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchLocationFromIP(t *testing.T) {
+	seattle := &SearchLocation{Latitude: 47.6, Longitude: -122.3, Radius: 5000}
+	lookupErr := errors.New("lookup failed")
+
+	tests := []struct {
+		name    string
+		ip      string
+		loc     *SearchLocation
+		err     error
+		wantErr bool
+	}{
+		{name: "ipv4", ip: "203.0.113.7", loc: seattle},
+		{name: "ipv6", ip: "2001:db8::1", loc: seattle},
+		{name: "invalid ip", ip: "localhost", loc: seattle, wantErr: true},
+		{name: "locator error", ip: "203.0.113.7", err: lookupErr, wantErr: true},
+		{name: "no location", ip: "203.0.113.7", wantErr: true},
+		{name: "invalid location", ip: "203.0.113.7", loc: &SearchLocation{Latitude: 100, Radius: 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locator := IPLocatorFunc(func(ctx context.Context, ip net.IP) (*SearchLocation, error) {
+				if !ip.Equal(net.ParseIP(tt.ip)) {
+					t.Errorf("located %s, want %s", ip, tt.ip)
+				}
+				return tt.loc, tt.err
+			})
+
+			loc, err := SearchLocationFromIP(context.Background(), locator, tt.ip)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("error = %v, want %v", err, tt.err)
+			}
+			if !tt.wantErr && loc != tt.loc {
+				t.Errorf("got %+v, want %+v", loc, tt.loc)
+			}
+		})
+	}
+}
+
+func TestHeadersLocation(t *testing.T) {
+	headers := NewSpellCheckHeaders("key")
+
+	if loc, err := headers.Location(); loc != nil || err != nil {
+		t.Errorf("Location without a header = %v, %v", loc, err)
+	}
+
+	want := &SearchLocation{Latitude: 1.5, Longitude: 2.5, Radius: 10}
+	headers.WithLocation(want)
+	if got, err := headers.Location(); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Location = %+v, %v; want %+v", got, err, want)
+	}
+
+	headers.WithLocation(nil)
+	if got := headers.Headers.Get(SearchLocationHeader); got != "" {
+		t.Errorf("header after WithLocation(nil) = %q", got)
+	}
+}
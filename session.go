@@ -0,0 +1,372 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultSessionIdleTimeout is the default inactivity period after which a
+// session expires
+const DefaultSessionIdleTimeout = 30 * time.Minute
+
+var (
+	// ErrSessionNotFound is returned by SessionManager.Resume when the
+	// session does not exist
+	ErrSessionNotFound = errors.New("bingSpellCheck: session not found")
+
+	// ErrSessionExpired is returned by SessionManager.Resume when the session
+	// has been inactive for longer than the idle timeout
+	ErrSessionExpired = errors.New("bingSpellCheck: session expired")
+)
+
+// SessionInfo is the persistent state of a Session
+//
+//  Fields
+//    SessionID         - Sent as the sessionId parameter
+//    UserID            - Sent as the userId parameter
+//    DocumentID        - Sent as the docId parameter
+//    ClientMachineName - Sent as the clientMachineName parameter (optional)
+//    Loaded            - Whether the first request (actionType=load) has
+//      succeeded; later requests are sent with actionType=edit
+//    Created           - When the session started
+//    LastUsed          - When the session was last used
+//
+type SessionInfo struct {
+	SessionID         string    `json:"sessionId"`
+	UserID            string    `json:"userId"`
+	DocumentID        string    `json:"docId"`
+	ClientMachineName string    `json:"clientMachineName,omitempty"`
+	Loaded            bool      `json:"loaded"`
+	Created           time.Time `json:"created"`
+	LastUsed          time.Time `json:"lastUsed"`
+}
+
+// SessionStore persists SessionInfo
+//
+//  Notes
+//    Get returns nil (and no error) for an unknown session. Implementations
+//    must be safe for concurrent use.
+//
+type SessionStore interface {
+	Get(ctx context.Context, sessionID string) (*SessionInfo, error)
+	Put(ctx context.Context, info *SessionInfo) error
+	Delete(ctx context.Context, sessionID string) error
+}
+
+// MemorySessionStore is an in memory SessionStore
+type MemorySessionStore struct {
+	mutex    sync.Mutex
+	sessions map[string]SessionInfo
+}
+
+// NewMemorySessionStore creates an empty MemorySessionStore
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{sessions: map[string]SessionInfo{}}
+}
+
+// Get returns a copy of the session, or nil if there is none
+func (store *MemorySessionStore) Get(ctx context.Context, sessionID string) (*SessionInfo, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	info, ok := store.sessions[sessionID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &info, nil
+}
+
+// Put saves a copy of the session
+func (store *MemorySessionStore) Put(ctx context.Context, info *SessionInfo) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.sessions[info.SessionID] = *info
+	return nil
+}
+
+// Delete removes the session
+func (store *MemorySessionStore) Delete(ctx context.Context, sessionID string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.sessions, sessionID)
+	return nil
+}
+
+// Prune removes sessions last used before before, and returns how many
+// were removed
+func (store *MemorySessionStore) Prune(before time.Time) int {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	pruned := 0
+	for id, info := range store.sessions {
+		if info.LastUsed.Before(before) {
+			delete(store.sessions, id)
+			pruned++
+		}
+	}
+
+	return pruned
+}
+
+// SessionConfig are the IDs of a new session
+//
+//  Notes
+//    A UserID or DocumentID that is empty is generated. ClientMachineName
+//    is optional.
+//
+type SessionConfig struct {
+	UserID            string
+	DocumentID        string
+	ClientMachineName string
+}
+
+// SessionManager creates, resumes and expires Sessions
+type SessionManager struct {
+	// IdleTimeout is the inactivity period after which a session expires
+	IdleTimeout time.Duration
+
+	next  Checker
+	store SessionStore
+	now   func() time.Time
+}
+
+// NewSessionManager creates a SessionManager whose sessions check text with
+// next and are persisted in store (a MemorySessionStore if nil)
+func NewSessionManager(next Checker, store SessionStore) *SessionManager {
+	if store == nil {
+		store = NewMemorySessionStore()
+	}
+
+	return &SessionManager{
+		IdleTimeout: DefaultSessionIdleTimeout,
+		next:        next,
+		store:       store,
+		now:         time.Now,
+	}
+}
+
+// Start starts a new session with a generated session ID
+func (manager *SessionManager) Start(ctx context.Context, config SessionConfig) (*Session, error) {
+	info := &SessionInfo{
+		UserID:            config.UserID,
+		DocumentID:        config.DocumentID,
+		ClientMachineName: config.ClientMachineName,
+	}
+
+	for _, id := range []*string{&info.UserID, &info.DocumentID} {
+		if *id != "" {
+			continue
+		}
+
+		var err error
+		if *id, err = NewID(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := manager.renew(ctx, info); err != nil {
+		return nil, err
+	}
+
+	return &Session{manager: manager, info: *info}, nil
+}
+
+// Resume returns the session with sessionID
+//
+//  Notes
+//    ErrSessionNotFound is returned if there is no such session, and
+//    ErrSessionExpired if it has been inactive for longer than IdleTimeout
+//    (in which case it is also deleted)
+//
+func (manager *SessionManager) Resume(ctx context.Context, sessionID string) (*Session, error) {
+	info, err := manager.store.Get(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if manager.expired(info) {
+		if err := manager.store.Delete(ctx, sessionID); err != nil {
+			return nil, err
+		}
+		return nil, ErrSessionExpired
+	}
+
+	return &Session{manager: manager, info: *info}, nil
+}
+
+// End deletes the session with sessionID
+func (manager *SessionManager) End(ctx context.Context, sessionID string) error {
+	return manager.store.Delete(ctx, sessionID)
+}
+
+// Prune removes expired sessions from the store, if the store supports it
+// (as MemorySessionStore does), and returns how many were removed
+func (manager *SessionManager) Prune() int {
+	pruner, ok := manager.store.(interface{ Prune(before time.Time) int })
+	if !ok {
+		return 0
+	}
+
+	return pruner.Prune(manager.now().Add(-manager.IdleTimeout))
+}
+
+func (manager *SessionManager) expired(info *SessionInfo) bool {
+	return manager.IdleTimeout > 0 && manager.now().Sub(info.LastUsed) > manager.IdleTimeout
+}
+
+// renew gives info a new session ID, so the next request is a load, and
+// saves it
+func (manager *SessionManager) renew(ctx context.Context, info *SessionInfo) error {
+	sessionID, err := NewID()
+	if err != nil {
+		return err
+	}
+
+	now := manager.now()
+
+	info.SessionID = sessionID
+	info.Loaded = false
+	info.Created = now
+	info.LastUsed = now
+
+	return manager.store.Put(ctx, info)
+}
+
+// Session is a Checker that sends the session, user and document IDs with
+// every request, and actionType=load for the first request and
+// actionType=edit after that
+//
+//  Notes
+//    Requests are sent with actionType=load until one succeeds, so a failed
+//    load (an error or an error response) is not followed by an edit.
+//
+//    If a session is used after being inactive for longer than the
+//    manager's IdleTimeout it continues with a new session ID (and a load
+//    request), keeping the user and document IDs.
+//
+//    CheckOptions.User defaults to the session's UserID, so a client with a
+//    ClientIDStore keeps the client ID per user.
+//
+type Session struct {
+	manager *SessionManager
+
+	mutex sync.Mutex
+	info  SessionInfo
+}
+
+// Info returns a copy of the session's state
+func (session *Session) Info() SessionInfo {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	return session.info
+}
+
+// ID returns the current session ID
+func (session *Session) ID() string {
+	return session.Info().SessionID
+}
+
+// Check checks text with the manager's Checker, adding the session's IDs
+// and actionType to opts
+func (session *Session) Check(ctx context.Context, text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+	info, err := session.touch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionOpts := CheckOptions{}
+	if opts != nil {
+		sessionOpts = *opts
+	}
+
+	sessionOpts.SessionID = info.SessionID
+	sessionOpts.UserID = info.UserID
+	sessionOpts.DocumentID = info.DocumentID
+	if info.ClientMachineName != "" {
+		sessionOpts.ClientMachineName = info.ClientMachineName
+	}
+	if sessionOpts.User == "" {
+		sessionOpts.User = info.UserID
+	}
+
+	sessionOpts.ActionType = EditActionType
+	if !info.Loaded {
+		sessionOpts.ActionType = LoadActionType
+	}
+
+	scr, err := session.manager.next.Check(ctx, text, &sessionOpts)
+	if err == nil && !info.Loaded && !scr.IsErrorResponse() {
+		session.loaded(ctx, info.SessionID)
+	}
+
+	return scr, err
+}
+
+// touch renews the session if it has expired, marks it used, and returns
+// its state
+func (session *Session) touch(ctx context.Context) (SessionInfo, error) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	if session.manager.expired(&session.info) {
+		oldID := session.info.SessionID
+		if err := session.manager.renew(ctx, &session.info); err != nil {
+			return SessionInfo{}, err
+		}
+		_ = session.manager.store.Delete(ctx, oldID)
+	}
+
+	before := session.info
+
+	session.info.LastUsed = session.manager.now()
+	if err := session.manager.store.Put(ctx, &session.info); err != nil {
+		session.info = before
+		return SessionInfo{}, err
+	}
+
+	return session.info, nil
+}
+
+// loaded marks the session loaded after a successful load request, unless
+// it has since been renewed
+//
+//  Notes
+//    If the store fails, the session is left unloaded, so the next request
+//    is a load again
+//
+func (session *Session) loaded(ctx context.Context, sessionID string) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	if session.info.SessionID != sessionID || session.info.Loaded {
+		return
+	}
+
+	session.info.Loaded = true
+	if err := session.manager.store.Put(ctx, &session.info); err != nil {
+		session.info.Loaded = false
+	}
+}
+
+// NewID returns a random 128 bit ID, hex encoded, suitable for the
+// sessionId, userId and docId parameters
+func NewID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id[:]), nil
+}
@@ -0,0 +1,202 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// sessionClock is a manually advanced clock for a SessionManager
+type sessionClock struct {
+	now time.Time
+}
+
+func (clock *sessionClock) advance(d time.Duration) {
+	clock.now = clock.now.Add(d)
+}
+
+// recordingChecker returns a stubChecker that records the options of each
+// check and answers with the responses in turn (nil means an error)
+func recordingChecker(sent *[]CheckOptions, responses ...*SpellCheckResponse) *stubChecker {
+	stub := &stubChecker{}
+	stub.respond = func(text string, opts *CheckOptions) (*SpellCheckResponse, error) {
+		*sent = append(*sent, *opts)
+
+		n := stub.count() - 1
+		if n >= len(responses) {
+			n = len(responses) - 1
+		}
+		if responses[n] == nil {
+			return nil, errors.New("check failed")
+		}
+		return responses[n].Clone(), nil
+	}
+
+	return stub
+}
+
+func newTestSessionManager(next Checker) (*SessionManager, *sessionClock) {
+	clock := &sessionClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	manager := NewSessionManager(next, nil)
+	manager.IdleTimeout = time.Minute
+	manager.now = func() time.Time { return clock.now }
+
+	return manager, clock
+}
+
+func TestSessionActionType(t *testing.T) {
+	ok := spellCheckResponse()
+
+	tests := []struct {
+		name      string
+		responses []*SpellCheckResponse
+		want      []string
+	}{
+		{"load then edit", []*SpellCheckResponse{ok}, []string{LoadActionType, EditActionType, EditActionType}},
+		{"failed load", []*SpellCheckResponse{nil, ok}, []string{LoadActionType, LoadActionType, EditActionType}},
+		{"error response", []*SpellCheckResponse{errorResponse(RateLimitExceededErrorCode), ok}, []string{LoadActionType, LoadActionType, EditActionType}},
+		{"failed edit", []*SpellCheckResponse{ok, nil}, []string{LoadActionType, EditActionType, EditActionType}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []CheckOptions
+			manager, _ := newTestSessionManager(recordingChecker(&sent, tt.responses...))
+
+			session, err := manager.Start(context.Background(), SessionConfig{UserID: "user", DocumentID: "doc"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for range tt.want {
+				session.Check(context.Background(), "text", nil)
+			}
+
+			for i, opts := range sent {
+				if opts.ActionType != tt.want[i] {
+					t.Errorf("request %d sent actionType %q, want %q", i, opts.ActionType, tt.want[i])
+				}
+				if opts.SessionID != session.ID() || opts.UserID != "user" || opts.DocumentID != "doc" || opts.User != "user" {
+					t.Errorf("request %d sent %+v", i, opts)
+				}
+			}
+		})
+	}
+}
+
+func TestSessionLoadedIsStored(t *testing.T) {
+	var sent []CheckOptions
+	manager, _ := newTestSessionManager(recordingChecker(&sent, spellCheckResponse()))
+	ctx := context.Background()
+
+	session, err := manager.Start(ctx, SessionConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.Check(ctx, "text", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed, err := manager.Resume(ctx, session.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resumed.Info().Loaded {
+		t.Error("the resumed session is not loaded")
+	}
+
+	resumed.Check(ctx, "text", nil)
+	if got := sent[len(sent)-1].ActionType; got != EditActionType {
+		t.Errorf("resumed session sent actionType %q, want %q", got, EditActionType)
+	}
+}
+
+func TestSessionRenewal(t *testing.T) {
+	var sent []CheckOptions
+	manager, clock := newTestSessionManager(recordingChecker(&sent, spellCheckResponse()))
+	ctx := context.Background()
+
+	session, err := manager.Start(ctx, SessionConfig{ClientMachineName: "machine"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := session.Info()
+
+	session.Check(ctx, "text", nil)
+	clock.advance(2 * time.Minute)
+	session.Check(ctx, "text", nil)
+
+	after := session.Info()
+	if after.SessionID == before.SessionID {
+		t.Fatal("the expired session was not renewed")
+	}
+	if after.UserID != before.UserID || after.DocumentID != before.DocumentID || after.ClientMachineName != "machine" {
+		t.Errorf("renewal changed the IDs: %+v, was %+v", after, before)
+	}
+	if !after.Created.Equal(clock.now) {
+		t.Errorf("Created = %s, want %s", after.Created, clock.now)
+	}
+
+	if got := sent[1]; got.ActionType != LoadActionType || got.SessionID != after.SessionID {
+		t.Errorf("first request after renewal sent %q with %q", got.ActionType, got.SessionID)
+	}
+
+	if _, err := manager.Resume(ctx, before.SessionID); err != ErrSessionNotFound {
+		t.Errorf("Resume of the old session = %v, want %v", err, ErrSessionNotFound)
+	}
+}
+
+func TestSessionManagerResume(t *testing.T) {
+	manager, clock := newTestSessionManager(flagging())
+	ctx := context.Background()
+
+	session, err := manager.Start(ctx, SessionConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		id      string
+		want    error
+	}{
+		{"active", 30 * time.Second, session.ID(), nil},
+		{"unknown", 0, "unknown", ErrSessionNotFound},
+		{"expired", 2 * time.Minute, session.ID(), ErrSessionExpired},
+		{"deleted once expired", 0, session.ID(), ErrSessionNotFound},
+	}
+
+	for _, tt := range tests {
+		clock.advance(tt.advance)
+		if _, err := manager.Resume(ctx, tt.id); err != tt.want {
+			t.Errorf("%s: Resume = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestSessionManagerPrune(t *testing.T) {
+	manager, clock := newTestSessionManager(flagging())
+	ctx := context.Background()
+
+	idle, _ := manager.Start(ctx, SessionConfig{})
+	clock.advance(45 * time.Second)
+	active, _ := manager.Start(ctx, SessionConfig{})
+	clock.advance(30 * time.Second)
+
+	if got := manager.Prune(); got != 1 {
+		t.Errorf("Prune = %d, want 1", got)
+	}
+	if _, err := manager.Resume(ctx, idle.ID()); err != ErrSessionNotFound {
+		t.Errorf("Resume of the idle session = %v", err)
+	}
+	if _, err := manager.Resume(ctx, active.ID()); err != nil {
+		t.Errorf("Resume of the active session = %v", err)
+	}
+
+	manager.End(ctx, active.ID())
+	if _, err := manager.Resume(ctx, active.ID()); err != ErrSessionNotFound {
+		t.Errorf("Resume of an ended session = %v", err)
+	}
+}
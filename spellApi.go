@@ -1,11 +1,14 @@
 package bingSpellCheck
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
+	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"strings"
+	"strconv"
 	"time"
 )
 
@@ -15,6 +18,16 @@ const BingHost = "https://api.cognitive.microsoft.com"
 // BingSpellCheckPath is the url path of the Bing spell check, version 7, API
 const BingSpellCheckPath = "/bing/v7.0/spellcheck"
 
+const (
+	// MaxGetTextLength is the maximum number of characters of text (including
+	// pre and post context) that can be sent using GET
+	MaxGetTextLength = 1500
+
+	// MaxPostTextLength is the maximum number of characters of text (including
+	// pre and post context) that can be sent using POST
+	MaxPostTextLength = 10000
+)
+
 // Client is a simple Bing Spell Check API client that is most useful when
 // the values of Params and Headers remain constant.
 //
@@ -29,6 +42,19 @@ type Client struct {
 
 	spellCheckURL string
 	httpClient    *http.Client
+	fallback      Checker
+	keyPool       *KeyPool
+	clientIDStore ClientIDStore
+
+	skipValidation bool
+	dryRun         bool
+	dryRunLogger   *log.Logger
+
+	logger     *slog.Logger
+	logOptions *LogOptions
+	metrics    Metrics
+	tracer     Tracer
+	ledger     *Ledger
 }
 
 // GetSpellCheckURL returns the URL for the Bing Spell Check version 7 API
@@ -48,38 +74,86 @@ func NewClient(subscriptionKey string) *Client {
 	}
 }
 
+// WithEndpoint sets the URL the client sends spell check requests to
+//
+//  Notes
+//    The default is the value returned by GetSpellCheckURL. This is mostly
+//    useful for regional endpoints and for testing (see package bingtest)
+//
+func (client *Client) WithEndpoint(spellCheckURL string) *Client {
+	client.spellCheckURL = spellCheckURL
+	return client
+}
+
+// WithHTTPClient sets the *http.Client used to send requests
+func (client *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	client.httpClient = httpClient
+	return client
+}
+
+// WithFallback sets a checker that is used when a request fails, Bing is
+// unavailable (see Error.IsUnavailable), or the subscription key is rejected
+// (see SpellCheckResponse.IsKeyRejected)
+//
+//  Notes
+//    See package hunspell for an offline Checker
+//
+func (client *Client) WithFallback(fallback Checker) *Client {
+	client.fallback = fallback
+	return client
+}
+
+// WithValidation enables or disables validation of each request before it
+// is sent (see ValidateRequest). Validation is enabled by default.
+func (client *Client) WithValidation(enabled bool) *Client {
+	client.skipValidation = !enabled
+	return client
+}
+
+// WithKeyPool rotates requests across the subscription keys in pool
+//
+//  Notes
+//    When a key is rejected for authorization, or for exceeding its rate
+//    limit or quota, it is marked exhausted and the request is retried with
+//    the next key. The subscription key in Headers is not used.
+//
+func (client *Client) WithKeyPool(pool *KeyPool) *Client {
+	client.keyPool = pool
+	return client
+}
+
 // SpellCheck is the core function for accessing the Bing Spell Check API
 func SpellCheck(
 	httpClient *http.Client,
 	targetURL string,
 	params *SpellCheckParams,
 	headers *SpellCheckHeaders) (*SpellCheckResponse, error) {
+	return SpellCheckContext(context.Background(), httpClient, targetURL, params, headers)
+}
 
-	var err error
-
-	// if the length of the text is excessively long we need to POST, not GET
-	postRequired := params.TotalTextLength() > 1500
-
-	q := params.Values
-
-	var r *http.Request
+// SpellCheckContext is SpellCheck with a context that controls cancellation
+// of the request
+//
+//  Notes
+//    The response's Meta describes the HTTP response (status, latency,
+//    trace ID, client ID and rate limits).
+//
+//    JSON-LD responses (see WithLinkedJSON) are normalized to the plain
+//    JSON form, and the JSON-LD form is available as the response's Linked
+//
+func SpellCheckContext(
+	ctx context.Context,
+	httpClient *http.Client,
+	targetURL string,
+	params *SpellCheckParams,
+	headers *SpellCheckHeaders) (*SpellCheckResponse, error) {
 
-	if postRequired {
-		r, err = http.NewRequest(http.MethodPost, targetURL, strings.NewReader(q.Encode()))
-		if err != nil {
-			return nil, err
-		}
-		// form encoded
-		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	} else {
-		r, err = http.NewRequest(http.MethodGet, targetURL, nil)
-		if err != nil {
-			return nil, err
-		}
-		r.URL.RawQuery = q.Encode()
+	r, err := BuildRequestContext(ctx, targetURL, params, headers)
+	if err != nil {
+		return nil, err
 	}
 
-	r.Header = headers.Headers
+	start := time.Now()
 
 	resp, err := httpClient.Do(r)
 	if err != nil {
@@ -93,26 +167,61 @@ func SpellCheck(
 		return nil, err
 	}
 
-	var spellCheck SpellCheckResponse
-	err = json.Unmarshal(bodyBytes, &spellCheck)
+	meta := newResponseMeta(resp, time.Since(start))
+
+	spellCheck, err := parseSpellCheckResponse(resp.Header, bodyBytes)
+
+	// non Bing errors (e.g. quota errors from Azure, or a proxy) are converted
+	// to an ErrorResponse so they are not mistaken for "no suggestions"
+	if resp.StatusCode >= 400 && (err != nil || !spellCheck.IsErrorResponse()) {
+		spellCheck = newHTTPErrorResponse(resp.StatusCode, bodyBytes)
+		err = nil
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	return &spellCheck, nil
+	spellCheck.Meta = meta
+	return spellCheck, nil
+}
+
+// newHTTPErrorResponse creates an ErrorResponse for a failed request whose
+// body is not a Bing ErrorResponse
+func newHTTPErrorResponse(statusCode int, body []byte) *SpellCheckResponse {
+	// Azure API Management errors are of the form {"error":{"code":"","message":""}}
+	var azure struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	message := http.StatusText(statusCode)
+	if json.Unmarshal(body, &azure) == nil && azure.Error.Message != "" {
+		message = azure.Error.Message
+	}
+
+	return &SpellCheckResponse{
+		Type: ErrorResponseType,
+		Errors: []Error{{
+			Code:    strconv.Itoa(statusCode),
+			Message: message,
+		}},
+	}
 }
 
 // SpellCheck performs a spelling and/or grammar check on text
 func (client *Client) SpellCheck(text string) (*SpellCheckResponse, error) {
-	client.Params.WithTextAndContext(text, "", "")
-	return SpellCheck(client.httpClient, client.spellCheckURL, client.Params, client.Headers)
+	return client.SpellCheckWithContext(text, "", "")
 }
 
 // SpellCheckWithContext performs a spelling and/or grammar check on text with optional
 // pre/post context
 func (client *Client) SpellCheckWithContext(text, preContext, postContext string) (*SpellCheckResponse, error) {
-	client.Params.WithTextAndContext(text, preContext, postContext)
-	return SpellCheck(client.httpClient, client.spellCheckURL, client.Params, client.Headers)
+	return client.Check(context.Background(), text, &CheckOptions{
+		PreContext:  preContext,
+		PostContext: postContext,
+	})
 }
 
 // AutoCorrect performs a spell check and corrects the text based on corrections
@@ -0,0 +1,247 @@
+package bingSpellCheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SpellCheckRequest is a typed view of the parameters and headers of a
+// spell check request, suitable for config files (JSON or YAML)
+//
+//  Notes
+//    SubscriptionKey is never marshaled, so a SpellCheckRequest can be
+//    saved or logged without leaking the key.
+//
+//    Unmarshaling fails if Mode is neither ProofMode nor SpellMode, so a
+//    typo in a config file is reported when it is loaded rather than on
+//    the first check. UnmarshalYAML uses the unmarshaler interface that
+//    gopkg.in/yaml.v2 and v3 both support, so this package does not depend
+//    on either.
+//
+//    Only the parameters and headers documented by the Bing Spell Check API
+//    are represented; any others are dropped by NewSpellCheckRequest
+//
+type SpellCheckRequest struct {
+	Text              string      `json:"text,omitempty" yaml:"text,omitempty"`
+	PreContext        string      `json:"preContextText,omitempty" yaml:"preContextText,omitempty"`
+	PostContext       string      `json:"postContextText,omitempty" yaml:"postContextText,omitempty"`
+	Mode              string      `json:"mode,omitempty" yaml:"mode,omitempty"`
+	Market            MarketCode  `json:"mkt,omitempty" yaml:"mkt,omitempty"`
+	CountryCode       CountryCode `json:"cc,omitempty" yaml:"cc,omitempty"`
+	SetLang           string      `json:"setLang,omitempty" yaml:"setLang,omitempty"`
+	ActionType        string      `json:"actionType,omitempty" yaml:"actionType,omitempty"`
+	AppName           string      `json:"appName,omitempty" yaml:"appName,omitempty"`
+	ClientMachineName string      `json:"clientMachineName,omitempty" yaml:"clientMachineName,omitempty"`
+	DocumentID        string      `json:"docId,omitempty" yaml:"docId,omitempty"`
+	SessionID         string      `json:"sessionId,omitempty" yaml:"sessionId,omitempty"`
+	UserID            string      `json:"userId,omitempty" yaml:"userId,omitempty"`
+
+	SubscriptionKey string `json:"-" yaml:"-"`
+	Accept          string `json:"accept,omitempty" yaml:"accept,omitempty"`
+	AcceptLanguage  string `json:"acceptLanguage,omitempty" yaml:"acceptLanguage,omitempty"`
+	UserAgent       string `json:"userAgent,omitempty" yaml:"userAgent,omitempty"`
+	Pragma          string `json:"pragma,omitempty" yaml:"pragma,omitempty"`
+	ClientID        string `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+	ClientIP        string `json:"clientIp,omitempty" yaml:"clientIp,omitempty"`
+	SearchLocation  string `json:"searchLocation,omitempty" yaml:"searchLocation,omitempty"`
+}
+
+// plainRequest is a SpellCheckRequest without its unmarshal methods
+type plainRequest SpellCheckRequest
+
+// UnmarshalJSON unmarshals the request and validates its Mode
+func (req *SpellCheckRequest) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*plainRequest)(req)); err != nil {
+		return err
+	}
+
+	return req.validateMode()
+}
+
+// UnmarshalYAML unmarshals the request and validates its Mode
+func (req *SpellCheckRequest) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal((*plainRequest)(req)); err != nil {
+		return err
+	}
+
+	return req.validateMode()
+}
+
+// validateMode returns a *ValidationError if Mode is set to an unknown mode
+func (req *SpellCheckRequest) validateMode() error {
+	if req.Mode != "" && req.Mode != ProofMode && req.Mode != SpellMode {
+		return &ValidationError{
+			Parameter: ModeParam,
+			Value:     req.Mode,
+			Message:   fmt.Sprintf("must be %q or %q", ProofMode, SpellMode),
+		}
+	}
+
+	return nil
+}
+
+// FieldDiff is a difference between two SpellCheckRequests
+//
+//  Fields
+//    Field - The name of the SpellCheckRequest field, e.g. "Market"
+//    Name  - The query parameter or header the field maps to, e.g. "mkt"
+//    Old   - The value in the original request
+//    New   - The value in the other request
+//
+//  Notes
+//    The values of SubscriptionKey are redacted
+//
+type FieldDiff struct {
+	Field string
+	Name  string
+	Old   string
+	New   string
+}
+
+// redactedValue replaces secrets in FieldDiff
+const redactedValue = "REDACTED"
+
+// requestField maps a SpellCheckRequest field to a query parameter or header
+type requestField struct {
+	field  string
+	param  string
+	header string
+	value  func(req *SpellCheckRequest) *string
+}
+
+var requestFields = []requestField{
+	{"Text", TextParam, "", func(req *SpellCheckRequest) *string { return &req.Text }},
+	{"PreContext", PreContextTextParam, "", func(req *SpellCheckRequest) *string { return &req.PreContext }},
+	{"PostContext", PostContextTextParam, "", func(req *SpellCheckRequest) *string { return &req.PostContext }},
+	{"Mode", ModeParam, "", func(req *SpellCheckRequest) *string { return &req.Mode }},
+	{"Market", MarketParam, "", func(req *SpellCheckRequest) *string { return (*string)(&req.Market) }},
+	{"CountryCode", CountryCodeParam, "", func(req *SpellCheckRequest) *string { return (*string)(&req.CountryCode) }},
+	{"SetLang", LanguageParam, "", func(req *SpellCheckRequest) *string { return &req.SetLang }},
+	{"ActionType", ActionTypeParam, "", func(req *SpellCheckRequest) *string { return &req.ActionType }},
+	{"AppName", AppNameParam, "", func(req *SpellCheckRequest) *string { return &req.AppName }},
+	{"ClientMachineName", ClientMachineNameParam, "", func(req *SpellCheckRequest) *string { return &req.ClientMachineName }},
+	{"DocumentID", DocumentIDParam, "", func(req *SpellCheckRequest) *string { return &req.DocumentID }},
+	{"SessionID", SessionIDParam, "", func(req *SpellCheckRequest) *string { return &req.SessionID }},
+	{"UserID", UserIDParam, "", func(req *SpellCheckRequest) *string { return &req.UserID }},
+
+	{"SubscriptionKey", "", SubscriptionKeyHeader, func(req *SpellCheckRequest) *string { return &req.SubscriptionKey }},
+	{"Accept", "", AcceptHeader, func(req *SpellCheckRequest) *string { return &req.Accept }},
+	{"AcceptLanguage", "", AcceptLanguageHeader, func(req *SpellCheckRequest) *string { return &req.AcceptLanguage }},
+	{"UserAgent", "", UserAgentHeader, func(req *SpellCheckRequest) *string { return &req.UserAgent }},
+	{"Pragma", "", PragmaHeader, func(req *SpellCheckRequest) *string { return &req.Pragma }},
+	{"ClientID", "", ClientIDHeader, func(req *SpellCheckRequest) *string { return &req.ClientID }},
+	{"ClientIP", "", ClientIPHeader, func(req *SpellCheckRequest) *string { return &req.ClientIP }},
+	{"SearchLocation", "", SearchLocationHeader, func(req *SpellCheckRequest) *string { return &req.SearchLocation }},
+}
+
+// NewSpellCheckRequest creates a SpellCheckRequest from params and headers,
+// either of which may be nil
+func NewSpellCheckRequest(params *SpellCheckParams, headers *SpellCheckHeaders) *SpellCheckRequest {
+	req := &SpellCheckRequest{}
+
+	for _, f := range requestFields {
+		switch {
+		case f.param != "" && params != nil:
+			*f.value(req) = params.Values.Get(f.param)
+		case f.header != "" && headers != nil:
+			*f.value(req) = headers.Headers.Get(f.header)
+		}
+	}
+
+	return req
+}
+
+// Params returns the query parameters of the request
+func (req *SpellCheckRequest) Params() *SpellCheckParams {
+	params := NewSpellCheckParams()
+
+	for _, f := range requestFields {
+		if f.param != "" {
+			params.SetParam(f.param, *f.value(req))
+		}
+	}
+
+	return params
+}
+
+// Headers returns the headers of the request
+//
+//  Notes
+//    Unlike NewSpellCheckHeaders, no Accept header is added if Accept is
+//    empty
+//
+func (req *SpellCheckRequest) Headers() *SpellCheckHeaders {
+	headers := &SpellCheckHeaders{Headers: http.Header{}}
+
+	for _, f := range requestFields {
+		if f.header != "" {
+			headers.SetHeader(f.header, *f.value(req))
+		}
+	}
+
+	return headers
+}
+
+// Clone returns a copy of the request
+func (req *SpellCheckRequest) Clone() *SpellCheckRequest {
+	clone := *req
+	return &clone
+}
+
+// Diff returns the fields whose values differ between req and other, in
+// field order
+func (req *SpellCheckRequest) Diff(other *SpellCheckRequest) []FieldDiff {
+	var diffs []FieldDiff
+
+	for _, f := range requestFields {
+		oldValue, newValue := *f.value(req), *f.value(other)
+		if oldValue == newValue {
+			continue
+		}
+
+		name := f.param
+		if name == "" {
+			name = f.header
+		}
+
+		if f.header == SubscriptionKeyHeader {
+			oldValue, newValue = redact(oldValue), redact(newValue)
+		}
+
+		diffs = append(diffs, FieldDiff{Field: f.field, Name: name, Old: oldValue, New: newValue})
+	}
+
+	return diffs
+}
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return redactedValue
+}
+
+// Request returns a SpellCheckRequest for the client's Params and Headers
+func (client *Client) Request() *SpellCheckRequest {
+	return NewSpellCheckRequest(client.Params, client.Headers)
+}
+
+// WithRequest replaces the client's Params and Headers with those of req
+//
+//  Notes
+//    If req.SubscriptionKey is empty (e.g. req was loaded from a config
+//    file) the client's subscription key is kept
+//
+func (client *Client) WithRequest(req *SpellCheckRequest) *Client {
+	key := client.Headers.Headers.Get(SubscriptionKeyHeader)
+
+	client.Params = req.Params()
+	client.Headers = req.Headers()
+	if req.SubscriptionKey == "" {
+		client.Headers.WithSubscriptionKey(key)
+	}
+
+	return client
+}
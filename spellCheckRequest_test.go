@@ -0,0 +1,201 @@
+package bingSpellCheck
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func fullRequest() *SpellCheckRequest {
+	return &SpellCheckRequest{
+		Text:              "teh text",
+		PreContext:        "before",
+		PostContext:       "after",
+		Mode:              SpellMode,
+		Market:            MktUnitedStates,
+		CountryCode:       "US",
+		SetLang:           "EN",
+		ActionType:        "Edit",
+		AppName:           "app",
+		ClientMachineName: "machine",
+		DocumentID:        "doc",
+		SessionID:         "session",
+		UserID:            "user",
+		SubscriptionKey:   "secret-key",
+		Accept:            "application/json",
+		AcceptLanguage:    "en-US",
+		UserAgent:         "agent",
+		Pragma:            "no-cache",
+		ClientID:          "client-id",
+		ClientIP:          "10.0.0.1",
+		SearchLocation:    "lat:47.6;long:-122.3;re:22",
+	}
+}
+
+func TestSpellCheckRequestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *SpellCheckRequest
+	}{
+		{"empty", &SpellCheckRequest{}},
+		{"every field", fullRequest()},
+		{"parameters only", &SpellCheckRequest{Text: "a", Mode: ProofMode, Market: MktUnitedStates}},
+		{"headers only", &SpellCheckRequest{SubscriptionKey: "k", ClientID: "id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewSpellCheckRequest(tt.req.Params(), tt.req.Headers())
+			if !reflect.DeepEqual(got, tt.req) {
+				t.Errorf("got %+v, want %+v", got, tt.req)
+			}
+		})
+	}
+}
+
+func TestSpellCheckRequestJSON(t *testing.T) {
+	req := fullRequest()
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), req.SubscriptionKey) {
+		t.Errorf("the subscription key was marshaled: %s", data)
+	}
+
+	var got SpellCheckRequest
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := req.Clone()
+	want.SubscriptionKey = ""
+	if !reflect.DeepEqual(&got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestSpellCheckRequestUnmarshal(t *testing.T) {
+	tests := []struct {
+		data    string
+		wantErr bool
+	}{
+		{`{}`, false},
+		{`{"mode": "spell"}`, false},
+		{`{"mode": "proof", "mkt": "en-US"}`, false},
+		{`{"mode": "grammar"}`, true},
+		{`{"mode": 1}`, true},
+	}
+
+	for _, tt := range tests {
+		var fromJSON SpellCheckRequest
+		err := json.Unmarshal([]byte(tt.data), &fromJSON)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UnmarshalJSON(%s) error = %v, want error %v", tt.data, err, tt.wantErr)
+		}
+
+		// a YAML library calls UnmarshalYAML with a func that decodes into
+		// the value it is given
+		var fromYAML SpellCheckRequest
+		err = fromYAML.UnmarshalYAML(func(v interface{}) error {
+			return json.Unmarshal([]byte(tt.data), v)
+		})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UnmarshalYAML(%s) error = %v, want error %v", tt.data, err, tt.wantErr)
+		}
+		if !tt.wantErr && !reflect.DeepEqual(fromJSON, fromYAML) {
+			t.Errorf("UnmarshalYAML(%s) = %+v, UnmarshalJSON = %+v", tt.data, fromYAML, fromJSON)
+		}
+	}
+
+	var validationErr *ValidationError
+	var req SpellCheckRequest
+	if err := json.Unmarshal([]byte(`{"mode": "grammar"}`), &req); !errors.As(err, &validationErr) || validationErr.Parameter != ModeParam {
+		t.Errorf("got error %v, want a *ValidationError for %s", err, ModeParam)
+	}
+}
+
+func TestSpellCheckRequestTags(t *testing.T) {
+	typ := reflect.TypeOf(SpellCheckRequest{})
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		jsonTag, yamlTag := field.Tag.Get("json"), field.Tag.Get("yaml")
+		if jsonTag == "" || jsonTag != yamlTag {
+			t.Errorf("%s: json tag %q, yaml tag %q", field.Name, jsonTag, yamlTag)
+		}
+	}
+}
+
+func TestSpellCheckRequestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(req *SpellCheckRequest)
+		want   []FieldDiff
+	}{
+		{
+			name:   "same",
+			change: func(req *SpellCheckRequest) {},
+			want:   nil,
+		},
+		{
+			name: "parameters and headers in field order",
+			change: func(req *SpellCheckRequest) {
+				req.ClientID = "other-id"
+				req.Market = "fr-FR"
+			},
+			want: []FieldDiff{
+				{Field: "Market", Name: MarketParam, Old: "en-US", New: "fr-FR"},
+				{Field: "ClientID", Name: ClientIDHeader, Old: "client-id", New: "other-id"},
+			},
+		},
+		{
+			name:   "subscription key is redacted",
+			change: func(req *SpellCheckRequest) { req.SubscriptionKey = "other-key" },
+			want:   []FieldDiff{{Field: "SubscriptionKey", Name: SubscriptionKeyHeader, Old: redactedValue, New: redactedValue}},
+		},
+		{
+			name:   "removed subscription key",
+			change: func(req *SpellCheckRequest) { req.SubscriptionKey = "" },
+			want:   []FieldDiff{{Field: "SubscriptionKey", Name: SubscriptionKeyHeader, Old: redactedValue, New: ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := fullRequest()
+			other := req.Clone()
+			tt.change(other)
+
+			if got := req.Diff(other); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(req, fullRequest()) {
+				t.Error("changing the clone changed the request")
+			}
+		})
+	}
+}
+
+func TestClientWithRequest(t *testing.T) {
+	client := NewClient("client-key")
+
+	req := fullRequest()
+	req.SubscriptionKey = ""
+	client.WithRequest(req)
+
+	got := client.Request()
+	if got.SubscriptionKey != "client-key" {
+		t.Errorf("subscription key = %q, want the client's", got.SubscriptionKey)
+	}
+	got.SubscriptionKey = ""
+	if !reflect.DeepEqual(got, req) {
+		t.Errorf("got %+v, want %+v", got, req)
+	}
+
+	client.WithRequest(fullRequest())
+	if got := client.Request().SubscriptionKey; got != "secret-key" {
+		t.Errorf("subscription key = %q, want the request's", got)
+	}
+}
@@ -0,0 +1,160 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"strconv"
+)
+
+// Names of the spans created by Client and the Checker decorators
+const (
+	// SpanCheck is a logical check made by Client.Check, including any
+	// failover to the fallback checker
+	SpanCheck = "bingspellcheck.check"
+
+	// SpanRequest is a request sent to Bing (one per key when using a key
+	// pool)
+	SpanRequest = "bingspellcheck.request"
+
+	// SpanAttempt is an attempt made by RetryChecker
+	SpanAttempt = "bingspellcheck.attempt"
+
+	// SpanChunked is a check made by ChunkedChecker, and SpanChunk is the
+	// check of one of its chunks
+	SpanChunked = "bingspellcheck.chunked"
+	SpanChunk   = "bingspellcheck.chunk"
+
+	// SpanBatch is a batch checked by BatchChecker
+	SpanBatch = "bingspellcheck.batch"
+)
+
+// Span attribute keys
+const (
+	MarketAttribute        = "bingspellcheck.market"
+	ModeAttribute          = "bingspellcheck.mode"
+	TagAttribute           = "bingspellcheck.tag"
+	TextLengthAttribute    = "bingspellcheck.text_length"
+	FlaggedTokensAttribute = "bingspellcheck.flagged_tokens"
+	TraceIDAttribute       = "bingspellcheck.trace_id"
+	StatusCodeAttribute    = "bingspellcheck.status_code"
+	ErrorCodeAttribute     = "bingspellcheck.error_code"
+	AttemptAttribute       = "bingspellcheck.attempt"
+	ChunkAttribute         = "bingspellcheck.chunk"
+	ChunkCountAttribute    = "bingspellcheck.chunk_count"
+	BatchSizeAttribute     = "bingspellcheck.batch_size"
+	BatchFailedAttribute   = "bingspellcheck.batch_failed"
+)
+
+// Attribute is a key/value annotation of a Span
+//
+//  Notes
+//    Value is a string, an int or a bool
+//
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns the attribute as key=value
+func (attr Attribute) String() string {
+	switch value := attr.Value.(type) {
+	case string:
+		return attr.Key + "=" + value
+	case int:
+		return attr.Key + "=" + strconv.Itoa(value)
+	case bool:
+		return attr.Key + "=" + strconv.FormatBool(value)
+	}
+
+	return attr.Key + "=?"
+}
+
+// Span is a traced operation (see Tracer)
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer creates the spans recorded by Client and the Checker decorators
+// (see the Span constants for names)
+//
+//  Notes
+//    Start returns a context holding the new span, which is passed to the
+//    operations it covers so their spans become its children. An adapter
+//    for OpenTelemetry can implement Tracer with trace.Tracer.Start,
+//    without this package depending on it.
+//
+//    Implementations must be safe for concurrent use
+//
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// WithTracer creates SpanCheck and SpanRequest spans for the checks the
+// client makes
+func (client *Client) WithTracer(tracer Tracer) *Client {
+	client.tracer = tracer
+	return client
+}
+
+// WithTracer creates a SpanAttempt span for each attempt
+func (rc *RetryChecker) WithTracer(tracer Tracer) *RetryChecker {
+	rc.tracer = tracer
+	return rc
+}
+
+// noopSpan is the Span used when there is no Tracer
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// startSpan starts a span using tracer, or returns ctx and a span that does
+// nothing if tracer is nil
+func startSpan(ctx context.Context, tracer Tracer, name string, attrs ...Attribute) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return tracer.Start(ctx, name, attrs...)
+}
+
+// requestAttributes returns the attributes describing a request
+func requestAttributes(params *SpellCheckParams, tag string) []Attribute {
+	return []Attribute{
+		{MarketAttribute, params.Values.Get(MarketParam)},
+		{ModeAttribute, params.Values.Get(ModeParam)},
+		{TextLengthAttribute, params.TotalTextLength()},
+		{TagAttribute, tag},
+	}
+}
+
+// endSpan annotates span with the outcome of a check and ends it
+func endSpan(span Span, scr *SpellCheckResponse, err error) {
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+	if scr == nil {
+		return
+	}
+
+	if scr.Meta != nil {
+		span.SetAttributes(
+			Attribute{StatusCodeAttribute, scr.Meta.StatusCode},
+			Attribute{TraceIDAttribute, scr.Meta.TraceID})
+	}
+
+	if scr.IsErrorResponse() {
+		if len(scr.Errors) > 0 {
+			span.SetAttributes(Attribute{ErrorCodeAttribute, scr.Errors[0].Code})
+			span.RecordError(scr.Errors[0])
+		}
+		return
+	}
+
+	span.SetAttributes(Attribute{FlaggedTokensAttribute, len(scr.FlaggedTokens)})
+}
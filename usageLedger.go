@@ -0,0 +1,576 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Layouts of the day and month of usage records (Bing bills in UTC)
+const (
+	UsageDayLayout   = "2006-01-02"
+	UsageMonthLayout = "2006-01"
+)
+
+// UsageRecord is the number of transactions made with a key, by a caller
+// tag, on a day
+//
+//  Fields
+//    Day          - The UTC day, e.g. "2026-10-19" (see UsageDayLayout)
+//    KeyID        - Identifies the subscription key without revealing it
+//      (see KeyID)
+//    Tag          - The Tag of the CheckOptions ("" when none)
+//    Transactions - The number of billable requests
+//
+type UsageRecord struct {
+	Day          string `json:"day"`
+	KeyID        string `json:"keyId"`
+	Tag          string `json:"tag"`
+	Transactions int64  `json:"transactions"`
+}
+
+// Usage is the number of transactions in a period, in total and by key ID
+// and tag
+type Usage struct {
+	Transactions int64
+	ByKey        map[string]int64
+	ByTag        map[string]int64
+}
+
+// KeyID returns a short fingerprint of a subscription key, used to
+// identify the key in a Ledger without storing it
+func KeyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:4])
+}
+
+// BudgetExceededError is returned when a request would exceed the hard
+// budget of a Ledger
+type BudgetExceededError struct {
+	Month  string
+	Used   int64
+	Needed int64
+	Budget int64
+}
+
+func (err *BudgetExceededError) Error() string {
+	return fmt.Sprintf("bingSpellCheck: transaction budget exceeded for %s: %d used, %d needed, budget %d",
+		err.Month, err.Used, err.Needed, err.Budget)
+}
+
+// LedgerStore persists the records of a Ledger
+//
+//  Notes
+//    Implementations must be safe for concurrent use
+//
+type LedgerStore interface {
+	Load(ctx context.Context) ([]UsageRecord, error)
+	Save(ctx context.Context, records []UsageRecord) error
+}
+
+// FileLedgerStore is a LedgerStore that keeps the records in a JSON file
+type FileLedgerStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileLedgerStore creates a FileLedgerStore that uses the file at path,
+// which is created when the records are first saved
+func NewFileLedgerStore(path string) *FileLedgerStore {
+	return &FileLedgerStore{path: path}
+}
+
+// Load reads the records, returning none if the file does not exist
+func (store *FileLedgerStore) Load(ctx context.Context) ([]UsageRecord, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	data, err := ioutil.ReadFile(store.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []UsageRecord
+	if err = json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("bingSpellCheck: %s: %v", store.path, err)
+	}
+
+	return records, nil
+}
+
+// Save replaces the file with records
+//
+//  Notes
+//    The records are written to a temporary file that is renamed over the
+//    file, so a crash never leaves a partial file
+//
+func (store *FileLedgerStore) Save(ctx context.Context, records []UsageRecord) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(store.path)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, filepath.Base(store.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), store.path)
+}
+
+// Estimate is the expected cost of a check
+//
+//  Fields
+//    Transactions - The number of requests the check will make
+//    Cost         - Transactions priced at Ledger.PricePer1000
+//
+type Estimate struct {
+	Transactions int64
+	Cost         float64
+}
+
+// EstimateTransactions returns the number of requests needed to check
+// texts, each split into chunks of at most maxLength characters (as
+// ChunkedChecker does)
+//
+//  Notes
+//    When maxLength is 0, MaxPostTextLength is used. Empty texts are not
+//    counted. Decorators can change the actual number: CachedChecker makes
+//    fewer requests, MultilingualChecker may make more and only successful
+//    responses are billed.
+//
+func EstimateTransactions(texts []string, maxLength int) int64 {
+	if maxLength <= 0 {
+		maxLength = MaxPostTextLength
+	}
+
+	var transactions int64
+	for _, text := range texts {
+		if length := utf8.RuneCountInString(text); length > 0 {
+			transactions += int64((length + maxLength - 1) / maxLength)
+		}
+	}
+
+	return transactions
+}
+
+// DefaultLedgerSaveInterval is the default time a Ledger waits after a
+// change before saving its records
+const DefaultLedgerSaveInterval = 5 * time.Second
+
+// Ledger counts the transactions (billable requests) made with each key,
+// by each caller tag, on each day, and enforces a monthly budget
+//
+//  Notes
+//    When SoftBudget is reached, OnSoftBudget is called (once a month).
+//    Once HardBudget is reached, Allow and Reserve fail with
+//    *BudgetExceededError. A budget of 0 means no budget. Transactions
+//    reserved with Reserve count against the budget until they are
+//    committed or released, so concurrent requests cannot exceed it.
+//
+//    Records are saved to the store in the background, at most once per
+//    save interval (see WithSaveInterval), and by Flush and Close; see
+//    Client.WithLedger
+//
+type Ledger struct {
+	// SoftBudget and HardBudget are monthly transaction budgets
+	SoftBudget int64
+	HardBudget int64
+
+	// OnSoftBudget is called when the soft budget of a month is reached
+	OnSoftBudget func(month string, used, budget int64)
+
+	// PricePer1000 is the price of 1000 transactions, used by Estimate
+	PricePer1000 float64
+
+	store        LedgerStore
+	now          func() time.Time
+	saveInterval time.Duration
+	saveMu       sync.Mutex
+
+	mu        sync.Mutex
+	records   map[UsageRecord]int64
+	reserved  map[string]int64
+	warned    string
+	dirty     bool
+	saveTimer *time.Timer
+}
+
+// NewLedger creates a Ledger that persists its records to store, loading
+// any existing records
+//
+//  Notes
+//    store may be nil, in which case the records are only kept in memory.
+//    Close the ledger when done with it, so the last changes are saved
+//
+func NewLedger(ctx context.Context, store LedgerStore) (*Ledger, error) {
+	ledger := &Ledger{
+		store:        store,
+		now:          time.Now,
+		saveInterval: DefaultLedgerSaveInterval,
+		records:      map[UsageRecord]int64{},
+		reserved:     map[string]int64{},
+	}
+
+	if store != nil {
+		records, err := store.Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			ledger.records[record.key()] += record.Transactions
+		}
+	}
+
+	return ledger, nil
+}
+
+// key returns the record without its count, for use in map keys
+func (record UsageRecord) key() UsageRecord {
+	record.Transactions = 0
+	return record
+}
+
+// WithSaveInterval sets the time the ledger waits after a change before
+// saving its records
+//
+//  Notes
+//    An interval of 0 saves the records synchronously on each Record
+//
+func (ledger *Ledger) WithSaveInterval(interval time.Duration) *Ledger {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	ledger.saveInterval = interval
+	return ledger
+}
+
+// Record adds transactions made with key (a subscription key) by tag
+//
+//  Notes
+//    The records are saved later (see WithSaveInterval), so an error is
+//    only returned when the save interval is 0 and the save fails
+//
+func (ledger *Ledger) Record(ctx context.Context, key, tag string, transactions int64) error {
+	return ledger.record(ctx, key, tag, transactions, nil)
+}
+
+// record adds transactions and releases reservation (if any) atomically,
+// then saves or schedules a save of the records
+func (ledger *Ledger) record(ctx context.Context, key, tag string, transactions int64, reservation *Reservation) error {
+	now := ledger.now().UTC()
+	month := now.Format(UsageMonthLayout)
+
+	ledger.mu.Lock()
+	if reservation != nil {
+		reservation.release()
+	}
+	ledger.records[UsageRecord{Day: now.Format(UsageDayLayout), KeyID: KeyID(key), Tag: tag}] += transactions
+	used := ledger.monthUsage(month)
+	warn := ledger.SoftBudget > 0 && used >= ledger.SoftBudget && ledger.warned != month
+	if warn {
+		ledger.warned = month
+	}
+
+	ledger.dirty = true
+	saveNow := ledger.store != nil && ledger.saveInterval <= 0
+	if ledger.store != nil && !saveNow && ledger.saveTimer == nil {
+		ledger.saveTimer = time.AfterFunc(ledger.saveInterval, ledger.saveInBackground)
+	}
+	ledger.mu.Unlock()
+
+	if warn && ledger.OnSoftBudget != nil {
+		ledger.OnSoftBudget(month, used, ledger.SoftBudget)
+	}
+
+	if saveNow {
+		return ledger.Flush(ctx)
+	}
+
+	return nil
+}
+
+// saveInBackground saves the records when the save timer fires; a failed
+// save is retried after the next change, or by Flush or Close
+func (ledger *Ledger) saveInBackground() {
+	ledger.mu.Lock()
+	ledger.saveTimer = nil
+	ledger.mu.Unlock()
+
+	_ = ledger.Flush(context.Background())
+}
+
+// Flush saves the records if they changed since they were last saved
+func (ledger *Ledger) Flush(ctx context.Context) error {
+	if ledger.store == nil {
+		return nil
+	}
+
+	// snapshot under saveMu so an older snapshot is never saved last
+	ledger.saveMu.Lock()
+	defer ledger.saveMu.Unlock()
+
+	ledger.mu.Lock()
+	if !ledger.dirty {
+		ledger.mu.Unlock()
+		return nil
+	}
+	records := ledger.snapshot()
+	ledger.dirty = false
+	ledger.mu.Unlock()
+
+	err := ledger.store.Save(ctx, records)
+	if err != nil {
+		ledger.mu.Lock()
+		ledger.dirty = true
+		ledger.mu.Unlock()
+	}
+
+	return err
+}
+
+// Close cancels any pending background save and saves the records
+func (ledger *Ledger) Close() error {
+	ledger.mu.Lock()
+	if ledger.saveTimer != nil {
+		ledger.saveTimer.Stop()
+		ledger.saveTimer = nil
+	}
+	ledger.mu.Unlock()
+
+	return ledger.Flush(context.Background())
+}
+
+// Allow returns *BudgetExceededError if n more transactions this month
+// would exceed the hard budget
+//
+//  Notes
+//    Allow does not hold the transactions for the caller, so concurrent
+//    callers can each be allowed the last of the budget; use Reserve
+//    before making requests
+//
+func (ledger *Ledger) Allow(n int64) error {
+	if ledger.HardBudget <= 0 {
+		return nil
+	}
+
+	month := ledger.now().UTC().Format(UsageMonthLayout)
+
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	return ledger.allow(month, n)
+}
+
+// allow checks n more transactions in month against the hard budget, and
+// must be called with mu held
+func (ledger *Ledger) allow(month string, n int64) error {
+	if ledger.HardBudget <= 0 {
+		return nil
+	}
+
+	used := ledger.monthUsage(month) + ledger.reserved[month]
+	if used+n > ledger.HardBudget {
+		return &BudgetExceededError{Month: month, Used: used, Needed: n, Budget: ledger.HardBudget}
+	}
+
+	return nil
+}
+
+// Reservation holds transactions against the hard budget of a Ledger until
+// they are committed or released
+type Reservation struct {
+	ledger *Ledger
+	month  string
+	n      int64
+	done   bool
+}
+
+// Reserve holds n transactions this month, or returns
+// *BudgetExceededError if they would exceed the hard budget (counting the
+// transactions held by other reservations)
+//
+//  Notes
+//    Each Reservation must be committed or released; releasing it after it
+//    is committed does nothing, so Release can be deferred
+//
+func (ledger *Ledger) Reserve(n int64) (*Reservation, error) {
+	month := ledger.now().UTC().Format(UsageMonthLayout)
+
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	if err := ledger.allow(month, n); err != nil {
+		return nil, err
+	}
+
+	ledger.reserved[month] += n
+	return &Reservation{ledger: ledger, month: month, n: n}, nil
+}
+
+// Commit releases the reservation and records the transactions that were
+// made (see Ledger.Record), in one step so the budget cannot be overtaken
+// in between
+func (reservation *Reservation) Commit(ctx context.Context, key, tag string, transactions int64) error {
+	return reservation.ledger.record(ctx, key, tag, transactions, reservation)
+}
+
+// Release releases the transactions that were not made
+func (reservation *Reservation) Release() {
+	reservation.ledger.mu.Lock()
+	defer reservation.ledger.mu.Unlock()
+
+	reservation.release()
+}
+
+// release releases the reservation once, and must be called with the
+// ledger's mu held
+func (reservation *Reservation) release() {
+	if reservation.done {
+		return
+	}
+
+	reservation.done = true
+	reservation.ledger.reserved[reservation.month] -= reservation.n
+	if reservation.ledger.reserved[reservation.month] <= 0 {
+		delete(reservation.ledger.reserved, reservation.month)
+	}
+}
+
+// Estimate returns the expected number and cost of the transactions
+// needed to check texts, e.g. the texts of a batch or a single document
+func (ledger *Ledger) Estimate(texts ...string) Estimate {
+	transactions := EstimateTransactions(texts, 0)
+	return Estimate{Transactions: transactions, Cost: float64(transactions) * ledger.PricePer1000 / 1000}
+}
+
+// AllowEstimate returns *BudgetExceededError if running a check with
+// estimate would exceed the hard budget (see Allow)
+func (ledger *Ledger) AllowEstimate(estimate Estimate) error {
+	return ledger.Allow(estimate.Transactions)
+}
+
+// DayUsage returns the usage on the UTC day of t
+func (ledger *Ledger) DayUsage(t time.Time) Usage {
+	day := t.UTC().Format(UsageDayLayout)
+	return ledger.usage(func(record UsageRecord) bool { return record.Day == day })
+}
+
+// MonthUsage returns the usage in the UTC month of t
+func (ledger *Ledger) MonthUsage(t time.Time) Usage {
+	month := t.UTC().Format(UsageMonthLayout)
+	return ledger.usage(func(record UsageRecord) bool { return strings.HasPrefix(record.Day, month) })
+}
+
+// Records returns the records, ordered by day, key ID and tag
+func (ledger *Ledger) Records() []UsageRecord {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	return ledger.snapshot()
+}
+
+func (ledger *Ledger) usage(match func(record UsageRecord) bool) Usage {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	usage := Usage{ByKey: map[string]int64{}, ByTag: map[string]int64{}}
+	for record, transactions := range ledger.records {
+		if match(record) {
+			usage.Transactions += transactions
+			usage.ByKey[record.KeyID] += transactions
+			usage.ByTag[record.Tag] += transactions
+		}
+	}
+
+	return usage
+}
+
+// monthUsage returns the transactions in month, and must be called with mu
+// held
+func (ledger *Ledger) monthUsage(month string) int64 {
+	var used int64
+	for record, transactions := range ledger.records {
+		if strings.HasPrefix(record.Day, month) {
+			used += transactions
+		}
+	}
+
+	return used
+}
+
+// snapshot returns the records in order, and must be called with mu held
+func (ledger *Ledger) snapshot() []UsageRecord {
+	records := make([]UsageRecord, 0, len(ledger.records))
+	for record, transactions := range ledger.records {
+		record.Transactions = transactions
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.KeyID != b.KeyID {
+			return a.KeyID < b.KeyID
+		}
+		return a.Tag < b.Tag
+	})
+
+	return records
+}
+
+// WithLedger records the transactions the client makes in ledger, and
+// refuses to send requests once its hard budget is reached
+//
+//  Notes
+//    Each request reserves a transaction before it is sent, and commits it
+//    if it is billed. Over budget, Check fails with *BudgetExceededError,
+//    or fails over to the fallback checker if there is one. Errors saving
+//    the ledger do not fail the check.
+//
+func (client *Client) WithLedger(ledger *Ledger) *Client {
+	client.ledger = ledger
+	return client
+}
+
+// recordTransaction commits the reservation of a successful request, which
+// is billed; the reservation of any other request is released by send
+func recordTransaction(ctx context.Context, reservation *Reservation, headers *SpellCheckHeaders, tag string, scr *SpellCheckResponse, err error) {
+	if reservation == nil || err != nil || scr.IsErrorResponse() {
+		return
+	}
+
+	_ = reservation.Commit(ctx, headers.Headers.Get(SubscriptionKeyHeader), tag, 1)
+}
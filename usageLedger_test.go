@@ -0,0 +1,240 @@
+package bingSpellCheck
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryLedgerStore keeps the records in memory and counts the saves
+type memoryLedgerStore struct {
+	mu      sync.Mutex
+	records []UsageRecord
+	saves   int
+	err     error
+}
+
+func (store *memoryLedgerStore) Load(ctx context.Context) ([]UsageRecord, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.records, nil
+}
+
+func (store *memoryLedgerStore) Save(ctx context.Context, records []UsageRecord) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.err != nil {
+		return store.err
+	}
+	store.records = records
+	store.saves++
+	return nil
+}
+
+func (store *memoryLedgerStore) count() (int, int64) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var transactions int64
+	for _, record := range store.records {
+		transactions += record.Transactions
+	}
+	return store.saves, transactions
+}
+
+func TestLedgerReserve(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  int64
+		used    int64
+		held    []int64
+		n       int64
+		wantErr bool
+	}{
+		{"no budget", 0, 100, []int64{100}, 100, false},
+		{"within budget", 10, 3, []int64{2}, 5, false},
+		{"exceeds budget", 10, 3, nil, 8, true},
+		{"reservations count", 10, 3, []int64{2, 2}, 4, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger, err := NewLedger(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ledger.HardBudget = tt.budget
+			if err = ledger.Record(context.Background(), "key", "", tt.used); err != nil {
+				t.Fatal(err)
+			}
+			for _, n := range tt.held {
+				if _, err = ledger.Reserve(n); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			reservation, err := ledger.Reserve(tt.n)
+			var budgetErr *BudgetExceededError
+			if tt.wantErr != errors.As(err, &budgetErr) {
+				t.Fatalf("Reserve(%d) error = %v, want error %v", tt.n, err, tt.wantErr)
+			}
+			if reservation != nil {
+				reservation.Release()
+			}
+		})
+	}
+}
+
+func TestLedgerReservationCommit(t *testing.T) {
+	ledger, err := NewLedger(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ledger.HardBudget = 10
+
+	reservation, err := ledger.Reserve(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ledger.Reserve(5); err == nil {
+		t.Fatal("reserved more than the budget")
+	}
+
+	// only 2 of the 6 transactions were made
+	if err = reservation.Commit(context.Background(), "key", "tag", 2); err != nil {
+		t.Fatal(err)
+	}
+	reservation.Release()
+
+	if used := ledger.MonthUsage(time.Now()).Transactions; used != 2 {
+		t.Errorf("used %d transactions, want 2", used)
+	}
+	if err = ledger.Allow(8); err != nil {
+		t.Errorf("Allow(8) = %v after the reservation is committed", err)
+	}
+	if err = ledger.Allow(9); err == nil {
+		t.Error("Allow(9) succeeded over the budget")
+	}
+}
+
+func TestLedgerSave(t *testing.T) {
+	tests := []struct {
+		name           string
+		interval       time.Duration
+		wantSaves      int
+		wantSavesClose int
+	}{
+		{"synchronous", 0, 3, 3},
+		{"debounced", time.Hour, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryLedgerStore{}
+			ledger, err := NewLedger(context.Background(), store)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ledger.WithSaveInterval(tt.interval)
+
+			for i := 0; i < 3; i++ {
+				if err = ledger.Record(context.Background(), "key", "tag", 1); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if saves, _ := store.count(); saves != tt.wantSaves {
+				t.Errorf("saved %d times before Close, want %d", saves, tt.wantSaves)
+			}
+
+			if err = ledger.Close(); err != nil {
+				t.Fatal(err)
+			}
+			saves, transactions := store.count()
+			if saves != tt.wantSavesClose || transactions != 3 {
+				t.Errorf("saved %d times with %d transactions after Close, want %d and 3", saves, transactions, tt.wantSavesClose)
+			}
+
+			// nothing changed since the last save
+			if err = ledger.Flush(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if again, _ := store.count(); again != saves {
+				t.Errorf("Flush saved unchanged records")
+			}
+		})
+	}
+}
+
+func TestLedgerSaveInBackground(t *testing.T) {
+	store := &memoryLedgerStore{}
+	ledger, err := NewLedger(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ledger.WithSaveInterval(10 * time.Millisecond)
+	defer ledger.Close()
+
+	if err = ledger.Record(context.Background(), "key", "tag", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if saves, transactions := store.count(); saves == 1 && transactions == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the records were not saved in the background")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestLedgerFlushRetries(t *testing.T) {
+	store := &memoryLedgerStore{err: errors.New("disk full")}
+	ledger, err := NewLedger(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ledger.WithSaveInterval(time.Hour)
+
+	if err = ledger.Record(context.Background(), "key", "tag", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err = ledger.Flush(context.Background()); err == nil {
+		t.Fatal("Flush did not return the store's error")
+	}
+
+	store.mu.Lock()
+	store.err = nil
+	store.mu.Unlock()
+
+	if err = ledger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if saves, transactions := store.count(); saves != 1 || transactions != 1 {
+		t.Errorf("saved %d times with %d transactions, want 1 and 1", saves, transactions)
+	}
+}
+
+func TestFileLedgerStore(t *testing.T) {
+	store := NewFileLedgerStore(filepath.Join(t.TempDir(), "usage", "ledger.json"))
+
+	records, err := store.Load(context.Background())
+	if err != nil || len(records) != 0 {
+		t.Fatalf("Load of a missing file = %v, %v", records, err)
+	}
+
+	want := []UsageRecord{{Day: "2026-10-19", KeyID: KeyID("key"), Tag: "tag", Transactions: 3}}
+	if err = store.Save(context.Background(), want); err != nil {
+		t.Fatal(err)
+	}
+	if records, err = store.Load(context.Background()); err != nil || len(records) != 1 || records[0] != want[0] {
+		t.Errorf("Load = %v, %v; want %v", records, err, want)
+	}
+}
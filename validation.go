@@ -0,0 +1,218 @@
+package bingSpellCheck
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	marketPattern      = regexp.MustCompile(`^[A-Za-z]{2,3}-[A-Za-z]{2}$`)
+	countryPattern     = regexp.MustCompile(`^[A-Za-z]{2}$`)
+	languagePattern    = regexp.MustCompile(`^[A-Za-z]{2}(-[A-Za-z]{2,4})?$`)
+	languageTagPattern = regexp.MustCompile(`^(\*|[A-Za-z]{1,8}(-[A-Za-z0-9]{1,8})*)(\s*;\s*q=(0(\.\d{0,3})?|1(\.0{0,3})?))?$`)
+)
+
+// ValidationError describes a single invalid parameter or header
+type ValidationError struct {
+	Parameter string
+	Value     string
+	Message   string
+}
+
+func (err *ValidationError) Error() string {
+	if err.Value == "" {
+		return fmt.Sprintf("%s: %s", err.Parameter, err.Message)
+	}
+
+	return fmt.Sprintf("%s=%q: %s", err.Parameter, err.Value, err.Message)
+}
+
+// ValidationErrors is every problem found by a validation
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("bingSpellCheck: %d invalid parameter(s): %s", len(errs), strings.Join(messages, "; "))
+}
+
+// add records a problem
+func (errs *ValidationErrors) add(parameter, value, format string, args ...interface{}) {
+	*errs = append(*errs, &ValidationError{
+		Parameter: parameter,
+		Value:     value,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// err returns nil when there are no problems, so a nil ValidationErrors is
+// never returned as a non-nil error
+func (errs ValidationErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// Validate checks the parameters for problems the Bing Spell Check API
+// would reject, and returns ValidationErrors listing all of them
+//
+//  Notes
+//    ProofMode market support is checked when ModeParam is ProofMode or is
+//    not set, since ProofMode is the default
+//
+func (scp *SpellCheckParams) Validate() error {
+	var errs ValidationErrors
+	scp.validate(&errs)
+	return errs.err()
+}
+
+func (scp *SpellCheckParams) validate(errs *ValidationErrors) {
+	text := scp.Values.Get(TextParam)
+	if text == "" {
+		errs.add(TextParam, "", "text is required")
+	}
+
+	if length := scp.TotalTextLength(); length > MaxPostTextLength {
+		errs.add(TextParam, "", "text and context length %d exceeds the maximum of %d characters", length, MaxPostTextLength)
+	}
+
+	mode := scp.Values.Get(ModeParam)
+	if mode != "" && mode != ProofMode && mode != SpellMode {
+		errs.add(ModeParam, mode, "must be %q or %q", ProofMode, SpellMode)
+	}
+
+	if action := scp.Values.Get(ActionTypeParam); action != "" && action != EditActionType && action != LoadActionType {
+		errs.add(ActionTypeParam, action, "must be %q or %q", EditActionType, LoadActionType)
+	}
+
+	market := scp.Values.Get(MarketParam)
+	if market != "" {
+		if !marketPattern.MatchString(market) {
+			errs.add(MarketParam, market, "must be of the form <language code>-<country code>")
+		} else if !MarketCode(market).IsSupported() {
+			errs.add(MarketParam, market, "is not a supported market")
+		} else if (mode == "" || mode == ProofMode) && !MarketCode(market).SupportsMode(ProofMode) {
+			errs.add(MarketParam, market, "%s mode is only supported in the %s markets", ProofMode, joinMarkets(ProofMarkets()))
+		}
+	}
+
+	country := scp.Values.Get(CountryCodeParam)
+	if country != "" {
+		if !countryPattern.MatchString(country) {
+			errs.add(CountryCodeParam, country, "must be a 2 character country code")
+		} else if !CountryCode(country).IsSupported() {
+			errs.add(CountryCodeParam, country, "is not a supported country")
+		}
+	}
+
+	if market != "" && country != "" {
+		errs.add(CountryCodeParam, country, "%s and %s are mutually exclusive; do not specify both", CountryCodeParam, MarketParam)
+	}
+
+	if lang := scp.Values.Get(LanguageParam); lang != "" && !languagePattern.MatchString(lang) {
+		errs.add(LanguageParam, lang, "must be an ISO 639-1 2-letter language code")
+	}
+
+	params := make([]string, 0, len(scp.Values))
+	for param := range scp.Values {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	for _, param := range params {
+		if n := len(scp.Values[param]); n > 1 {
+			errs.add(param, "", "specified %d times", n)
+		}
+	}
+}
+
+// Validate checks the headers for problems the Bing Spell Check API would
+// reject, and returns ValidationErrors listing all of them
+func (sch *SpellCheckHeaders) Validate() error {
+	var errs ValidationErrors
+	sch.validate(&errs, true)
+	return errs.err()
+}
+
+func (sch *SpellCheckHeaders) validate(errs *ValidationErrors, requireKey bool) {
+	if requireKey && sch.Headers.Get(SubscriptionKeyHeader) == "" {
+		errs.add(SubscriptionKeyHeader, "", "subscription key is required")
+	}
+
+	if accept := sch.Headers.Get(AcceptHeader); accept != "" {
+		mediaType, _, err := mime.ParseMediaType(accept)
+		if err != nil || (mediaType != AcceptApplicationJSON && mediaType != AcceptApplicationLinkedJSON) {
+			errs.add(AcceptHeader, accept, "must be %q or %q", AcceptApplicationJSON, AcceptApplicationLinkedJSON)
+		}
+	}
+
+	if languages := sch.Headers.Get(AcceptLanguageHeader); languages != "" {
+		for _, tag := range strings.Split(languages, ",") {
+			if !languageTagPattern.MatchString(strings.TrimSpace(tag)) {
+				errs.add(AcceptLanguageHeader, languages, "%q is not a valid language range", strings.TrimSpace(tag))
+			}
+		}
+	}
+
+	if ip := sch.Headers.Get(ClientIPHeader); ip != "" && net.ParseIP(ip) == nil {
+		errs.add(ClientIPHeader, ip, "must be an IPv4 or IPv6 address")
+	}
+
+	if pragma := sch.Headers.Get(PragmaHeader); pragma != "" && pragma != PragmaNoCache {
+		errs.add(PragmaHeader, pragma, "must be %q", PragmaNoCache)
+	}
+
+	if _, err := sch.Location(); err != nil {
+		*errs = append(*errs, err.(ValidationErrors)...)
+	}
+}
+
+// ValidateRequest checks params and headers, both individually and against
+// each other, and returns ValidationErrors listing every problem
+//
+//  Notes
+//    In addition to Validate on each, this reports LanguageParam being used
+//    with the Accept-Language header (they are mutually exclusive), and
+//    CountryCodeParam being used without the Accept-Language header
+//
+func ValidateRequest(params *SpellCheckParams, headers *SpellCheckHeaders) error {
+	return validateRequest(params, headers, true)
+}
+
+func validateRequest(params *SpellCheckParams, headers *SpellCheckHeaders, requireKey bool) error {
+	var errs ValidationErrors
+
+	params.validate(&errs)
+	headers.validate(&errs, requireKey)
+
+	acceptLanguage := headers.Headers.Get(AcceptLanguageHeader)
+
+	if lang := params.Values.Get(LanguageParam); lang != "" && acceptLanguage != "" {
+		errs.add(LanguageParam, lang, "%s and the %s header are mutually exclusive; do not specify both",
+			LanguageParam, AcceptLanguageHeader)
+	}
+
+	if cc := params.Values.Get(CountryCodeParam); cc != "" && acceptLanguage == "" {
+		errs.add(CountryCodeParam, cc, "requires the %s header", AcceptLanguageHeader)
+	}
+
+	return errs.err()
+}
+
+func joinMarkets(markets []MarketCode) string {
+	names := make([]string, len(markets))
+	for i, market := range markets {
+		names[i] = string(market)
+	}
+
+	return strings.Join(names, ", ")
+}
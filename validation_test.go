@@ -0,0 +1,160 @@
+package bingSpellCheck
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// paramsOf returns params with the given name and value pairs
+func paramsOf(pairs ...string) *SpellCheckParams {
+	params := NewSpellCheckParams()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		params.SetParam(pairs[i], pairs[i+1])
+	}
+	return params
+}
+
+// invalid returns the parameters (or headers) reported by err, which must
+// be nil or ValidationErrors
+func invalid(t *testing.T, err error) []string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("%T is not ValidationErrors: %v", err, err)
+	}
+
+	var names []string
+	for _, e := range errs {
+		names = append(names, e.Parameter)
+	}
+	return names
+}
+
+func TestSpellCheckParamsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *SpellCheckParams
+		want   []string
+	}{
+		{"text only", paramsOf(TextParam, "text"), nil},
+		{"no text", paramsOf(MarketParam, "en-US"), []string{TextParam}},
+		{"too long", paramsOf(TextParam, strings.Repeat("a", MaxPostTextLength-1), PreContextTextParam, "ab"), []string{TextParam}},
+		{"at the limit", paramsOf(TextParam, strings.Repeat("é", MaxPostTextLength)), nil},
+		{"unknown mode", paramsOf(TextParam, "text", ModeParam, "grammar"), []string{ModeParam}},
+		{"unknown action", paramsOf(TextParam, "text", ActionTypeParam, "save"), []string{ActionTypeParam}},
+		{"load action", paramsOf(TextParam, "text", ActionTypeParam, LoadActionType), nil},
+		{"malformed market", paramsOf(TextParam, "text", MarketParam, "english"), []string{MarketParam}},
+		{"unsupported market", paramsOf(TextParam, "text", MarketParam, "de-LU", ModeParam, SpellMode), []string{MarketParam}},
+		{"proof market", paramsOf(TextParam, "text", MarketParam, "es-ES", ModeParam, ProofMode), nil},
+		{"proof mode in a spell market", paramsOf(TextParam, "text", MarketParam, "es-US", ModeParam, ProofMode), []string{MarketParam}},
+		{"default mode in a spell market", paramsOf(TextParam, "text", MarketParam, "en-GB"), []string{MarketParam}},
+		{"default mode in a proof market", paramsOf(TextParam, "text", MarketParam, "pt-BR"), nil},
+		{"spell mode in a spell market", paramsOf(TextParam, "text", MarketParam, "en-GB", ModeParam, SpellMode), nil},
+		{"malformed country", paramsOf(TextParam, "text", CountryCodeParam, "USA"), []string{CountryCodeParam}},
+		{"unsupported country", paramsOf(TextParam, "text", CountryCodeParam, "LU"), []string{CountryCodeParam}},
+		{"market and country", paramsOf(TextParam, "text", MarketParam, "en-US", CountryCodeParam, "US"), []string{CountryCodeParam}},
+		{"language", paramsOf(TextParam, "text", LanguageParam, "en"), nil},
+		{"malformed language", paramsOf(TextParam, "text", LanguageParam, "english"), []string{LanguageParam}},
+		{"every problem", paramsOf(ModeParam, "grammar", MarketParam, "english", LanguageParam, "e"), []string{TextParam, ModeParam, MarketParam, LanguageParam}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invalid(t, tt.params.Validate()); !equalStrings(got, tt.want) {
+				t.Errorf("invalid %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpellCheckParamsValidateRepeated(t *testing.T) {
+	params := paramsOf(TextParam, "text")
+	params.Values.Add(MarketParam, "en-US")
+	params.Values.Add(MarketParam, "pt-BR")
+
+	err := params.Validate()
+	if got := invalid(t, err); !equalStrings(got, []string{MarketParam}) {
+		t.Errorf("invalid %q", got)
+	}
+	if !strings.Contains(err.Error(), "specified 2 times") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestSpellCheckHeadersValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		pairs []string
+		want  []string
+	}{
+		{"defaults", "key", nil, nil},
+		{"no key", "", nil, []string{SubscriptionKeyHeader}},
+		{"json-ld", "key", []string{AcceptHeader, AcceptApplicationLinkedJSON}, nil},
+		{"unknown accept", "key", []string{AcceptHeader, "text/html"}, []string{AcceptHeader}},
+		{"accept languages", "key", []string{AcceptLanguageHeader, "en-US, fr;q=0.8, *;q=0.1"}, nil},
+		{"invalid accept languages", "key", []string{AcceptLanguageHeader, "en-US, fr;q=2, !"}, []string{AcceptLanguageHeader, AcceptLanguageHeader}},
+		{"client ip", "key", []string{ClientIPHeader, "2001:db8::1"}, nil},
+		{"invalid client ip", "key", []string{ClientIPHeader, "localhost"}, []string{ClientIPHeader}},
+		{"no-cache", "key", []string{PragmaHeader, PragmaNoCache}, nil},
+		{"invalid pragma", "key", []string{PragmaHeader, "cache"}, []string{PragmaHeader}},
+		{"location", "key", []string{SearchLocationHeader, "lat:1;long:2;re:3"}, nil},
+		{"invalid location", "key", []string{SearchLocationHeader, "lat:100;long:200;re:3"}, []string{SearchLocationHeader, SearchLocationHeader}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := NewSpellCheckHeaders(tt.key)
+			for i := 0; i+1 < len(tt.pairs); i += 2 {
+				headers.SetHeader(tt.pairs[i], tt.pairs[i+1])
+			}
+
+			if got := invalid(t, headers.Validate()); !equalStrings(got, tt.want) {
+				t.Errorf("invalid %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		params         *SpellCheckParams
+		acceptLanguage string
+		want           []string
+	}{
+		{"valid", paramsOf(TextParam, "text", MarketParam, "en-US"), "en-US", nil},
+		{"language and accept-language", paramsOf(TextParam, "text", LanguageParam, "en"), "en-US", []string{LanguageParam}},
+		{"country without accept-language", paramsOf(TextParam, "text", CountryCodeParam, "US"), "", []string{CountryCodeParam}},
+		{"country with accept-language", paramsOf(TextParam, "text", CountryCodeParam, "US"), "en-US", nil},
+		{"params and headers", paramsOf(MarketParam, "en-US"), "en-US;q=9", []string{TextParam, AcceptLanguageHeader}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := NewSpellCheckHeaders("key").WithAcceptLanguages(tt.acceptLanguage)
+
+			if got := invalid(t, ValidateRequest(tt.params, headers)); !equalStrings(got, tt.want) {
+				t.Errorf("invalid %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationErrorMessages(t *testing.T) {
+	err := paramsOf(TextParam, "text", MarketParam, "en-GB").Validate()
+	want := `bingSpellCheck: 1 invalid parameter(s): mkt="en-GB": proof mode is only supported in the pt-BR, es-ES, en-US markets`
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v, want %s", err, want)
+	}
+
+	if err := (ValidationErrors{}).err(); err != nil {
+		t.Errorf("empty ValidationErrors err() = %v", err)
+	}
+}